@@ -77,13 +77,19 @@ func PAddHinters(pctx context.Context) (context.Context, error) {
 }
 
 type OperationFlags struct {
-	Privatekey PrivatekeyFlag `arg:"" name:"privatekey" help:"privatekey to sign operation" required:"true"`
+	Privatekey PrivatekeyFlag `arg:"" name:"privatekey" help:"privatekey to sign operation; \"-\" to load from --keystore, --signer or stdin" required:"true"` //nolint:lll //...
+	Keystore   string         `name:"keystore" help:"keystore file to sign operation" type:"existingfile"`
+	Signer     string         `name:"signer" help:"local socket of external signer to sign operation"`
 	Token      string         `help:"token for operation" optional:""`
 	NetworkID  NetworkIDFlag  `name:"network-id" help:"network-id" required:"true" default:"${network_id}"`
 	Pretty     bool           `name:"pretty" help:"pretty format"`
 }
 
 func (op *OperationFlags) IsValid([]byte) error {
+	if err := op.Privatekey.Load(op.Keystore, op.Signer); err != nil {
+		return err
+	}
+
 	if len(op.Token) < 1 {
 		op.Token = localtime.Now().UTC().String()
 	}
@@ -85,12 +85,32 @@ func (cmd *CreateAccountCommand) parseFlags() error {
 }
 
 func (cmd *CreateAccountCommand) createOperation() (base.Operation, error) { // nolint:dupl}
+	fact, err := newCreateAccountFact(cmd.Token, cmd.sender, cmd.keys, cmd.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := currency.NewCreateAccount(fact)
+	if err != nil {
+		return nil, errors.Wrap(err, "create create-account operation")
+	}
+	err = op.HashSign(cmd.Privatekey, cmd.NetworkID.NetworkID())
+	if err != nil {
+		return nil, errors.Wrap(err, "create create-account operation")
+	}
+
+	return op, nil
+}
+
+func newCreateAccountFact(
+	token string, sender base.Address, keys types.AccountKeys, amount CurrencyAmountFlag,
+) (currency.CreateAccountFact, error) {
 	var items []currency.CreateAccountItem
 
 	ams := make([]types.Amount, 1)
-	am := types.NewAmount(cmd.Amount.Big, cmd.Amount.CID)
+	am := types.NewAmount(amount.Big, amount.CID)
 	if err := am.IsValid(nil); err != nil {
-		return nil, err
+		return currency.CreateAccountFact{}, err
 	}
 
 	ams[0] = am
@@ -101,22 +121,11 @@ func (cmd *CreateAccountCommand) createOperation() (base.Operation, error) { //
 	//	addrType = types.EthAddressHint.Type()
 	//}
 
-	item := currency.NewCreateAccountItemMultiAmounts(cmd.keys, ams)
+	item := currency.NewCreateAccountItemMultiAmounts(keys, ams)
 	if err := item.IsValid(nil); err != nil {
-		return nil, err
+		return currency.CreateAccountFact{}, err
 	}
 	items = append(items, item)
 
-	fact := currency.NewCreateAccountFact([]byte(cmd.Token), cmd.sender, items)
-
-	op, err := currency.NewCreateAccount(fact)
-	if err != nil {
-		return nil, errors.Wrap(err, "create create-account operation")
-	}
-	err = op.HashSign(cmd.Privatekey, cmd.NetworkID.NetworkID())
-	if err != nil {
-		return nil, errors.Wrap(err, "create create-account operation")
-	}
-
-	return op, nil
+	return currency.NewCreateAccountFact([]byte(token), sender, items), nil
 }
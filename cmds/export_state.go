@@ -0,0 +1,194 @@
+package cmds
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameExportState = ps.Name("export-state")
+
+// ExportStateCommand dumps the states of snapshot at the height into the state
+// snapshot file. The states are collected by replaying the states of blocks
+// from genesis.
+type ExportStateCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	Snapshot        string            `arg:"" name:"snapshot" help:"state snapshot file" type:"path"`
+	Height          launch.HeightFlag `name:"height" help:"export the state at this height; default is last"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *ExportStateCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Str("snapshot", cmd.Snapshot).
+		Interface("height", cmd.Height).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := ps.NewPS("cmd-export-state")
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal).
+		AddOK(PNameExportState, cmd.pExportState, nil, launch.PNameStorage)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(launch.PNameLoadFromDatabase, launch.PLoadFromDatabase).
+		PostAddOK(launch.PNameCheckBlocksOfStorage, launch.PCheckBlocksOfStorage).
+		PostAddOK(launch.PNamePatchBlockItemReaders, launch.PPatchBlockItemReaders)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *ExportStateCommand) pExportState(pctx context.Context) (context.Context, error) {
+	e := util.StringError("export state")
+
+	var encs *encoder.Encoders
+	var design launch.NodeDesign
+	var db isaac.Database
+	var newReaders func(context.Context, string, *isaac.BlockItemReadersArgs) (*isaac.BlockItemReaders, error)
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.EncodersContextKey, &encs,
+		launch.DesignContextKey, &design,
+		launch.CenterDatabaseContextKey, &db,
+		launch.NewBlockItemReadersFuncContextKey, &newReaders,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	height, err := lastHeightFromFlag(db, cmd.Height)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	readers, err := newReaders(pctx, launch.LocalFSDataDirectory(design.Storage.Base), nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	states := map[string]base.State{}
+
+	if err := replayStates(readers, height, cmd.log, func(st base.State) error {
+		if IsStateSnapshotKey(st.Key()) {
+			states[st.Key()] = st
+		}
+
+		return nil
+	}); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	sts := make([]base.State, 0, len(states))
+	for k := range states {
+		sts = append(sts, states[k])
+	}
+
+	header, err := WriteStateSnapshot(cmd.Snapshot, encs.JSON(), design.NetworkID, height, sts)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Info().Interface("header", header).Str("snapshot", cmd.Snapshot).Msg("state exported")
+
+	return pctx, nil
+}
+
+// lastHeightFromFlag returns the height of flag; without flag, the last height
+// of storage.
+func lastHeightFromFlag(db isaac.Database, flag launch.HeightFlag) (base.Height, error) {
+	switch m, found, err := db.LastBlockMap(); {
+	case err != nil:
+		return base.NilHeight, err
+	case !found:
+		return base.NilHeight, util.ErrNotFound.Errorf("last blockmap")
+	case !flag.IsSet():
+		return m.Manifest().Height(), nil
+	case flag.Height() > m.Manifest().Height():
+		return base.NilHeight, errors.Errorf(
+			"height higher than last; height=%d last=%d", flag.Height(), m.Manifest().Height())
+	default:
+		return flag.Height(), nil
+	}
+}
+
+// replayStates calls f with the states of blocks from genesis to the height by
+// height order.
+func replayStates(
+	readers *isaac.BlockItemReaders,
+	height base.Height,
+	log *zerolog.Logger,
+	f func(base.State) error,
+) error {
+	for h := base.GenesisHeight; h <= height; h++ {
+		// NOTE block without operations has no states.
+		if _, _, _, err := isaac.BlockItemReadersDecodeItems[base.State](
+			readers.Item, h, base.BlockItemStates,
+			func(_ uint64, _ uint64, st base.State) error {
+				return f(st)
+			},
+			nil,
+		); err != nil {
+			return errors.WithMessagef(err, "states; height=%d", h)
+		}
+
+		if h%1000 == 0 { //nolint:gomnd //...
+			log.Debug().Interface("height", h).Msg("states loaded")
+		}
+	}
+
+	return nil
+}
@@ -65,6 +65,21 @@ func (v *KeyFlag) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// AccountKeys returns the account keys of the keys with threshold.
+func (v KeyFlag) AccountKeys(threshold uint) (types.BaseAccountKeys, error) {
+	ks := make([]types.AccountKey, len(v.Values))
+	for i := range v.Values {
+		ks[i] = v.Values[i]
+	}
+
+	kys, err := types.NewBaseAccountKeys(ks, threshold)
+	if err != nil {
+		return types.BaseAccountKeys{}, err
+	}
+
+	return kys, nil
+}
+
 type StringLoad []byte
 
 func (v *StringLoad) UnmarshalText(b []byte) error {
@@ -93,6 +108,7 @@ func (v StringLoad) String() string {
 
 type PrivatekeyFlag struct {
 	base.Privatekey
+	s        string
 	notEmpty bool
 }
 
@@ -101,14 +117,38 @@ func (v PrivatekeyFlag) Empty() bool {
 }
 
 func (v *PrivatekeyFlag) UnmarshalText(b []byte) error {
+	// NOTE "-" is loaded by Load from keystore or stdin.
+	if bytes.Equal(bytes.TrimSpace(b), []byte("-")) {
+		*v = PrivatekeyFlag{s: "-"}
+
+		return nil
+	}
+
 	if k, err := base.DecodePrivatekeyFromString(string(b), enc); err != nil {
 		return errors.Wrapf(err, "invalid private key, %v", string(b))
 	} else if err := k.IsValid(nil); err != nil {
 		return err
 	} else {
-		*v = PrivatekeyFlag{Privatekey: k}
+		*v = PrivatekeyFlag{Privatekey: k, s: string(b)}
+	}
+
+	v.notEmpty = true
+
+	return nil
+}
+
+// Load loads the privatekey of "-" from keystore file or stdin.
+func (v *PrivatekeyFlag) Load(keystore, signer string) error {
+	if !v.Empty() && len(keystore) < 1 && len(signer) < 1 {
+		return nil
+	}
+
+	k, err := LoadPrivatekey(v.s, keystore, signer, enc)
+	if err != nil {
+		return err
 	}
 
+	v.Privatekey = k
 	v.notEmpty = true
 
 	return nil
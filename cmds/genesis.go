@@ -34,6 +34,10 @@ type GenesisBlockGenerator struct {
 	facts     []base.Fact
 	ops       []base.Operation
 	ctx       context.Context
+	states    []base.State
+	allocs    []GenesisAllocation
+	preloads  []base.StateMergeValue
+	replaced  func(string) bool
 }
 
 func NewGenesisBlockGenerator(
@@ -61,6 +65,22 @@ func NewGenesisBlockGenerator(
 	}
 }
 
+// SetSnapshotStates sets the states of state snapshot, which are pre-loaded
+// into genesis block.
+func (g *GenesisBlockGenerator) SetSnapshotStates(sts []base.State) *GenesisBlockGenerator {
+	g.states = sts
+
+	return g
+}
+
+// SetAllocations sets the account allocations, which are created with the
+// balances in genesis block instead of the genesis account.
+func (g *GenesisBlockGenerator) SetAllocations(allocs []GenesisAllocation) *GenesisBlockGenerator {
+	g.allocs = allocs
+
+	return g
+}
+
 func (g *GenesisBlockGenerator) Generate() (base.BlockMap, error) {
 	e := util.StringError("generate genesis block")
 
@@ -130,6 +150,9 @@ func (g *GenesisBlockGenerator) generateOperations() error {
 			}
 
 			g.ops[i], err = g.registerGenesisCurrencyOperation(fact, g.networkID)
+			if err == nil {
+				err = g.loadPreloads(g.ops[i].Fact())
+			}
 		}
 
 		if err != nil {
@@ -139,6 +162,42 @@ func (g *GenesisBlockGenerator) generateOperations() error {
 		types[hinter.Hint().String()] = struct{}{}
 	}
 
+	if len(g.allocs) > 0 && g.preloads == nil {
+		return errors.Errorf("Allocations need RegisterGenesisCurrency operation")
+	}
+
+	return nil
+}
+
+// loadPreloads prepares the states, which are pre-loaded into genesis block
+// with the states of RegisterGenesisCurrency.
+func (g *GenesisBlockGenerator) loadPreloads(i base.Fact) error {
+	switch {
+	case len(g.states) > 0 && len(g.allocs) > 0:
+		return errors.Errorf("Snapshot states and allocations can not be used together")
+	case len(g.states) > 0:
+		g.preloads, g.replaced = snapshotStateMergeValues(g.states)
+	case len(g.allocs) > 0:
+		fact, ok := i.(currency.RegisterGenesisCurrencyFact)
+		if !ok {
+			return errors.Errorf("expected RegisterGenesisCurrencyFact, not %T", i)
+		}
+
+		genesis, err := fact.Address()
+		if err != nil {
+			return err
+		}
+
+		values, replaced, err := genesisAllocationStates(g.allocs, fact.Currencies(), genesis)
+		if err != nil {
+			return errors.WithMessage(err, "allocations")
+		}
+
+		g.preloads, g.replaced = values, replaced
+
+		g.Log().Debug().Int("allocations", len(g.allocs)).Int("states", len(values)).Msg("allocations loaded")
+	}
+
 	return nil
 }
 
@@ -386,6 +445,19 @@ func (g *GenesisBlockGenerator) newProposalProcessor() (*isaac.DefaultProposalPr
 	args := isaac.NewDefaultProposalProcessorArgs()
 	args.NewWriterFunc = launch.NewBlockWriterFunc(
 		g.local, g.networkID, g.dataroot, g.encs.JSON(), g.encs.Default(), g.db, math.MaxInt16, 0)
+
+	if len(g.preloads) > 0 {
+		newWriter := args.NewWriterFunc
+
+		args.NewWriterFunc = func(proposal base.ProposalSignFact, getStateFunc base.GetStateFunc) (isaac.BlockWriter, error) {
+			w, err := newWriter(proposal, getStateFunc)
+			if err != nil {
+				return nil, err
+			}
+
+			return newGenesisStatesWriter(w, g.preloads, g.replaced), nil
+		}
+	}
 	args.GetStateFunc = func(key string) (base.State, bool, error) {
 		return nil, false, nil
 	}
@@ -0,0 +1,350 @@
+package cmds
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/state"
+	"github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+var GenesisAllocationsFileContextKey util.ContextKey = "genesis-allocations-file"
+
+// GenesisAllocation is the initial account of genesis block. With keys, the
+// account is created; without keys, the address should be the account created
+// by the other allocations or the genesis account.
+type GenesisAllocation struct {
+	Address base.Address
+	Keys    types.AccountKeys
+	Amounts []types.Amount
+}
+
+type genesisAllocationJSONUnmarshaler struct {
+	Address string `json:"address"`
+	Keys    []struct {
+		Key    string `json:"key"`
+		Weight uint   `json:"weight"`
+	} `json:"keys"`
+	Threshold uint `json:"threshold"`
+	Amounts   []struct {
+		Currency string `json:"currency"`
+		Amount   string `json:"amount"`
+	} `json:"amounts"`
+}
+
+// LoadGenesisAllocations reads the allocation file by the file extension.
+// * csv: header is `address,keys,threshold,amounts`; keys is
+// "<public key>,<weight>@..." and amounts is "<currency id>,<amount>@...".
+// * json: list of {address, keys: [{key, weight}], threshold, amounts:
+// [{currency, amount}]}.
+func LoadGenesisAllocations(f string, enc encoder.Encoder) ([]GenesisAllocation, error) {
+	e := util.StringError("load genesis allocations")
+
+	r, err := os.Open(filepath.Clean(f))
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	var l []genesisAllocationJSONUnmarshaler
+
+	switch ext := strings.ToLower(filepath.Ext(f)); ext {
+	case ".csv":
+		l, err = readGenesisAllocationsCSV(r)
+	case ".json":
+		var b []byte
+
+		if b, err = io.ReadAll(r); err == nil {
+			err = util.UnmarshalJSON(b, &l)
+		}
+	default:
+		return nil, e.Errorf("unknown allocation file type, %q", ext)
+	}
+
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	allocs := make([]GenesisAllocation, len(l))
+
+	for i := range l {
+		a, err := l[i].decode(enc)
+		if err != nil {
+			return nil, e.WithMessage(err, "allocation %d", i)
+		}
+
+		allocs[i] = a
+	}
+
+	return allocs, nil
+}
+
+func readGenesisAllocationsCSV(r io.Reader) ([]genesisAllocationJSONUnmarshaler, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 4 //nolint:gomnd //...
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) > 0 && strings.EqualFold(records[0][0], "address") {
+		records = records[1:]
+	}
+
+	l := make([]genesisAllocationJSONUnmarshaler, len(records))
+
+	for i := range records {
+		var u genesisAllocationJSONUnmarshaler
+
+		u.Address = records[i][0]
+
+		if s := strings.TrimSpace(records[i][1]); len(s) > 0 {
+			for _, k := range strings.Split(s, "@") {
+				kw := strings.SplitN(k, ",", 2)
+				if len(kw) != 2 { //nolint:gomnd //...
+					return nil, errors.Errorf(`wrong formatted key, %q; "<public key>,<uint weight>"`, k)
+				}
+
+				w, err := strconv.ParseUint(strings.TrimSpace(kw[1]), 10, 8)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid weight, %q", kw[1])
+				}
+
+				u.Keys = append(u.Keys, struct {
+					Key    string `json:"key"`
+					Weight uint   `json:"weight"`
+				}{Key: strings.TrimSpace(kw[0]), Weight: uint(w)})
+			}
+		}
+
+		if s := strings.TrimSpace(records[i][2]); len(s) > 0 {
+			t, err := strconv.ParseUint(s, 10, 8)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid threshold, %q", s)
+			}
+
+			u.Threshold = uint(t)
+		}
+
+		for _, a := range strings.Split(records[i][3], "@") {
+			ca := strings.SplitN(a, ",", 2)
+			if len(ca) != 2 { //nolint:gomnd //...
+				return nil, errors.Errorf(`wrong formatted amount, %q; "<currency id>,<amount>"`, a)
+			}
+
+			u.Amounts = append(u.Amounts, struct {
+				Currency string `json:"currency"`
+				Amount   string `json:"amount"`
+			}{Currency: strings.TrimSpace(ca[0]), Amount: strings.TrimSpace(ca[1])})
+		}
+
+		l[i] = u
+	}
+
+	return l, nil
+}
+
+func (u genesisAllocationJSONUnmarshaler) decode(enc encoder.Encoder) (GenesisAllocation, error) {
+	var a GenesisAllocation
+
+	if len(u.Keys) > 0 {
+		keys := make([]types.AccountKey, len(u.Keys))
+
+		for i := range u.Keys {
+			pub, err := base.DecodePublickeyFromString(u.Keys[i].Key, enc)
+			if err != nil {
+				return a, errors.WithMessagef(err, "invalid public key, %q", u.Keys[i].Key)
+			}
+
+			k, err := types.NewBaseAccountKey(pub, u.Keys[i].Weight)
+			if err != nil {
+				return a, err
+			}
+
+			keys[i] = k
+		}
+
+		threshold := u.Threshold
+		if threshold < 1 {
+			threshold = 100
+		}
+
+		ks, err := types.NewBaseAccountKeys(keys, threshold)
+		if err != nil {
+			return a, err
+		}
+
+		if err := ks.IsValid(nil); err != nil {
+			return a, err
+		}
+
+		a.Keys = ks
+
+		ad, err := types.NewAddressFromKeys(ks)
+		if err != nil {
+			return a, err
+		}
+
+		a.Address = ad
+	}
+
+	if s := strings.TrimSpace(u.Address); len(s) > 0 {
+		ad, err := base.DecodeAddress(s, enc)
+		if err != nil {
+			return a, errors.WithMessagef(err, "invalid address, %q", s)
+		}
+
+		switch {
+		case a.Address == nil:
+			a.Address = ad
+		case !a.Address.Equal(ad):
+			return a, errors.Errorf("address not matched with keys; address=%q keys=%q", ad, a.Address)
+		}
+	}
+
+	if a.Address == nil {
+		return a, errors.Errorf("empty address and keys")
+	}
+
+	if len(u.Amounts) < 1 {
+		return a, errors.Errorf("empty amounts")
+	}
+
+	founds := map[string]struct{}{}
+
+	for i := range u.Amounts {
+		cid := types.CurrencyID(u.Amounts[i].Currency)
+		if err := cid.IsValid(nil); err != nil {
+			return a, err
+		}
+
+		if _, found := founds[cid.String()]; found {
+			return a, errors.Errorf("duplicated currency, %q", cid)
+		}
+
+		founds[cid.String()] = struct{}{}
+
+		big, err := common.NewBigFromString(u.Amounts[i].Amount)
+		if err != nil {
+			return a, errors.WithMessagef(err, "invalid amount, %q", u.Amounts[i].Amount)
+		}
+
+		if !big.OverZero() {
+			return a, errors.Errorf("amount should be over zero, %q", u.Amounts[i].Amount)
+		}
+
+		a.Amounts = append(a.Amounts, types.NewAmount(big, cid))
+	}
+
+	return a, nil
+}
+
+// genesisAllocationStates validates the allocations with the currency designs
+// of genesis and returns the account and balance states. The allocations of
+// each currency should be summed to the initial supply of currency; the
+// genesis account does not get the initial supply of the allocated currency.
+func genesisAllocationStates(
+	allocs []GenesisAllocation,
+	designs []types.CurrencyDesign,
+	genesis base.Address,
+) ([]base.StateMergeValue, func(string) bool, error) {
+	initials := map[types.CurrencyID]common.Big{}
+	for i := range designs {
+		initials[designs[i].Currency()] = designs[i].InitialSupply().Big()
+	}
+
+	accounts := map[string]struct{}{genesis.String(): {}}
+	for i := range allocs {
+		if allocs[i].Keys == nil {
+			continue
+		}
+
+		if _, found := accounts[allocs[i].Address.String()]; found {
+			return nil, nil, errors.Errorf("duplicated account, %q", allocs[i].Address)
+		}
+
+		accounts[allocs[i].Address.String()] = struct{}{}
+	}
+
+	sums := map[types.CurrencyID]common.Big{}
+	balances := map[string]struct{}{}
+
+	var values []base.StateMergeValue
+
+	for i := range allocs {
+		a := allocs[i]
+
+		if _, found := accounts[a.Address.String()]; !found {
+			return nil, nil, errors.Errorf("account not created in genesis, %q", a.Address)
+		}
+
+		if a.Keys != nil {
+			ac, err := types.NewAccount(a.Address, a.Keys)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			values = append(values, state.NewStateMergeValue(
+				currency.AccountStateKey(a.Address), currency.NewAccountStateValue(ac)))
+		}
+
+		for j := range a.Amounts {
+			am := a.Amounts[j]
+
+			if _, found := initials[am.Currency()]; !found {
+				return nil, nil, errors.Errorf("unknown currency in genesis, %q", am.Currency())
+			}
+
+			key := currency.BalanceStateKey(a.Address, am.Currency())
+			if _, found := balances[key]; found {
+				return nil, nil, errors.Errorf("duplicated balance, %q", key)
+			}
+
+			balances[key] = struct{}{}
+
+			if s, found := sums[am.Currency()]; found {
+				sums[am.Currency()] = s.Add(am.Big())
+			} else {
+				sums[am.Currency()] = am.Big()
+			}
+
+			values = append(values, state.NewStateMergeValue(key, currency.NewBalanceStateValue(am)))
+		}
+	}
+
+	replaced := map[string]struct{}{}
+
+	for i := range values {
+		replaced[values[i].Key()] = struct{}{}
+	}
+
+	for cid, sum := range sums {
+		if !sum.Equal(initials[cid]) {
+			return nil, nil, errors.Errorf("allocations of %q not matched with initial supply; allocations=%s initial=%s",
+				cid, sum, initials[cid])
+		}
+
+		replaced[currency.BalanceStateKey(genesis, cid)] = struct{}{}
+	}
+
+	return values, func(key string) bool {
+		_, found := replaced[key]
+
+		return found
+	}, nil
+}
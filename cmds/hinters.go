@@ -36,6 +36,8 @@ var AddedHinters = []encoder.DecodeDetail{
 	{Hint: types.ContractAccountStatusHint, Instance: types.ContractAccountStatus{}},
 	{Hint: types.CurrencyDesignHint, Instance: types.CurrencyDesign{}},
 	{Hint: types.CurrencyPolicyHint, Instance: types.CurrencyPolicy{}},
+	{Hint: types.Ed25519PrivatekeyHint, Instance: types.Ed25519Privatekey{}},
+	{Hint: types.Ed25519PublickeyHint, Instance: types.Ed25519Publickey{}},
 	{Hint: types.FixedFeeerHint, Instance: types.FixedFeeer{}},
 	{Hint: types.MEPrivatekeyHint, Instance: types.MEPrivatekey{}},
 	{Hint: types.MEPublickeyHint, Instance: types.MEPublickey{}},
@@ -68,6 +70,8 @@ var AddedHinters = []encoder.DecodeDetail{
 	{Hint: isaacoperation.GenesisNetworkPolicyHint, Instance: isaacoperation.GenesisNetworkPolicy{}},
 	{Hint: isaacoperation.FixedSuffrageCandidateLimiterRuleHint, Instance: isaacoperation.FixedSuffrageCandidateLimiterRule{}},
 	{Hint: isaacoperation.MajoritySuffrageCandidateLimiterRuleHint, Instance: isaacoperation.MajoritySuffrageCandidateLimiterRule{}},
+	{Hint: isaacoperation.BalanceSuffrageCandidateLimiterRuleHint, Instance: isaacoperation.BalanceSuffrageCandidateLimiterRule{}},
+	{Hint: isaacoperation.BondSuffrageCandidateLimiterRuleHint, Instance: isaacoperation.BondSuffrageCandidateLimiterRule{}},
 	{Hint: types.NetworkPolicyHint, Instance: types.NetworkPolicy{}},
 	{Hint: types.NetworkPolicyStateValueHint, Instance: types.NetworkPolicyStateValue{}},
 	{Hint: isaacoperation.SuffrageCandidateHint, Instance: isaacoperation.SuffrageCandidate{}},
@@ -79,6 +83,7 @@ var AddedHinters = []encoder.DecodeDetail{
 	{Hint: statecurrency.AccountStateValueHint, Instance: statecurrency.AccountStateValue{}},
 	{Hint: statecurrency.BalanceStateValueHint, Instance: statecurrency.BalanceStateValue{}},
 	{Hint: statecurrency.DesignStateValueHint, Instance: statecurrency.DesignStateValue{}},
+	{Hint: statecurrency.SuffrageBondStateValueHint, Instance: statecurrency.SuffrageBondStateValue{}},
 
 	{Hint: stateextension.ContractAccountStateValueHint, Instance: stateextension.ContractAccountStateValue{}},
 
@@ -0,0 +1,88 @@
+package cmds
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+)
+
+var PNameLoadStateSnapshot = ps.Name("load-state-snapshot")
+
+// ImportStateCommand initializes node like `init` command, but the genesis
+// block has the states of state snapshot. The genesis design should have the
+// RegisterGenesisCurrency operation.
+type ImportStateCommand struct {
+	GenesisDesign string `arg:"" name:"genesis design" help:"genesis design" type:"filepath"`
+	Snapshot      string `arg:"" name:"snapshot" help:"state snapshot file" type:"existingfile"`
+	launch.PrivatekeyFlags
+	launch.DesignFlag
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *ImportStateCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey:        cmd.DesignFlag,
+		launch.DevFlagsContextKey:          cmd.DevFlags,
+		launch.GenesisDesignFileContextKey: cmd.GenesisDesign,
+		launch.PrivatekeyContextKey:        string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := DefaultINITPS()
+	_ = pps.SetLogging(log)
+
+	_ = pps.POK(PNameGenerateGenesis).
+		PreAddOK(PNameLoadStateSnapshot, cmd.pLoadStateSnapshot)
+
+	log.Log().Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		log.Log().Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			log.Log().Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *ImportStateCommand) pLoadStateSnapshot(pctx context.Context) (context.Context, error) {
+	e := util.StringError("load state snapshot")
+
+	var log *logging.Logging
+	var encs *encoder.Encoders
+	var design launch.NodeDesign
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.LoggingContextKey, &log,
+		launch.EncodersContextKey, &encs,
+		launch.DesignContextKey, &design,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	header, sts, err := ReadStateSnapshot(cmd.Snapshot, encs)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	l := log.Log().With().Interface("header", header).Logger()
+
+	if !header.NetworkID.Equal(design.NetworkID) {
+		l.Warn().Interface("network_id", design.NetworkID).Msg("state snapshot from different network")
+	}
+
+	l.Info().Msg("state snapshot loaded")
+
+	return context.WithValue(pctx, StateSnapshotContextKey, sts), nil
+}
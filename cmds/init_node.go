@@ -10,6 +10,7 @@ import (
 
 type INITCommand struct {
 	GenesisDesign string `arg:"" name:"genesis design" help:"genesis design" type:"filepath"`
+	Allocations   string `name:"allocations" help:"account allocations file, csv or json" type:"existingfile"`
 	launch.PrivatekeyFlags
 	launch.DesignFlag
 	launch.DevFlags `embed:"" prefix:"dev."`
@@ -25,6 +26,7 @@ func (cmd *INITCommand) Run(pctx context.Context) error {
 		launch.DesignFlagContextKey:        cmd.DesignFlag,
 		launch.DevFlagsContextKey:          cmd.DevFlags,
 		launch.GenesisDesignFileContextKey: cmd.GenesisDesign,
+		GenesisAllocationsFileContextKey:   cmd.Allocations,
 		launch.PrivatekeyContextKey:        string(cmd.PrivatekeyFlags.Flag.Body()),
 	})
 
@@ -0,0 +1,80 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+type KeyExportCommand struct {
+	BaseCommand
+	KeyString string `arg:"" name:"privatekey" help:"privatekey string; \"-\" to load from stdin"`
+	Output    string `arg:"" name:"keystore" optional:"" help:"keystore file to write; without it, print keystore" type:"path"` //nolint:lll //...
+	Light     bool   `name:"light" help:"use light scrypt parameters"`
+}
+
+func (cmd *KeyExportCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().Str("keystore", cmd.Output).Bool("light", cmd.Light).Msg("flags")
+
+	if len(cmd.Output) > 0 {
+		switch _, err := os.Stat(cmd.Output); {
+		case err == nil:
+			return errors.Errorf("keystore file already exists, %q", cmd.Output)
+		case !os.IsNotExist(err):
+			return errors.WithStack(err)
+		}
+	}
+
+	key, err := LoadPrivatekey(cmd.KeyString, "", "", cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	mkey, ok := key.(types.MEPrivatekey)
+	if !ok {
+		return errors.Errorf("unsupported privatekey for keystore, %T", key)
+	}
+
+	passphrase, err := loadKeystorePassphrase(true)
+	if err != nil {
+		return err
+	}
+
+	n, p := types.StandardKeystoreScryptN, types.StandardKeystoreScryptP
+	if cmd.Light {
+		n, p = types.LightKeystoreScryptN, types.LightKeystoreScryptP
+	}
+
+	ks, err := types.EncryptKeystore(mkey, passphrase, n, p)
+	if err != nil {
+		return err
+	}
+
+	b, err := util.MarshalJSONIndent(ks)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Output) < 1 {
+		_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+		return nil
+	}
+
+	if err := os.WriteFile(filepath.Clean(cmd.Output), b, 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	cmd.Log.Debug().Str("keystore", cmd.Output).Msg("keystore exported")
+
+	return nil
+}
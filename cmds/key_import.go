@@ -0,0 +1,49 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+type KeyImportCommand struct {
+	BaseCommand
+	Keystore string `arg:"" name:"keystore" help:"keystore file" type:"existingfile"`
+}
+
+func (cmd *KeyImportCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	cmd.Log.Debug().Str("keystore", cmd.Keystore).Msg("flags")
+
+	key, err := LoadKeystore(cmd.Keystore)
+	if err != nil {
+		return err
+	}
+
+	o := struct {
+		PrivateKey base.PKKey  `json:"privatekey"` //nolint:tagliatelle //...
+		Publickey  base.PKKey  `json:"publickey"`
+		Hint       interface{} `json:"hint,omitempty"`
+		Type       string      `json:"type"`
+	}{
+		PrivateKey: key,
+		Publickey:  key.Publickey(),
+		Hint:       key.Hint(),
+		Type:       "privatekey",
+	}
+
+	b, err := util.MarshalJSONIndent(o)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+	return nil
+}
@@ -3,6 +3,7 @@ package cmds
 import (
 	"context"
 	"fmt"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
@@ -12,7 +13,10 @@ import (
 
 type KeyLoadCommand struct {
 	BaseCommand
-	KeyString string `arg:"" name:"key string" help:"key string"`
+	KeyString  string `arg:"" name:"key string" help:"key string; with --mnemonic, BIP-39 mnemonic or \"-\" to read it from stdin or prompt"`
+	Mnemonic   bool   `name:"mnemonic" help:"load key from BIP-39 mnemonic"`
+	Path       string `name:"path" help:"BIP-32 derivation path for mnemonic" default:"m/44'/60'/0'/0/0"`
+	Passphrase string `name:"passphrase" help:"BIP-39 passphrase for mnemonic; \"-\" to read it from MITUM_MNEMONIC_PASSPHRASE or prompt"` //nolint:lll //...
 }
 
 func (cmd *KeyLoadCommand) Run(pctx context.Context) error {
@@ -20,14 +24,21 @@ func (cmd *KeyLoadCommand) Run(pctx context.Context) error {
 		return err
 	}
 
-	cmd.Log.Debug().
-		Str("key_string", cmd.KeyString).
-		Msg("flags")
+	l := cmd.Log.Debug().Bool("mnemonic", cmd.Mnemonic).Str("path", cmd.Path)
+	if !cmd.Mnemonic { // NOTE mnemonic is not logged
+		l = l.Str("key_string", cmd.KeyString)
+	}
+
+	l.Msg("flags")
 
 	if len(cmd.KeyString) < 1 {
 		return errors.Errorf("Empty key string")
 	}
 
+	if cmd.Mnemonic {
+		return cmd.loadMnemonic()
+	}
+
 	var gerr error
 
 	for _, f := range []func() (bool, error){cmd.loadPrivatekey, cmd.loadPublickey} {
@@ -81,6 +92,46 @@ func (cmd *KeyLoadCommand) loadPrivatekey() (bool, error) {
 	return true, nil
 }
 
+func (cmd *KeyLoadCommand) loadMnemonic() error {
+	mnemonic, err := loadMnemonic(cmd.KeyString)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := loadMnemonicPassphrase(cmd.Passphrase, false)
+	if err != nil {
+		return err
+	}
+
+	key, err := types.NewMEPrivatekeyFromMnemonic(mnemonic, passphrase, cmd.Path)
+	if err != nil {
+		return err
+	}
+
+	o := struct {
+		PrivateKey base.PKKey  `json:"privatekey"` //nolint:tagliatelle //...
+		Publickey  base.PKKey  `json:"publickey"`
+		Hint       interface{} `json:"hint,omitempty"`
+		Path       string      `json:"path"`
+		Type       string      `json:"type"`
+	}{
+		PrivateKey: key,
+		Publickey:  key.Publickey(),
+		Hint:       key.Hint(),
+		Path:       cmd.Path,
+		Type:       "privatekey",
+	}
+
+	b, err := util.MarshalJSONIndent(o)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+	return nil
+}
+
 func (cmd *KeyLoadCommand) loadPublickey() (bool, error) {
 	key, err := base.DecodePublickeyFromString(cmd.KeyString, cmd.Encoder)
 	if err != nil {
@@ -10,11 +10,17 @@ import (
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/pkg/errors"
 )
 
 type KeyNewCommand struct {
 	BaseCommand
-	Seed string `arg:"" name:"seed" optional:"" help:"seed for generating key"`
+	Seed       string `arg:"" name:"seed" optional:"" help:"seed for generating key"`
+	Mnemonic   bool   `name:"mnemonic" help:"generate key from new BIP-39 mnemonic"`
+	Words      int    `name:"words" help:"number of mnemonic words; 12 or 24" default:"12"`
+	Path       string `name:"path" help:"BIP-32 derivation path for mnemonic" default:"m/44'/60'/0'/0/0"`
+	Passphrase string `name:"passphrase" help:"BIP-39 passphrase for mnemonic; \"-\" to read it from MITUM_MNEMONIC_PASSPHRASE or prompt"` //nolint:lll //...
+	KeyType    string `name:"type" help:"key type; secp256k1 or ed25519" default:"secp256k1" enum:"secp256k1,ed25519"`
 }
 
 func (cmd *KeyNewCommand) Run(pctx context.Context) error {
@@ -24,6 +30,10 @@ func (cmd *KeyNewCommand) Run(pctx context.Context) error {
 
 	cmd.Log.Debug().
 		Str("seed", cmd.Seed).
+		Bool("mnemonic", cmd.Mnemonic).
+		Int("words", cmd.Words).
+		Str("path", cmd.Path).
+		Str("type", cmd.KeyType).
 		Msg("flags")
 
 	if _, err := cmd.prepare(pctx); err != nil {
@@ -31,8 +41,53 @@ func (cmd *KeyNewCommand) Run(pctx context.Context) error {
 	}
 
 	var key base.Privatekey
+	var mnemonic, path string
+
+	isEd25519 := cmd.KeyType == "ed25519"
 
 	switch {
+	case cmd.Mnemonic && isEd25519:
+		return errors.Errorf("mnemonic can not be used with ed25519")
+	case cmd.Mnemonic:
+		if len(cmd.Seed) > 0 {
+			return errors.Errorf("seed can not be used with mnemonic")
+		}
+
+		var bits int
+
+		switch cmd.Words {
+		case 12: //nolint:gomnd //...
+			bits = 128
+		case 24: //nolint:gomnd //...
+			bits = 256
+		default:
+			return errors.Errorf("words should be 12 or 24, not %d", cmd.Words)
+		}
+
+		i, err := types.NewMnemonic(bits)
+		if err != nil {
+			return err
+		}
+
+		passphrase, err := loadMnemonicPassphrase(cmd.Passphrase, true)
+		if err != nil {
+			return err
+		}
+
+		j, err := types.NewMEPrivatekeyFromMnemonic(i, passphrase, cmd.Path)
+		if err != nil {
+			return err
+		}
+
+		key, mnemonic, path = j, i, cmd.Path
+	case isEd25519 && len(cmd.Seed) > 0:
+		i, err := types.NewEd25519PrivatekeyFromSeed(cmd.Seed)
+		if err != nil {
+			return err
+		}
+		key = i
+	case isEd25519:
+		key = types.NewEd25519Privatekey()
 	case len(cmd.Seed) > 0:
 		if len(strings.TrimSpace(cmd.Seed)) < 1 {
 			cmd.Log.Warn().Msg("seed consists with empty spaces")
@@ -53,9 +108,13 @@ func (cmd *KeyNewCommand) Run(pctx context.Context) error {
 		Publickey  base.PKKey  `json:"publickey"`
 		Hint       interface{} `json:"hint,omitempty"`
 		Seed       string      `json:"seed"`
+		Mnemonic   string      `json:"mnemonic,omitempty"`
+		Path       string      `json:"path,omitempty"`
 		Type       string      `json:"type"`
 	}{
 		Seed:       cmd.Seed,
+		Mnemonic:   mnemonic,
+		Path:       path,
 		PrivateKey: key,
 		Publickey:  key.Publickey(),
 		Type:       "privatekey",
@@ -0,0 +1,288 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/pkg/errors"
+)
+
+// KeyPlanCommand fetches the current keys of account from the remote node,
+// compares them with the new keys and creates the signed UpdateKey operation.
+// The signers are the public key of privatekey and the --cosigner keys, which
+// will sign the operation later; the signers should be able to meet the new
+// threshold, so the account will not be locked after the keys are updated.
+type KeyPlanCommand struct { //nolint:govet //...
+	BaseNetworkClientCommand
+	Sender     AddressFlag     `arg:"" name:"sender" help:"sender address" required:"true"`
+	Currency   CurrencyIDFlag  `arg:"" name:"currency-id" help:"currency id for fee" required:"true"`
+	Privatekey PrivatekeyFlag  `arg:"" name:"privatekey" help:"privatekey to sign operation; \"-\" to load from --keystore, --signer or stdin" required:"true"` //nolint:lll //...
+	Keystore   string          `name:"keystore" help:"keystore file to sign operation" type:"existingfile"`
+	Signer     string          `name:"signer" help:"local socket of external signer to sign operation"`
+	Threshold  uint            `help:"threshold for new keys (default: ${create_account_threshold})" default:"${create_account_threshold}"` // nolint
+	Key        KeyFlag         `name:"key" help:"new key (ex: \"<public key>,<weight>\") separator @" required:"true"`
+	Cosigner   []PublickeyFlag `name:"cosigner" help:"public key of other signer, who will sign the operation" sep:"none"`
+	Token      string          `help:"token for operation" optional:""`
+	sender     base.Address
+	keys       types.BaseAccountKeys
+	signers    []base.Publickey
+}
+
+func (cmd *KeyPlanCommand) Run(pctx context.Context) error {
+	if err := cmd.Prepare(pctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cmd.Client.Close()
+	}()
+
+	encs = cmd.Encoders
+	enc = cmd.Encoder
+
+	if err := cmd.parseFlags(); err != nil {
+		return err
+	}
+
+	current, err := cmd.currentKeys(pctx)
+	if err != nil {
+		return err
+	}
+
+	plan, err := newKeyPlan(current, cmd.keys, cmd.signers)
+	if err != nil {
+		return err
+	}
+
+	plan.write(os.Stderr)
+
+	if err := plan.IsValid(nil); err != nil {
+		return errors.WithMessage(err, "key plan")
+	}
+
+	fact := currency.NewUpdateKeyFact([]byte(cmd.Token), cmd.sender, cmd.keys, cmd.Currency.CID)
+
+	op, err := currency.NewUpdateKey(fact)
+	if err != nil {
+		return errors.Wrap(err, "create update-key operation")
+	}
+
+	if err := op.HashSign(cmd.Privatekey, base.NetworkID(cmd.NetworkID)); err != nil {
+		return errors.Wrap(err, "create update-key operation")
+	}
+
+	PrettyPrint(cmd.Out, op)
+
+	return nil
+}
+
+func (cmd *KeyPlanCommand) parseFlags() error {
+	if err := cmd.Privatekey.Load(cmd.Keystore, cmd.Signer); err != nil {
+		return err
+	}
+
+	if len(cmd.Token) < 1 {
+		cmd.Token = localtime.Now().UTC().String()
+	}
+
+	a, err := cmd.Sender.Encode(enc)
+	if err != nil {
+		return errors.Wrapf(err, "invalid sender format, %v", cmd.Sender.String())
+	}
+
+	if a == nil {
+		return errors.Errorf("empty sender")
+	}
+
+	cmd.sender = a
+
+	kys, err := cmd.Key.AccountKeys(cmd.Threshold)
+	if err != nil {
+		return err
+	}
+
+	cmd.keys = kys
+
+	cmd.signers = []base.Publickey{cmd.Privatekey.Publickey()}
+
+	for i := range cmd.Cosigner {
+		pub := cmd.Cosigner[i].Publickey
+
+		for j := range cmd.signers {
+			if cmd.signers[j].Equal(pub) {
+				return errors.Errorf("duplicated signer, %v", pub)
+			}
+		}
+
+		cmd.signers = append(cmd.signers, pub)
+	}
+
+	return nil
+}
+
+// currentKeys fetches the account keys of sender; the balance of fee currency
+// is also checked against the fee of update-key, so the operation will not be
+// rejected by insufficient balance.
+func (cmd *KeyPlanCommand) currentKeys(pctx context.Context) (types.AccountKeys, error) {
+	ctx, cancel := context.WithTimeout(pctx, cmd.Timeout)
+	defer cancel()
+
+	var keys types.AccountKeys
+
+	switch st, found, err := cmd.Client.State(ctx, cmd.Remote.ConnInfo(), statecurrency.AccountStateKey(cmd.sender), nil); {
+	case err != nil:
+		return nil, errors.WithMessage(err, "get sender account state")
+	case !found:
+		return nil, errors.Errorf("sender account not found, %v", cmd.sender)
+	default:
+		ac, err := statecurrency.LoadAccountStateValue(st)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = ac.Keys()
+	}
+
+	var fee common.Big
+
+	switch st, found, err := cmd.Client.State(
+		ctx, cmd.Remote.ConnInfo(), statecurrency.DesignStateKey(cmd.Currency.CID), nil); {
+	case err != nil:
+		return nil, errors.WithMessage(err, "get currency design state")
+	case !found:
+		return nil, errors.Errorf("currency, %v not found", cmd.Currency.CID)
+	default:
+		de, err := statecurrency.GetDesignFromState(st)
+		if err != nil {
+			return nil, err
+		}
+
+		if fee, err = de.Policy().Feeer().Fee(common.ZeroBig); err != nil {
+			return nil, errors.WithMessagef(err, "fee of currency, %v", cmd.Currency.CID)
+		}
+	}
+
+	switch st, found, err := cmd.Client.State(
+		ctx, cmd.Remote.ConnInfo(), statecurrency.BalanceStateKey(cmd.sender, cmd.Currency.CID), nil); {
+	case err != nil:
+		return nil, errors.WithMessage(err, "get sender balance state")
+	case !found:
+		return nil, errors.Errorf("sender balance of currency, %v not found", cmd.Currency.CID)
+	default:
+		am, err := statecurrency.StateBalanceValue(st)
+		if err != nil {
+			return nil, err
+		}
+
+		if am.Big().Compare(fee) < 0 {
+			return nil, errors.Errorf("insufficient balance for fee, %v < %v", am.Big(), fee)
+		}
+	}
+
+	return keys, nil
+}
+
+type keyPlanChange struct {
+	key        base.Publickey
+	weight     uint
+	prevWeight uint
+}
+
+// keyPlan is the difference between the current keys and the new keys with the
+// weights of signers.
+type keyPlan struct {
+	current       types.AccountKeys
+	keys          types.AccountKeys
+	added         []keyPlanChange
+	removed       []keyPlanChange
+	changed       []keyPlanChange
+	unknowns      []base.Publickey
+	currentWeight uint
+	weight        uint
+}
+
+func newKeyPlan(current, keys types.AccountKeys, signers []base.Publickey) (keyPlan, error) {
+	switch current.(type) {
+	case nil, types.NilAccountKeys, types.ContractAccountKeys:
+		return keyPlan{}, errors.Errorf("sender account has no updatable keys, %T", current)
+	}
+
+	p := keyPlan{current: current, keys: keys}
+
+	for _, k := range keys.Keys() {
+		switch ck, found := current.Key(k.Key()); {
+		case !found:
+			p.added = append(p.added, keyPlanChange{key: k.Key(), weight: k.Weight()})
+		case ck.Weight() != k.Weight():
+			p.changed = append(p.changed, keyPlanChange{key: k.Key(), weight: k.Weight(), prevWeight: ck.Weight()})
+		}
+	}
+
+	for _, ck := range current.Keys() {
+		if _, found := keys.Key(ck.Key()); !found {
+			p.removed = append(p.removed, keyPlanChange{key: ck.Key(), prevWeight: ck.Weight()})
+		}
+	}
+
+	for i := range signers {
+		if ck, found := current.Key(signers[i]); found {
+			p.currentWeight += ck.Weight()
+		} else {
+			p.unknowns = append(p.unknowns, signers[i])
+		}
+
+		if k, found := keys.Key(signers[i]); found {
+			p.weight += k.Weight()
+		}
+	}
+
+	return p, nil
+}
+
+// IsValid checks the new keys can be updated by the signers and the signers
+// can still meet the threshold of the new keys.
+func (p keyPlan) IsValid([]byte) error {
+	switch {
+	case p.current.Equal(p.keys):
+		return errors.Errorf("new keys is same with current keys")
+	case len(p.unknowns) > 0:
+		return errors.Errorf("signer not in current keys, %v", p.unknowns[0])
+	case p.weight < p.keys.Threshold():
+		return errors.Errorf(
+			"signers can not meet new threshold, %d < %d; account will be locked", p.weight, p.keys.Threshold())
+	}
+
+	return nil
+}
+
+func (p keyPlan) write(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "threshold: %d -> %d\n", p.current.Threshold(), p.keys.Threshold())
+
+	for i := range p.added {
+		_, _ = fmt.Fprintf(w, "+ %v weight=%d\n", p.added[i].key, p.added[i].weight)
+	}
+
+	for i := range p.changed {
+		_, _ = fmt.Fprintf(w, "~ %v weight=%d -> %d\n", p.changed[i].key, p.changed[i].prevWeight, p.changed[i].weight)
+	}
+
+	for i := range p.removed {
+		_, _ = fmt.Fprintf(w, "- %v weight=%d\n", p.removed[i].key, p.removed[i].prevWeight)
+	}
+
+	_, _ = fmt.Fprintf(w, "signers: current weight=%d threshold=%d, new weight=%d threshold=%d\n",
+		p.currentWeight, p.current.Threshold(), p.weight, p.keys.Threshold())
+
+	if p.currentWeight < p.current.Threshold() {
+		_, _ = fmt.Fprintf(w, "warning: signs of other signers needed to meet current threshold, %d < %d\n",
+			p.currentWeight, p.current.Threshold())
+	}
+}
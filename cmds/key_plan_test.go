@@ -0,0 +1,163 @@
+package cmds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+)
+
+type testKeyPlanKey struct {
+	priv   base.Privatekey
+	weight uint
+}
+
+func newTestKeyPlanKeys(t *testing.T, threshold uint, kws ...testKeyPlanKey) types.AccountKeys {
+	t.Helper()
+
+	ks := make([]types.AccountKey, len(kws))
+
+	for i := range kws {
+		k, err := types.NewBaseAccountKey(kws[i].priv.Publickey(), kws[i].weight)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ks[i] = k
+	}
+
+	keys, err := types.NewBaseAccountKeys(ks, threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keys
+}
+
+func TestKeyPlan(t *testing.T) {
+	a, b, c := types.NewMEPrivatekey(), types.NewMEPrivatekey(), types.NewMEPrivatekey()
+
+	single := newTestKeyPlanKeys(t, 100, testKeyPlanKey{a, 100})
+	multi := newTestKeyPlanKeys(t, 100, testKeyPlanKey{a, 50}, testKeyPlanKey{b, 50})
+
+	for _, i := range []struct {
+		name    string
+		current types.AccountKeys
+		keys    types.AccountKeys
+		signers []base.Privatekey
+		added   int
+		removed int
+		changed int
+		error   string
+	}{
+		{
+			name:    "add key",
+			current: single,
+			keys:    newTestKeyPlanKeys(t, 100, testKeyPlanKey{a, 100}, testKeyPlanKey{b, 50}),
+			signers: []base.Privatekey{a},
+			added:   1,
+		},
+		{
+			name:    "change weight and threshold",
+			current: multi,
+			keys:    newTestKeyPlanKeys(t, 60, testKeyPlanKey{a, 60}, testKeyPlanKey{b, 40}),
+			signers: []base.Privatekey{a, b},
+			changed: 2,
+		},
+		{
+			name:    "same keys",
+			current: multi,
+			keys:    multi,
+			signers: []base.Privatekey{a, b},
+			error:   "same with current keys",
+		},
+		{
+			name:    "threshold above weight of signers",
+			current: single,
+			keys:    newTestKeyPlanKeys(t, 100, testKeyPlanKey{a, 50}, testKeyPlanKey{b, 50}),
+			signers: []base.Privatekey{a},
+			changed: 1,
+			added:   1,
+			error:   "can not meet new threshold, 50 < 100",
+		},
+		{
+			name:    "remove only controlled key",
+			current: single,
+			keys:    newTestKeyPlanKeys(t, 100, testKeyPlanKey{b, 100}),
+			signers: []base.Privatekey{a},
+			added:   1,
+			removed: 1,
+			error:   "account will be locked",
+		},
+		{
+			name:    "unknown cosigner",
+			current: single,
+			keys:    newTestKeyPlanKeys(t, 100, testKeyPlanKey{a, 100}, testKeyPlanKey{b, 100}),
+			signers: []base.Privatekey{a, c},
+			added:   1,
+			error:   "signer not in current keys",
+		},
+	} {
+		t.Run(i.name, func(t *testing.T) {
+			signers := make([]base.Publickey, len(i.signers))
+			for j := range i.signers {
+				signers[j] = i.signers[j].Publickey()
+			}
+
+			p, err := newKeyPlan(i.current, i.keys, signers)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(p.added) != i.added || len(p.removed) != i.removed || len(p.changed) != i.changed {
+				t.Fatalf("unexpected changes, added=%d removed=%d changed=%d",
+					len(p.added), len(p.removed), len(p.changed))
+			}
+
+			switch err := p.IsValid(nil); {
+			case len(i.error) < 1:
+				if err != nil {
+					t.Fatal(err)
+				}
+			case err == nil:
+				t.Fatalf("expected error, %q", i.error)
+			case !strings.Contains(err.Error(), i.error):
+				t.Fatalf("expected error %q, but %v", i.error, err)
+			}
+		})
+	}
+
+	t.Run("threshold above total weight", func(t *testing.T) {
+		k, err := types.NewBaseAccountKey(b.Publickey(), 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// NOTE the new keys of --key and --threshold are rejected before the
+		// plan.
+		if _, err := (KeyFlag{Values: []types.BaseAccountKey{k}}).AccountKeys(100); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("no updatable keys", func(t *testing.T) {
+		nilkeys, err := types.NewNilAccountKeys(a.Publickey())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		contractkeys, err := types.NewContractAccountKeys()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, current := range []types.AccountKeys{nil, nilkeys, contractkeys} {
+			if _, err := newKeyPlan(current, single, []base.Publickey{a.Publickey()}); err == nil {
+				t.Fatalf("expected error for %T", current)
+			} else if !strings.Contains(err.Error(), "no updatable keys") {
+				t.Fatalf("unexpected error, %v", err)
+			}
+		}
+	})
+}
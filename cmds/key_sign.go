@@ -8,19 +8,24 @@ import (
 	"os"
 	"reflect"
 
+	"github.com/ProtoconNet/mitum-currency/v3/common"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/launch"
 	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/pkg/errors"
 )
 
 type KeySignCommand struct {
 	BaseCommand
-	KeyString string             `arg:"" name:"privatekey" help:"privatekey string"`
+	KeyString string             `arg:"" name:"privatekey" help:"privatekey string; \"-\" to load from --keystore, --signer or stdin"`
 	NetworkID string             `arg:"" name:"network-id" help:"network-id"`
 	Body      *os.File           `arg:"" help:"body"`
 	Node      launch.AddressFlag `help:"node address"`
 	Token     string             `help:"set fact token"`
+	Keystore  string             `name:"keystore" help:"keystore file to sign" type:"existingfile"`
+	Signer    string             `name:"signer" help:"local socket of external signer to sign"`
+	Scheme    string             `name:"scheme" help:"sign scheme; mitum, eip191 or eip712" default:"mitum" enum:"mitum,eip191,eip712"` //nolint:lll //...
 	priv      base.Privatekey
 	networkID base.NetworkID
 }
@@ -32,6 +37,9 @@ func (cmd *KeySignCommand) Run(pctx context.Context) error {
 
 	cmd.Log.Debug().
 		Str("privatekey", cmd.KeyString).
+		Str("keystore", cmd.Keystore).
+		Str("signer", cmd.Signer).
+		Str("scheme", cmd.Scheme).
 		Str("network_id", cmd.NetworkID).
 		Stringer("node", cmd.Node.Address()).
 		Msg("flags")
@@ -78,14 +86,10 @@ func (cmd *KeySignCommand) prepare(pctx context.Context) error {
 		return err
 	}
 
-	switch key, err := base.DecodePrivatekeyFromString(cmd.KeyString, cmd.Encoder); {
+	switch key, err := LoadPrivatekey(cmd.KeyString, cmd.Keystore, cmd.Signer, cmd.Encoder); {
 	case err != nil:
 		return err
 	default:
-		if err := key.IsValid(nil); err != nil {
-			return err
-		}
-
 		cmd.priv = key
 	}
 
@@ -118,7 +122,20 @@ func (cmd *KeySignCommand) loadBody() (interface{}, error) {
 
 	cmd.Log.Debug().Str("raw_body", string(body)).Msg("read body")
 
-	elem, err := cmd.Encoder.Decode(body)
+	ptr, err := decodeSignBody(cmd.Encoder, body)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd.Log.Debug().Str("body_type", fmt.Sprintf("%T", ptr)).Msg("body loaded")
+
+	return ptr, nil
+}
+
+// decodeSignBody decodes the body and returns the pointer of it, so the body
+// can be signed.
+func decodeSignBody(enc encoder.Encoder, body []byte) (interface{}, error) {
+	elem, err := enc.Decode(body)
 	if err != nil {
 		return nil, err
 	}
@@ -133,8 +150,6 @@ func (cmd *KeySignCommand) loadBody() (interface{}, error) {
 		return nil, err
 	}
 
-	cmd.Log.Debug().Str("body_type", fmt.Sprintf("%T", elem)).Msg("body loaded")
-
 	return ptr, nil
 }
 
@@ -175,17 +190,55 @@ func (cmd *KeySignCommand) updateToken(ptr interface{}) error {
 	return nil
 }
 
+type ethereumSigner interface {
+	base.Signer
+	EthereumSign(base.Privatekey, base.NetworkID, common.EthereumSignScheme) error
+}
+
 func (cmd *KeySignCommand) sign(ptr interface{}) error {
+	return signBody(ptr, cmd.priv, cmd.networkID, cmd.Node.Address(), cmd.Scheme)
+}
+
+// signBody signs the body by the scheme, "mitum", "eip191" or "eip712"; node
+// is needed for base.NodeSigner.
+func signBody(
+	ptr interface{}, priv base.Privatekey, networkID base.NetworkID, node base.Address, scheme string,
+) error {
 	var sign func() error
 
 	switch t := ptr.(type) {
 	case base.NodeSigner:
+		if scheme != "mitum" {
+			return errors.Errorf("%s scheme not supported for node sign", scheme)
+		}
+
 		sign = func() error {
-			return t.NodeSign(cmd.priv, cmd.networkID, cmd.Node.Address())
+			return t.NodeSign(priv, networkID, node)
+		}
+	case ethereumSigner:
+		if scheme == "mitum" {
+			sign = func() error {
+				return t.Sign(priv, networkID)
+			}
+
+			break
+		}
+
+		ethscheme := common.EthereumSignSchemeEIP191
+		if scheme == "eip712" {
+			ethscheme = common.EthereumSignSchemeEIP712
+		}
+
+		sign = func() error {
+			return t.EthereumSign(priv, networkID, ethscheme)
 		}
 	case base.Signer:
+		if scheme != "mitum" {
+			return errors.Errorf("%s scheme not supported, %T", scheme, ptr)
+		}
+
 		sign = func() error {
-			return t.Sign(cmd.priv, cmd.networkID)
+			return t.Sign(priv, networkID)
 		}
 	default:
 		return errors.Errorf("It's not Signer, %T", ptr)
@@ -196,7 +249,7 @@ func (cmd *KeySignCommand) sign(ptr interface{}) error {
 	}
 
 	if i, ok := ptr.(util.IsValider); ok {
-		if err := i.IsValid(cmd.networkID); err != nil {
+		if err := i.IsValid(networkID); err != nil {
 			return err
 		}
 	}
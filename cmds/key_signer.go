@@ -0,0 +1,98 @@
+package cmds
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types/signer"
+	"github.com/pkg/errors"
+)
+
+// KeySignerCommand serves the privatekey by the external signer protocol over
+// the unix socket. It keeps the privatekey in memory, so it is for testing the
+// --signer of the other commands; use the signing process of HSM in
+// production.
+type KeySignerCommand struct {
+	BaseCommand
+	KeyString string `arg:"" name:"privatekey" help:"privatekey string; \"-\" to load from --keystore or stdin"`
+	Socket    string `arg:"" name:"socket" help:"unix socket path"`
+	Keystore  string `name:"keystore" help:"keystore file to serve" type:"existingfile"`
+}
+
+func (cmd *KeySignerCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	priv, err := LoadPrivatekey(cmd.KeyString, cmd.Keystore, "", cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	f := filepath.Clean(cmd.Socket)
+
+	l, err := listenSignerSocket(f)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = os.Remove(f)
+	}()
+
+	ctx, stop := signal.NotifyContext(pctx, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cmd.Log.Info().Stringer("publickey", priv.Publickey()).Str("socket", f).Msg("signer started")
+
+	return signer.NewServer(priv, signer.DefaultTimeout).Serve(ctx, l)
+}
+
+// listenSignerSocket listens the unix socket, which only the owner can access.
+// The socket is created in the new directory of 0700 and chmod-ed before it is
+// linked to the path, so the other users can not connect to it in the meantime.
+func listenSignerSocket(f string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(f), ".signer-")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	tmp := filepath.Join(dir, "s")
+
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if ul, ok := l.(*net.UnixListener); ok {
+		// NOTE the temporary path is removed with the directory.
+		ul.SetUnlinkOnClose(false)
+	}
+
+	if err := os.Chmod(tmp, 0o600); err != nil {
+		_ = l.Close()
+
+		return nil, errors.WithStack(err)
+	}
+
+	// NOTE os.Link fails when the path already exists.
+	if err := os.Link(tmp, f); err != nil {
+		_ = l.Close()
+
+		if os.IsExist(err) {
+			return nil, errors.Errorf("socket already exists, %q", f)
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return l, nil
+}
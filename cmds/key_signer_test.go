@@ -0,0 +1,67 @@
+package cmds
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListenSignerSocket(t *testing.T) {
+	// NOTE the path of unix socket is limited about 100 bytes, so t.TempDir()
+	// can be too long.
+	dir, err := os.MkdirTemp("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	f := filepath.Join(dir, "s.sock")
+
+	l, err := listenSignerSocket(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	switch fi, err := os.Stat(f); {
+	case err != nil:
+		t.Fatal(err)
+	case fi.Mode()&os.ModeSocket == 0:
+		t.Fatalf("not socket, %v", fi.Mode())
+	case fi.Mode().Perm() != 0o600:
+		t.Fatalf("unexpected permission, %v", fi.Mode().Perm())
+	}
+
+	// NOTE the temporary directory is removed.
+	switch es, err := os.ReadDir(dir); {
+	case err != nil:
+		t.Fatal(err)
+	case len(es) != 1:
+		t.Fatalf("unexpected files, %d", len(es))
+	}
+
+	go func() {
+		if conn, err := l.Accept(); err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	conn, err := net.Dial("unix", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = conn.Close()
+
+	if _, err := listenSignerSocket(f); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected already exists error, but %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package cmds
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum-currency/v3/types/signer"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// KeystorePassphraseEnv is the environment variable of keystore passphrase;
+// without it, the passphrase is prompted.
+const KeystorePassphraseEnv = "MITUM_KEYSTORE_PASSPHRASE"
+
+// MnemonicPassphraseEnv is the environment variable of BIP-39 passphrase,
+// which is used when the passphrase flag is "-"; without it, the passphrase is
+// prompted.
+const MnemonicPassphraseEnv = "MITUM_MNEMONIC_PASSPHRASE"
+
+// LoadPrivatekey loads the privatekey string. With keystore file, the
+// privatekey string should be "-" and the privatekey is decrypted from the
+// keystore; with external signer, the privatekey string should be "-" and the
+// signs are made by the signer; otherwise "-" reads the privatekey string from
+// stdin.
+func LoadPrivatekey(s, keystore, signer string, enc encoder.Encoder) (base.Privatekey, error) {
+	isInput := strings.TrimSpace(s) == "-"
+
+	switch {
+	case len(keystore) > 0 && len(signer) > 0:
+		return nil, errors.Errorf("keystore and signer can not be used together")
+	case len(signer) > 0:
+		if !isInput {
+			return nil, errors.Errorf(`privatekey should be "-" with signer`)
+		}
+
+		return LoadSigner(signer, enc)
+	case len(keystore) > 0:
+		if !isInput {
+			return nil, errors.Errorf(`privatekey should be "-" with keystore`)
+		}
+
+		return LoadKeystore(keystore)
+	case isInput:
+		b, err := LoadFromStdInput()
+		if err != nil {
+			return nil, err
+		}
+
+		s = string(b)
+	}
+
+	switch k, err := base.DecodePrivatekeyFromString(s, enc); {
+	case err != nil:
+		return nil, errors.WithMessage(err, "invalid private key")
+	default:
+		if err := k.IsValid(nil); err != nil {
+			return nil, err
+		}
+
+		return k, nil
+	}
+}
+
+// LoadKeystore decrypts the keystore file with the passphrase.
+func LoadKeystore(f string) (types.MEPrivatekey, error) {
+	b, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return types.MEPrivatekey{}, errors.WithStack(err)
+	}
+
+	passphrase, err := loadKeystorePassphrase(false)
+	if err != nil {
+		return types.MEPrivatekey{}, err
+	}
+
+	return types.DecryptKeystore(b, passphrase)
+}
+
+// LoadSigner connects to the external signer and returns the privatekey, which
+// signs by the signer.
+func LoadSigner(address string, enc encoder.Encoder) (base.Privatekey, error) {
+	s, err := signer.NewExternalSigner(address, signer.DefaultTimeout, enc)
+	if err != nil {
+		return nil, err
+	}
+
+	return signer.NewPrivatekey(s), nil
+}
+
+func loadKeystorePassphrase(confirm bool) ([]byte, error) {
+	return loadPassphrase("keystore passphrase", KeystorePassphraseEnv, confirm)
+}
+
+// loadMnemonicPassphrase returns the BIP-39 passphrase; "-" loads it from the
+// environment variable or the prompt.
+func loadMnemonicPassphrase(s string, confirm bool) (string, error) {
+	if s != "-" {
+		return s, nil
+	}
+
+	b, err := loadPassphrase("mnemonic passphrase", MnemonicPassphraseEnv, confirm)
+
+	return string(b), err
+}
+
+// loadMnemonic returns the BIP-39 mnemonic; "-" reads it from stdin or, in
+// terminal, from the prompt.
+func loadMnemonic(s string) (string, error) {
+	if strings.TrimSpace(s) != "-" {
+		return s, nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		b, err := LoadFromStdInput()
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+
+		return string(b), nil
+	}
+
+	b, err := loadPassphrase("mnemonic", "", false)
+
+	return strings.TrimSpace(string(b)), err
+}
+
+// loadPassphrase loads the secret from the environment variable; without it,
+// the secret is prompted without echo.
+func loadPassphrase(name, env string, confirm bool) ([]byte, error) {
+	if len(env) > 0 {
+		if s, found := os.LookupEnv(env); found {
+			return []byte(s), nil
+		}
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		if len(env) < 1 {
+			return nil, errors.Errorf("%s not found; terminal required", name)
+		}
+
+		return nil, errors.Errorf("%s not found; set %s", name, env)
+	}
+
+	prompt := func(s string) ([]byte, error) {
+		_, _ = fmt.Fprint(os.Stderr, s)
+
+		defer func() {
+			_, _ = fmt.Fprintln(os.Stderr)
+		}()
+
+		b, err := term.ReadPassword(fd)
+
+		return b, errors.WithStack(err)
+	}
+
+	passphrase, err := prompt(name + ": ")
+	if err != nil {
+		return nil, err
+	}
+
+	if confirm {
+		switch b, err := prompt("repeat " + name + ": "); {
+		case err != nil:
+			return nil, err
+		case !bytes.Equal(passphrase, b):
+			return nil, errors.Errorf("%s not matched", name)
+		}
+	}
+
+	return passphrase, nil
+}
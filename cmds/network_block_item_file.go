@@ -20,7 +20,8 @@ import (
 
 type NetworkClientBlockItemFilesCommand struct { //nolint:govet //...
 	BaseNetworkClientCommand
-	Privatekey         string            `arg:"" name:"privatekey" help:"privatekey string"`
+	Privatekey         string            `arg:"" name:"privatekey" help:"privatekey string; \"-\" to load from --keystore or stdin"`
+	Keystore           string            `name:"keystore" help:"keystore file" type:"existingfile"`
 	Height             launch.HeightFlag `arg:""`
 	OutputDirectory    string            `arg:"" name:"output directory" default:""`
 	DownloadRemoteItem bool              `name:"download-remote-item"`
@@ -65,7 +66,7 @@ func (cmd *NetworkClientBlockItemFilesCommand) prepare(pctx context.Context) err
 		return err
 	}
 
-	switch i, err := launch.DecodePrivatekey(cmd.Privatekey, cmd.Encoders.JSON()); {
+	switch i, err := LoadPrivatekey(cmd.Privatekey, cmd.Keystore, "", cmd.Encoders.JSON()); {
 	case err != nil:
 		return err
 	default:
@@ -0,0 +1,82 @@
+package cmds
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+// OfflineCommand builds and signs the operation without node. The unsigned
+// operation is built to the file, the key holders append their signatures to
+// the file and the operation is finalized after the signatures are verified
+// by the account keys.
+type OfflineCommand struct {
+	//revive:disable:nested-structs
+	Build struct {
+		Transfer      OfflineTransferCommand      `cmd:"" name:"transfer" help:"build unsigned transfer"`
+		CreateAccount OfflineCreateAccountCommand `cmd:"" name:"create-account" help:"build unsigned create-account"`
+		UpdateKey     OfflineUpdateKeyCommand     `cmd:"" name:"update-key" help:"build unsigned update-key"`
+	} `cmd:"" help:"build unsigned operation"`
+	//revive:enable:nested-structs
+	Sign     OfflineSignCommand     `cmd:"" name:"sign" help:"append signature to operation file"`
+	Verify   OfflineVerifyCommand   `cmd:"" name:"verify" help:"verify signatures of operation file"`
+	Finalize OfflineFinalizeCommand `cmd:"" name:"finalize" help:"verify signatures and print operation to broadcast"`
+}
+
+type OfflineBuildFlags struct {
+	Token  string `help:"token for operation" optional:""`
+	Output string `name:"output" help:"operation file; without it, print to stdout"`
+}
+
+// loadOperationFile loads the operation from the operation file.
+func loadOperationFile(enc encoder.Encoder, f string) (interface{}, error) {
+	b, err := os.ReadFile(filepath.Clean(f))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ptr, err := decodeSignBody(enc, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := ptr.(base.Operation); !ok {
+		return nil, errors.Errorf("not operation, %T", ptr)
+	}
+
+	return ptr, nil
+}
+
+// writeOperationFile writes the operation to the operation file; with empty
+// file, the operation is printed to stdout.
+func writeOperationFile(cmd *BaseCommand, f string, v interface{}, overwrite bool) error {
+	b, err := util.MarshalJSONIndent(v)
+	if err != nil {
+		return err
+	}
+
+	if len(f) < 1 {
+		cmd.print(string(b))
+
+		return nil
+	}
+
+	mode := os.FileMode(0o600)
+
+	switch fi, err := os.Stat(f); {
+	case err == nil:
+		if !overwrite {
+			return errors.Errorf("operation file already exists, %q", f)
+		}
+
+		mode = fi.Mode()
+	case !os.IsNotExist(err):
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(os.WriteFile(filepath.Clean(f), append(b, '\n'), mode))
+}
@@ -0,0 +1,139 @@
+package cmds
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/pkg/errors"
+)
+
+type OfflineTransferCommand struct {
+	BaseCommand
+	OfflineBuildFlags
+	Sender         AddressFlag               `arg:"" name:"sender" help:"sender address" required:"true"`
+	ReceiverAmount AddressCurrencyAmountFlag `arg:"" name:"receiver-currency-amount" help:"receiver amount (ex: \"<address>,<currency>,<amount>\") separator @" required:"true"` //nolint:lll //...
+}
+
+func (cmd *OfflineTransferCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	sender, err := offlineSender(cmd.Sender, cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	fact, err := newTransferFact(cmd.OfflineBuildFlags.token(), sender, cmd.ReceiverAmount)
+	if err != nil {
+		return err
+	}
+
+	op, err := currency.NewTransfer(fact)
+	if err != nil {
+		return errors.Wrap(err, "create transfer operation")
+	}
+
+	return cmd.OfflineBuildFlags.write(&cmd.BaseCommand, &op)
+}
+
+type OfflineCreateAccountCommand struct {
+	BaseCommand
+	OfflineBuildFlags
+	Sender    AddressFlag        `arg:"" name:"sender" help:"sender address" required:"true"`
+	Threshold uint               `help:"threshold for keys (default: ${create_account_threshold})" default:"${create_account_threshold}"` // nolint
+	Key       KeyFlag            `name:"key" help:"key for new account (ex: \"<public key>,<weight>\") separator @"`
+	Amount    CurrencyAmountFlag `arg:"" name:"currency-amount" help:"amount (ex: \"<currency>,<amount>\")"`
+}
+
+func (cmd *OfflineCreateAccountCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	sender, err := offlineSender(cmd.Sender, cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	keys, err := cmd.Key.AccountKeys(cmd.Threshold)
+	if err != nil {
+		return err
+	}
+
+	fact, err := newCreateAccountFact(cmd.OfflineBuildFlags.token(), sender, keys, cmd.Amount)
+	if err != nil {
+		return err
+	}
+
+	op, err := currency.NewCreateAccount(fact)
+	if err != nil {
+		return errors.Wrap(err, "create create-account operation")
+	}
+
+	return cmd.OfflineBuildFlags.write(&cmd.BaseCommand, &op)
+}
+
+type OfflineUpdateKeyCommand struct {
+	BaseCommand
+	OfflineBuildFlags
+	Sender    AddressFlag    `arg:"" name:"sender" help:"sender address" required:"true"`
+	Threshold uint           `help:"threshold for keys (default: ${create_account_threshold})" default:"${create_account_threshold}"` // nolint
+	Key       KeyFlag        `name:"key" help:"new keys of account (ex: \"<public key>,<weight>\") separator @"`
+	Currency  CurrencyIDFlag `arg:"" name:"currency-id" help:"currency id" required:"true"`
+}
+
+func (cmd *OfflineUpdateKeyCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	sender, err := offlineSender(cmd.Sender, cmd.Encoder)
+	if err != nil {
+		return err
+	}
+
+	keys, err := cmd.Key.AccountKeys(cmd.Threshold)
+	if err != nil {
+		return err
+	}
+
+	op, err := currency.NewUpdateKey(
+		currency.NewUpdateKeyFact([]byte(cmd.OfflineBuildFlags.token()), sender, keys, cmd.Currency.CID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "create update-key operation")
+	}
+
+	return cmd.OfflineBuildFlags.write(&cmd.BaseCommand, &op)
+}
+
+func offlineSender(flag AddressFlag, enc encoder.Encoder) (base.Address, error) {
+	switch a, err := flag.Encode(enc); {
+	case err != nil:
+		return nil, errors.Wrapf(err, "invalid sender format, %v", flag.String())
+	case a == nil:
+		return nil, errors.Errorf("empty sender")
+	default:
+		return a, nil
+	}
+}
+
+func (flag OfflineBuildFlags) token() string {
+	if len(flag.Token) < 1 {
+		return localtime.Now().UTC().String()
+	}
+
+	return flag.Token
+}
+
+func (flag OfflineBuildFlags) write(cmd *BaseCommand, op base.Operation) error {
+	if err := op.Fact().IsValid(nil); err != nil {
+		return err
+	}
+
+	return writeOperationFile(cmd, flag.Output, op, false)
+}
@@ -0,0 +1,64 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/pkg/errors"
+)
+
+// OfflineSignCommand appends the signature to the operation file; the signature
+// of the same key is replaced.
+type OfflineSignCommand struct {
+	BaseCommand
+	KeyString string `arg:"" name:"privatekey" help:"privatekey string; \"-\" to load from --keystore, --signer or stdin"`
+	NetworkID string `arg:"" name:"network-id" help:"network-id"`
+	File      string `arg:"" name:"file" help:"operation file" type:"existingfile"`
+	Keystore  string `name:"keystore" help:"keystore file to sign" type:"existingfile"`
+	Signer    string `name:"signer" help:"local socket of external signer to sign"`
+	Scheme    string `name:"scheme" help:"sign scheme; mitum, eip191 or eip712" default:"mitum" enum:"mitum,eip191,eip712"` //nolint:lll //...
+	priv      base.Privatekey
+	networkID base.NetworkID
+}
+
+func (cmd *OfflineSignCommand) Run(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	switch key, err := LoadPrivatekey(cmd.KeyString, cmd.Keystore, cmd.Signer, cmd.Encoder); {
+	case err != nil:
+		return err
+	default:
+		cmd.priv = key
+	}
+
+	cmd.networkID = base.NetworkID([]byte(cmd.NetworkID))
+	if err := cmd.networkID.IsValid(nil); err != nil {
+		return err
+	}
+
+	ptr, err := loadOperationFile(cmd.Encoder, cmd.File)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ptr.(base.NodeSigner); ok {
+		return errors.Errorf("node operation not supported, %T", ptr)
+	}
+
+	if err := signBody(ptr, cmd.priv, cmd.networkID, nil, cmd.Scheme); err != nil {
+		return err
+	}
+
+	if err := writeOperationFile(&cmd.BaseCommand, cmd.File, ptr, true); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "signed by %s; %d signs\n",
+		cmd.priv.Publickey(), len(ptr.(base.Operation).Signs())) //nolint:forcetypeassert //...
+
+	return nil
+}
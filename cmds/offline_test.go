@@ -0,0 +1,239 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum-currency/v3/types/signer"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/alecthomas/kong"
+)
+
+type testOfflineCLI struct {
+	Offline OfflineCommand `cmd:""`
+}
+
+func runTestOfflineCommand(t *testing.T, args ...string) error {
+	t.Helper()
+
+	pctx := context.WithValue(context.Background(), launch.LoggingContextKey, logging.NewLogging(nil))
+
+	var cli testOfflineCLI
+
+	parser, err := kong.New(&cli,
+		kong.Vars{"create_account_threshold": "100"},
+		kong.BindTo(pctx, (*context.Context)(nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kctx, err := parser.Parse(append([]string{"offline"}, args...))
+	if err != nil {
+		return err
+	}
+
+	return kctx.Run()
+}
+
+func newTestOfflineKeys(t *testing.T, privs ...base.Privatekey) (types.AccountKeys, string) {
+	t.Helper()
+
+	ks := make([]types.AccountKey, len(privs))
+	fs := make([]string, len(privs))
+	weight := uint(100 / len(privs))
+
+	for i := range privs {
+		k, err := types.NewBaseAccountKey(privs[i].Publickey(), weight)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ks[i] = k
+		fs[i] = fmt.Sprintf("%s,%d", privs[i].Publickey(), weight)
+	}
+
+	keys, err := types.NewBaseAccountKeys(ks, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keys, strings.Join(fs, "@")
+}
+
+func startTestSigner(t *testing.T, priv base.Privatekey) string {
+	t.Helper()
+
+	// NOTE the path of unix socket is limited about 100 bytes, so t.TempDir()
+	// can be too long.
+	dir, err := os.MkdirTemp("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	f := filepath.Join(dir, "s.sock")
+
+	l, err := net.Listen("unix", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go func() {
+		_ = signer.NewServer(signer.NewMockSigner(priv), 0).Serve(ctx, l)
+	}()
+
+	return "unix://" + f
+}
+
+func TestOfflineTransfer(t *testing.T) {
+	networkID := "offline-test"
+
+	apriv, bpriv := types.NewMEPrivatekey(), types.NewMEPrivatekey()
+
+	keys, keyflag := newTestOfflineKeys(t, apriv, bpriv)
+
+	sender, err := types.NewAddressFromKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiver, err := types.NewAddressFromKeys(func() types.AccountKeys {
+		k, _ := newTestOfflineKeys(t, types.NewMEPrivatekey())
+
+		return k
+	}())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	f := filepath.Join(dir, "op.json")
+
+	if err := runTestOfflineCommand(t,
+		"build", "transfer", sender.String(), receiver.String()+",MCC,100", "--output", f,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(network string) error {
+		return runTestOfflineCommand(t, "verify", f, network, "--key", keyflag)
+	}
+
+	t.Run("build again", func(t *testing.T) {
+		if err := runTestOfflineCommand(t,
+			"build", "transfer", sender.String(), receiver.String()+",MCC,100", "--output", f,
+		); err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Fatalf("expected already exists error, but %v", err)
+		}
+	})
+
+	t.Run("verify unsigned", func(t *testing.T) {
+		if err := verify(networkID); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("sign by privatekey", func(t *testing.T) {
+		if err := runTestOfflineCommand(t, "sign", apriv.String(), networkID, f); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := verify(networkID); err == nil || !strings.Contains(err.Error(), "verify signs") {
+			t.Fatalf("expected threshold error, but %v", err)
+		}
+	})
+
+	t.Run("keystore and signer", func(t *testing.T) {
+		ks := filepath.Join(dir, "keystore.json")
+		if err := os.WriteFile(ks, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := runTestOfflineCommand(t,
+			"sign", "-", networkID, f, "--keystore", ks, "--signer", "unix:///not-found",
+		); err == nil || !strings.Contains(err.Error(), "can not be used together") {
+			t.Fatalf("expected error, but %v", err)
+		}
+	})
+
+	t.Run("sign by signer", func(t *testing.T) {
+		if err := runTestOfflineCommand(t,
+			"sign", "-", networkID, f, "--signer", startTestSigner(t, bpriv),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := verify(networkID); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("sign again", func(t *testing.T) {
+		if err := runTestOfflineCommand(t, "sign", apriv.String(), networkID, f); err != nil {
+			t.Fatal(err)
+		}
+
+		ptr, err := loadOperationFile(enc, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := len(ptr.(base.Operation).Signs()); n != 2 { //nolint:forcetypeassert //...
+			t.Fatalf("expected 2 signs, but %d", n)
+		}
+	})
+
+	t.Run("wrong network id", func(t *testing.T) {
+		if err := verify("wrong-network"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("unknown signer", func(t *testing.T) {
+		_, other := newTestOfflineKeys(t, apriv, types.NewMEPrivatekey())
+
+		if err := runTestOfflineCommand(t, "verify", f, networkID, "--key", other); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("finalize", func(t *testing.T) {
+		out := filepath.Join(dir, "final.json")
+
+		if err := runTestOfflineCommand(t,
+			"finalize", f, networkID, "--key", keyflag, "--output", out,
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		ptr, err := loadOperationFile(enc, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		op := ptr.(base.Operation) //nolint:forcetypeassert //...
+
+		if err := op.IsValid([]byte(networkID)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := types.CheckThreshold(op.Signs(), keys); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
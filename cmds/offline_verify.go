@@ -0,0 +1,99 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/pkg/errors"
+)
+
+// OfflineVerifyCommand verifies the signatures of the operation file and checks
+// the sum of the weights of signers passes the threshold of account keys.
+type OfflineVerifyCommand struct {
+	BaseCommand
+	File      string  `arg:"" name:"file" help:"operation file" type:"existingfile"`
+	NetworkID string  `arg:"" name:"network-id" help:"network-id"`
+	Threshold uint    `help:"threshold of sender account keys (default: ${create_account_threshold})" default:"${create_account_threshold}"` // nolint
+	Key       KeyFlag `name:"key" help:"key of sender account (ex: \"<public key>,<weight>\") separator @" required:"true"`
+	op        base.Operation
+}
+
+func (cmd *OfflineVerifyCommand) Run(pctx context.Context) error {
+	if err := cmd.verify(pctx); err != nil {
+		return err
+	}
+
+	cmd.print("verified; %d signs passed threshold, %d", len(cmd.op.Signs()), cmd.Threshold)
+
+	return nil
+}
+
+func (cmd *OfflineVerifyCommand) verify(pctx context.Context) error {
+	if _, err := cmd.prepare(pctx); err != nil {
+		return err
+	}
+
+	networkID := base.NetworkID([]byte(cmd.NetworkID))
+	if err := networkID.IsValid(nil); err != nil {
+		return err
+	}
+
+	keys, err := cmd.Key.AccountKeys(cmd.Threshold)
+	if err != nil {
+		return err
+	}
+
+	ptr, err := loadOperationFile(cmd.Encoder, cmd.File)
+	if err != nil {
+		return err
+	}
+
+	cmd.op = ptr.(base.Operation) //nolint:forcetypeassert //...
+
+	if err := cmd.op.IsValid(networkID); err != nil {
+		return err
+	}
+
+	signs := cmd.op.Signs()
+
+	var sum uint
+
+	for i := range signs {
+		if ky, found := keys.Key(signs[i].Signer()); found {
+			sum += ky.Weight()
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "signs=%d weight=%d threshold=%d\n", len(signs), sum, keys.Threshold())
+
+	if err := types.CheckThreshold(signs, keys); err != nil {
+		return errors.WithMessage(err, "verify signs")
+	}
+
+	return nil
+}
+
+// OfflineFinalizeCommand verifies the operation file like OfflineVerifyCommand
+// and prints the operation, which can be sent by "network client
+// send-operation".
+type OfflineFinalizeCommand struct {
+	OfflineVerifyCommand
+	Output string `name:"output" help:"output file; without it, print to stdout"`
+}
+
+func (cmd *OfflineFinalizeCommand) Run(pctx context.Context) error {
+	if err := cmd.verify(pctx); err != nil {
+		return err
+	}
+
+	if len(cmd.Output) < 1 {
+		PrettyPrint(cmd.Out, cmd.op)
+
+		return nil
+	}
+
+	return writeOperationFile(&cmd.BaseCommand, cmd.Output, cmd.op, false)
+}
@@ -42,7 +42,7 @@ func ProcessDigestAPI(ctx context.Context) (context.Context, error) {
 		return ctx, nil
 	}
 
-	var st *digest.Database
+	var st digest.Store
 	if err := mitumutil.LoadFromContextOK(ctx, digest.ContextValueDigestDatabase, &st); err != nil {
 		log.Log().Debug().Err(err).Msg("digest api disabled; empty database")
 
@@ -2,8 +2,11 @@ package cmds
 
 import (
 	"context"
+	"path/filepath"
+
 	"github.com/ProtoconNet/mitum-currency/v3/digest"
 	mongodbstorage "github.com/ProtoconNet/mitum-currency/v3/digest/mongodb"
+	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
 	"github.com/ProtoconNet/mitum2/launch"
@@ -27,6 +30,8 @@ func ProcessDatabase(ctx context.Context) (context.Context, error) {
 	switch {
 	case conf.URI().Scheme == "mongodb", conf.URI().Scheme == "mongodb+srv":
 		return processMongodbDatabase(ctx, l)
+	case conf.URI().Scheme == "leveldb":
+		return processLeveldbDatabase(ctx, l)
 	default:
 		return ctx, errors.Errorf("Unsupported database type, %v", conf.URI().Scheme)
 	}
@@ -80,6 +85,50 @@ func processMongodbDatabase(ctx context.Context, l digest.YamlDigestDesign) (con
 	return context.WithValue(ctx, digest.ContextValueDigestDatabase, dst), nil
 }
 
+// processLeveldbDatabase opens the embedded digest database. The relative path
+// of uri, like `leveldb://digest`, is under the storage base directory.
+func processLeveldbDatabase(ctx context.Context, l digest.YamlDigestDesign) (context.Context, error) {
+	u := l.Database().URI()
+
+	var encs *encoder.Encoders
+	if err := util.LoadFromContext(ctx, launch.EncodersContextKey, &encs); err != nil {
+		return ctx, err
+	}
+
+	enc, found := encs.Find(bsonenc.BSONEncoderHint)
+	if !found {
+		return ctx, util.ErrNotFound.Errorf("Unknown encoder hint, %q", bsonenc.BSONEncoderHint)
+	}
+
+	var design launch.NodeDesign
+	if err := util.LoadFromContextOK(ctx, launch.DesignContextKey, &design); err != nil {
+		return ctx, err
+	}
+
+	path := u.Host + u.Path
+	if len(path) < 1 {
+		return ctx, errors.Errorf("Empty leveldb path, %q", u.String())
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(design.Storage.Base, path)
+	}
+
+	dst, err := digest.NewLeveldbDatabase(path, encs, enc)
+	if err != nil {
+		return ctx, err
+	}
+
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(ctx, launch.LoggingContextKey, &log); err != nil {
+		return ctx, err
+	}
+
+	_ = dst.SetLogging(log)
+
+	return context.WithValue(ctx, digest.ContextValueDigestDatabase, dst), nil
+}
+
 func loadDigestDatabase(mst *isaacdatabase.Center, st *mongodbstorage.Database, readonly bool) (*digest.Database, error) {
 	var dst *digest.Database
 	if readonly {
@@ -96,6 +145,14 @@ func loadDigestDatabase(mst *isaacdatabase.Center, st *mongodbstorage.Database,
 		dst = s
 	}
 
+	// NOTE load the digest database activated by rebuild-digest.
+	switch i, err := dst.Active(); {
+	case err != nil:
+		return nil, err
+	default:
+		dst = i
+	}
+
 	if err := dst.Initialize(digest.DefaultIndexes); err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"github.com/ProtoconNet/mitum2/isaac"
 
 	"github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	"github.com/ProtoconNet/mitum2/base"
 	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
@@ -30,7 +31,7 @@ func ProcessDigester(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
-	var st *digest.Database
+	var st digest.Store
 	if err := util.LoadFromContext(ctx, digest.ContextValueDigestDatabase, &st); err != nil {
 		return ctx, err
 	}
@@ -67,6 +68,15 @@ func ProcessDigester(ctx context.Context) (context.Context, error) {
 	di := digest.NewDigester(st, root, sourceReaders, fromRemotes, design.NetworkID, vs.String(), nil)
 	_ = di.SetLogging(log)
 
+	var handlers *digest.Handlers
+	if err := util.LoadFromContext(ctx, digest.ContextValueDigestHandlers, &handlers); err != nil {
+		return ctx, err
+	}
+
+	if handlers != nil {
+		_ = handlers.SetDigester(di)
+	}
+
 	return context.WithValue(ctx, digest.ContextValueDigester, di), nil
 }
 
@@ -95,7 +105,7 @@ func PdigesterFollowUp(ctx context.Context) (context.Context, error) {
 		return ctx, err
 	}
 
-	var st *digest.Database
+	var st digest.Store
 	if err := util.LoadFromContext(ctx, digest.ContextValueDigestDatabase, &st); err != nil {
 		return ctx, err
 	}
@@ -103,6 +113,12 @@ func PdigesterFollowUp(ctx context.Context) (context.Context, error) {
 		return ctx, nil
 	}
 
+	setDigesterMetrics(mst, st)
+
+	if err := rollbackDivergedDigest(ctx, log, mst, st); err != nil {
+		return ctx, err
+	}
+
 	switch m, found, err := mst.LastBlockMap(); {
 	case err != nil:
 		return ctx, err
@@ -133,11 +149,24 @@ func digestFollowup(ctx context.Context, height base.Height) error {
 		return err
 	}
 
-	var st *digest.Database
+	var log *logging.Logging
+	var mst *isaacdatabase.Center
+	if err := util.LoadFromContextOK(ctx,
+		launch.LoggingContextKey, &log,
+		launch.CenterDatabaseContextKey, &mst,
+	); err != nil {
+		return err
+	}
+
+	var st digest.Store
 	if err := util.LoadFromContextOK(ctx, digest.ContextValueDigestDatabase, &st); err != nil {
 		return err
 	}
 
+	if err := rollbackDivergedDigest(ctx, log, mst, st); err != nil {
+		return err
+	}
+
 	var design launch.NodeDesign
 	if err := util.LoadFromContext(ctx, launch.DesignContextKey, &design); err != nil {
 		return err
@@ -172,14 +201,20 @@ func digestFollowup(ctx context.Context, height base.Height) error {
 		lastBlock = base.GenesisHeight
 	}
 
+	itemf := isaac.BlockItemReadersItemFuncWithRemote(sourceReaders, fromRemotes, nil)(ctx)
+
 	for h := lastBlock; h <= height; h++ {
 		var bm base.BlockMap
 
-		switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](sourceReaders.Item, h, base.BlockItemMap, nil); {
+		switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](itemf, h, base.BlockItemMap, nil); {
 		case err != nil:
 			return err
 		case !found:
-			continue
+			// NOTE LastBlock should not be advanced across the missed height;
+			// digester fills the gap later.
+			log.Log().Warn().Int64("height", h.Int64()).Msg("blockmap not found; stop to follow up")
+
+			return nil
 		default:
 			if err := i.IsValid(design.NetworkID); err != nil {
 				return err
@@ -188,7 +223,7 @@ func digestFollowup(ctx context.Context, height base.Height) error {
 			bm = i
 		}
 
-		pr, ops, sts, opsTree, _, _, err := isaacblock.LoadBlockItemsFromReader(bm, sourceReaders.Item, h)
+		pr, ops, sts, opsTree, _, _, err := isaacblock.LoadBlockItemsFromReader(bm, itemf, h)
 		if err != nil {
 			return err
 		}
@@ -204,3 +239,75 @@ func digestFollowup(ctx context.Context, height base.Height) error {
 	}
 	return nil
 }
+
+// rollbackDivergedDigest compares the digested manifests with the block maps
+// of node and rolls back the digest to the last matched height. The rolled
+// back blocks are digested again by follow up.
+func rollbackDivergedDigest(
+	ctx context.Context,
+	log *logging.Logging,
+	mst *isaacdatabase.Center,
+	st digest.Store,
+) error {
+	var last base.Height
+
+	switch m, found, err := mst.LastBlockMap(); {
+	case err != nil:
+		return err
+	case !found:
+		return nil
+	default:
+		last = m.Manifest().Height()
+	}
+
+	prev := st.LastBlock()
+
+	switch height, rolled, err := digest.RollbackDiverged(ctx, st, last, mst.BlockMap); {
+	case err != nil:
+		return err
+	case rolled:
+		log.Log().Warn().
+			Int64("last_block", prev.Int64()).
+			Int64("last_consistent", height.Int64()).
+			Msg("digest diverged from block maps; rolled back")
+	}
+
+	return nil
+}
+
+func setDigesterMetrics(mst *isaacdatabase.Center, st digest.Store) {
+	chainHeight := func() base.Height {
+		switch m, found, err := mst.LastBlockMap(); {
+		case err != nil, !found:
+			return base.NilHeight
+		default:
+			return m.Manifest().Height()
+		}
+	}
+
+	metrics.Default.SetGaugeFunc(
+		"mitum_chain_last_block_height",
+		"height of last block stored in node",
+		func() float64 {
+			return float64(chainHeight())
+		},
+	)
+	metrics.Default.SetGaugeFunc(
+		"mitum_digest_last_block_height",
+		"height of last digested block",
+		func() float64 {
+			return float64(st.LastBlock())
+		},
+	)
+	metrics.Default.SetGaugeFunc(
+		"mitum_digest_lag_blocks",
+		"number of blocks not yet digested",
+		func() float64 {
+			if lag := chainHeight() - st.LastBlock(); lag > 0 {
+				return float64(lag)
+			}
+
+			return 0
+		},
+	)
+}
@@ -210,6 +210,7 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 				limiterF,
 				nil,
 				policy.SuffrageCandidateLifespan(),
+				policy.SuffrageCandidateLimiterRule(),
 			)
 		})
 
@@ -241,6 +242,7 @@ func POperationProcessorsMap(pctx context.Context) (context.Context, error) {
 				getStatef,
 				nil,
 				nil,
+				policy.SuffrageCandidateLimiterRule(),
 			)
 		})
 
@@ -345,6 +347,33 @@ func PGenerateGenesis(pctx context.Context) (context.Context, error) {
 	)
 	_ = g.SetLogging(log)
 
+	var sts []base.State
+	if err := util.LoadFromContext(pctx, StateSnapshotContextKey, &sts); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if len(sts) > 0 {
+		_ = g.SetSnapshotStates(sts)
+
+		log.Log().Info().Int("states", len(sts)).Msg("snapshot states will be loaded into genesis")
+	}
+
+	var allocf string
+	if err := util.LoadFromContext(pctx, GenesisAllocationsFileContextKey, &allocf); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if len(allocf) > 0 {
+		allocs, err := LoadGenesisAllocations(allocf, encs.JSON())
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		_ = g.SetAllocations(allocs)
+
+		log.Log().Info().Int("allocations", len(allocs)).Msg("allocations will be loaded into genesis")
+	}
+
 	if _, err := g.Generate(); err != nil {
 		return pctx, e.Wrap(err)
 	}
@@ -634,6 +663,20 @@ func PSuffrageCandidateLimiterSet(pctx context.Context) (context.Context, error)
 		return pctx, e.Wrap(err)
 	}
 
+	if err := set.Add(
+		isaacoperation.BalanceSuffrageCandidateLimiterRuleHint,
+		base.SuffrageCandidateLimiterFunc(BalanceSuffrageCandidateLimiterFunc()),
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if err := set.Add(
+		isaacoperation.BondSuffrageCandidateLimiterRuleHint,
+		base.SuffrageCandidateLimiterFunc(BondSuffrageCandidateLimiterFunc()),
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
 	return context.WithValue(pctx, launch.SuffrageCandidateLimiterSetContextKey, set), nil
 }
 
@@ -650,6 +693,32 @@ func FixedSuffrageCandidateLimiterFunc() func(
 	}
 }
 
+func BalanceSuffrageCandidateLimiterFunc() func(
+	base.SuffrageCandidateLimiterRule,
+) (base.SuffrageCandidateLimiter, error) {
+	return func(rule base.SuffrageCandidateLimiterRule) (base.SuffrageCandidateLimiter, error) {
+		switch i, err := util.AssertInterfaceValue[isaacoperation.BalanceSuffrageCandidateLimiterRule](rule); {
+		case err != nil:
+			return nil, err
+		default:
+			return isaacoperation.NewBalanceSuffrageCandidateLimiter(i), nil
+		}
+	}
+}
+
+func BondSuffrageCandidateLimiterFunc() func(
+	base.SuffrageCandidateLimiterRule,
+) (base.SuffrageCandidateLimiter, error) {
+	return func(rule base.SuffrageCandidateLimiterRule) (base.SuffrageCandidateLimiter, error) {
+		switch i, err := util.AssertInterfaceValue[isaacoperation.BondSuffrageCandidateLimiterRule](rule); {
+		case err != nil:
+			return nil, err
+		default:
+			return isaacoperation.NewBondSuffrageCandidateLimiter(i), nil
+		}
+	}
+}
+
 func MajoritySuffrageCandidateLimiterFunc(
 	db isaac.Database,
 ) func(base.SuffrageCandidateLimiterRule) (base.SuffrageCandidateLimiter, error) {
@@ -0,0 +1,372 @@
+package cmds
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameRebuildDigest = ps.Name("rebuild-digest")
+
+// RebuildDigestCommand digests the blocks from the local block item files.
+// The digested height is saved as checkpoint after each block committed, so
+// the stopped rebuild is resumed from the next height of checkpoint. With
+// --swap, the database built by --database is activated; the running node keeps
+// the previous database until restarted.
+type RebuildDigestCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	HeightRange     launch.RangeFlag `name:"range" help:"<from>-<to>" default:""`
+	Workers         int64            `name:"workers" help:"number of blocks prepared in parallel" default:"8"`
+	Database        string           `name:"database" help:"build into the database of this name instead of the current"`
+	Swap            bool             `name:"swap" help:"activate the built database; the node uses it after restart"`
+	Clean           bool             `name:"clean" help:"clean the digest before rebuilding"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+	fromHeight      base.Height
+	toHeight        base.Height
+}
+
+func (cmd *RebuildDigestCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	cmd.fromHeight, cmd.toHeight = base.NilHeight, base.NilHeight
+
+	if h := cmd.HeightRange.From(); h != nil {
+		cmd.fromHeight = base.Height(*h)
+
+		if err := cmd.fromHeight.IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid from height; from=%d", *h)
+		}
+	}
+
+	if h := cmd.HeightRange.To(); h != nil {
+		cmd.toHeight = base.Height(*h)
+
+		if err := cmd.toHeight.IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid to height; to=%d", *h)
+		}
+
+		if cmd.fromHeight > cmd.toHeight {
+			return errors.Errorf("from height is higher than to; from=%d to=%d", cmd.fromHeight, cmd.toHeight)
+		}
+	}
+
+	switch {
+	case cmd.Workers < 1:
+		return errors.Errorf("workers should be over 0; workers=%d", cmd.Workers)
+	case cmd.Swap && len(cmd.Database) < 1:
+		return errors.Errorf("swap needs database")
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("from_height", cmd.fromHeight).
+		Interface("to_height", cmd.toHeight).
+		Int64("workers", cmd.Workers).
+		Str("database", cmd.Database).
+		Bool("swap", cmd.Swap).
+		Bool("clean", cmd.Clean).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := ps.NewPS("cmd-rebuild-digest")
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(PNameDigestDesign, PLoadDigestDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal).
+		AddOK(PNameMongoDBsDataBase, ProcessDatabase, nil, PNameDigestDesign, launch.PNameStorage).
+		AddOK(PNameRebuildDigest, cmd.pRebuildDigest, nil, PNameMongoDBsDataBase)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(launch.PNameLoadFromDatabase, launch.PLoadFromDatabase).
+		PostAddOK(launch.PNameCheckBlocksOfStorage, launch.PCheckBlocksOfStorage).
+		PostAddOK(launch.PNamePatchBlockItemReaders, launch.PPatchBlockItemReaders)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *RebuildDigestCommand) pRebuildDigest(pctx context.Context) (context.Context, error) {
+	e := util.StringError("rebuild digest")
+
+	var vs util.Version
+	var design launch.NodeDesign
+	var mst *isaacdatabase.Center
+	var newReaders func(context.Context, string, *isaac.BlockItemReadersArgs) (*isaac.BlockItemReaders, error)
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.VersionContextKey, &vs,
+		launch.DesignContextKey, &design,
+		launch.CenterDatabaseContextKey, &mst,
+		launch.NewBlockItemReadersFuncContextKey, &newReaders,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	var current digest.Store
+	if err := util.LoadFromContext(pctx, digest.ContextValueDigestDatabase, &current); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if current == nil {
+		return pctx, e.Errorf("digest database not found in design")
+	}
+
+	st := current
+
+	var mcurrent, target *digest.Database
+
+	if len(cmd.Database) > 0 {
+		i, ok := current.(*digest.Database)
+		if !ok {
+			return pctx, e.Errorf("database option is only for mongodb digest database, not %T", current)
+		}
+
+		mcurrent = i
+
+		if cmd.Database == mcurrent.Name() {
+			return pctx, e.Errorf("database, %q is already active", cmd.Database)
+		}
+
+		j, err := mcurrent.NewByName(cmd.Database)
+		if err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		if err := j.Initialize(digest.DefaultIndexes); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		target = j
+		st = j
+	}
+
+	readers, err := newReaders(pctx, launch.LocalFSDataDirectory(design.Storage.Base), nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	if err := cmd.checkHeights(mst, st); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	cmd.log.Debug().
+		Interface("from_height", cmd.fromHeight).
+		Interface("to_height", cmd.toHeight).
+		Interface("checkpoint", st.LastBlock()).
+		Msg("heights checked")
+
+	if cmd.fromHeight <= cmd.toHeight {
+		if err := cmd.rebuild(pctx, st, readers, design.NetworkID, vs.String()); err != nil {
+			return pctx, e.Wrap(err)
+		}
+	}
+
+	if cmd.Swap {
+		if err := mcurrent.SetActive(target); err != nil {
+			return pctx, e.Wrap(err)
+		}
+
+		cmd.log.Info().
+			Str("database", cmd.Database).
+			Str("previous", mcurrent.Name()).
+			Msg("digest database activated; restart node to use it and drop the previous database after")
+	}
+
+	return pctx, nil
+}
+
+// checkHeights decides the heights to rebuild. Without from height, the rebuild
+// starts from the next of checkpoint.
+func (cmd *RebuildDigestCommand) checkHeights(mst *isaacdatabase.Center, st digest.Store) error {
+	switch m, found, err := mst.LastBlockMap(); {
+	case err != nil:
+		return err
+	case !found:
+		return errors.Errorf("last blockmap not found")
+	case cmd.toHeight > m.Manifest().Height():
+		return errors.Errorf("to height higher than last; to=%d last=%d", cmd.toHeight, m.Manifest().Height())
+	case cmd.toHeight <= base.NilHeight:
+		cmd.toHeight = m.Manifest().Height()
+	}
+
+	switch {
+	case cmd.Clean:
+		if cmd.fromHeight < base.GenesisHeight {
+			cmd.fromHeight = base.GenesisHeight
+		}
+
+		return st.CleanByHeight(context.Background(), cmd.fromHeight)
+	case cmd.fromHeight <= base.NilHeight:
+		cmd.fromHeight = st.LastBlock() + 1
+		if cmd.fromHeight < base.GenesisHeight {
+			cmd.fromHeight = base.GenesisHeight
+		}
+
+		return nil
+	case cmd.fromHeight > st.LastBlock()+1:
+		return errors.Errorf("from height is higher than next of checkpoint; from=%d checkpoint=%d",
+			cmd.fromHeight, st.LastBlock())
+	default:
+		return st.CleanByHeight(context.Background(), cmd.fromHeight)
+	}
+}
+
+// rebuild prepares the blocks of each batch in parallel and commits them by
+// height order.
+func (cmd *RebuildDigestCommand) rebuild(
+	ctx context.Context,
+	st digest.Store,
+	readers *isaac.BlockItemReaders,
+	networkID base.NetworkID,
+	vs string,
+) error {
+	for from := cmd.fromHeight; from <= cmd.toHeight; from += base.Height(cmd.Workers) {
+		size := cmd.Workers
+		if to := from + base.Height(size) - 1; to > cmd.toHeight {
+			size = (cmd.toHeight - from + 1).Int64()
+		}
+
+		sessions := make([]digest.BlockSessioner, size)
+
+		if err := util.RunJobWorker(ctx, size, size, func(_ context.Context, i, _ uint64) error {
+			bs, err := prepareRebuildBlockSession(st, readers, from+base.Height(i), networkID, vs)
+			if err != nil {
+				return err
+			}
+
+			sessions[i] = bs
+
+			return nil
+		}); err != nil {
+			closeBlockSessions(sessions)
+
+			return err
+		}
+
+		for i := range sessions {
+			height := from + base.Height(i)
+
+			if err := sessions[i].Commit(ctx); err != nil {
+				closeBlockSessions(sessions[i+1:])
+
+				return errors.WithMessagef(err, "commit block session; height=%d", height)
+			}
+
+			if err := st.SetLastBlock(height); err != nil {
+				closeBlockSessions(sessions[i+1:])
+
+				return err
+			}
+
+			cmd.log.Debug().Interface("height", height).Msg("block digested")
+		}
+
+		cmd.log.Info().
+			Interface("to_height", cmd.toHeight).
+			Interface("checkpoint", from+base.Height(size)-1).
+			Msg("blocks digested")
+	}
+
+	return nil
+}
+
+func prepareRebuildBlockSession(
+	st digest.Store,
+	readers *isaac.BlockItemReaders,
+	height base.Height,
+	networkID base.NetworkID,
+	vs string,
+) (digest.BlockSessioner, error) {
+	var bm base.BlockMap
+
+	switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](readers.Item, height, base.BlockItemMap, nil); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, util.ErrNotFound.Errorf("blockmap; height=%d", height)
+	default:
+		if err := i.IsValid(networkID); err != nil {
+			return nil, err
+		}
+
+		bm = i
+	}
+
+	pr, ops, sts, opsTree, _, _, err := isaacblock.LoadBlockItemsFromReader(bm, readers.Item, height)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := st.NewBlockSession(bm, ops, opsTree, sts, pr, vs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bs.Prepare(); err != nil {
+		_ = bs.Close()
+
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+func closeBlockSessions(sessions []digest.BlockSessioner) {
+	for i := range sessions {
+		if sessions[i] != nil {
+			_ = sessions[i].Close()
+		}
+	}
+}
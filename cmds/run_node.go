@@ -3,6 +3,7 @@ package cmds
 import (
 	"context"
 	"github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	isaacstates "github.com/ProtoconNet/mitum2/isaac/states"
@@ -10,6 +11,7 @@ import (
 	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
 	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/logging"
 	"github.com/ProtoconNet/mitum2/util/ps"
 	"github.com/arl/statsviz"
@@ -31,6 +33,7 @@ type RunCommand struct { //nolint:govet //...
 	Discovery []launch.ConnInfoFlag `help:"member discovery" placeholder:"ConnInfo"`
 	Hold      launch.HeightFlag     `help:"hold consensus states"`
 	HTTPState string                `name:"http-state" help:"runtime statistics thru https" placeholder:"bind address"`
+	Signer    string                `name:"signer" help:"local socket of external signer for node privatekey"`
 	launch.ACLFlags
 	exitf  func(error)
 	log    *zerolog.Logger
@@ -50,6 +53,7 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 		Interface("discovery", cmd.Discovery).
 		Interface("hold", cmd.Hold).
 		Interface("http_state", cmd.HTTPState).
+		Interface("signer", cmd.Signer).
 		Interface("dev", cmd.DevFlags).
 		Interface("acl", cmd.ACLFlags).
 		Msg("flags")
@@ -75,6 +79,11 @@ func (cmd *RunCommand) Run(pctx context.Context) error {
 	_ = pps.AddOK(PNameDigester, ProcessDigester, nil, PNameMongoDBsDataBase).
 		AddOK(PNameStartDigester, ProcessStartDigester, nil, PNameDigestStart)
 	_ = pps.POK(launch.PNameStorage).PostAddOK(ps.Name("check-hold"), cmd.pCheckHold)
+
+	if len(cmd.Signer) > 0 {
+		_ = pps.POK(launch.PNameDesign).PostAddOK(PNameDesignSigner, cmd.pDesignSigner)
+	}
+
 	_ = pps.POK(launch.PNameStates).
 		PreAddOK(ps.Name("when-new-block-saved-in-consensus-state-func"), cmd.pWhenNewBlockSavedInConsensusStateFunc).
 		PreAddOK(ps.Name("when-new-block-confirmed-func"), cmd.pWhenNewBlockConfirmed).
@@ -352,6 +361,38 @@ func (cmd *RunCommand) pCheckHold(pctx context.Context) (context.Context, error)
 	return pctx, nil
 }
 
+// pDesignSigner replaces the privatekey of node design by the external signer;
+// the consensus and node operations are signed by the signer.
+func (cmd *RunCommand) pDesignSigner(pctx context.Context) (context.Context, error) {
+	var encs *encoder.Encoders
+	var design launch.NodeDesign
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.EncodersContextKey, &encs,
+		launch.DesignContextKey, &design,
+	); err != nil {
+		return pctx, err
+	}
+
+	priv, err := LoadSigner(cmd.Signer, encs.JSON())
+	if err != nil {
+		return pctx, errors.WithMessage(err, "load signer")
+	}
+
+	// NOTE the node is known by the publickey of design privatekey, so the
+	// signer should have the same publickey.
+	if design.Privatekey != nil && !design.Privatekey.Publickey().Equal(priv.Publickey()) {
+		return pctx, errors.Errorf("signer publickey, %q not matched with design privatekey publickey, %q",
+			priv.Publickey(), design.Privatekey.Publickey())
+	}
+
+	cmd.log.Debug().Interface("publickey", priv.Publickey()).Msg("privatekey loaded from signer")
+
+	design.Privatekey = priv
+
+	return context.WithValue(pctx, launch.DesignContextKey, design), nil
+}
+
 func (cmd *RunCommand) runHTTPState(bind string) error {
 	addr, err := net.ResolveTCPAddr("tcp", bind)
 	if err != nil {
@@ -363,6 +404,8 @@ func (cmd *RunCommand) runHTTPState(bind string) error {
 		return errors.Wrap(err, "register statsviz for http-state")
 	}
 
+	m.Handle("/metrics", metrics.Default.Handler())
+
 	cmd.log.Debug().Stringer("bind", addr).Msg("statsviz started")
 
 	go func() {
@@ -417,7 +460,7 @@ func (cmd *RunCommand) pDigestAPIHandlers(ctx context.Context) (context.Context,
 		return ctx, err
 	}
 
-	return ctx, nil
+	return context.WithValue(ctx, digest.ContextValueDigestHandlers, handlers), nil
 }
 
 func (cmd *RunCommand) loadCache(_ context.Context, design digest.YamlDigestDesign) (digest.Cache, error) {
@@ -438,7 +481,7 @@ func (cmd *RunCommand) setDigestDefaultHandlers(
 	router *mux.Router,
 	queue chan digest.RequestWrapper,
 ) (*digest.Handlers, error) {
-	var st *digest.Database
+	var st digest.Store
 	if err := util.LoadFromContext(ctx, digest.ContextValueDigestDatabase, &st); err != nil {
 		return nil, err
 	}
@@ -447,7 +490,15 @@ func (cmd *RunCommand) setDigestDefaultHandlers(
 		return nil, err
 	}
 
-	handlers := digest.NewHandlers(ctx, params.ISAAC.NetworkID(), encs, enc, st, cache, router, queue)
+	handlers := digest.NewHandlers(ctx, params.ISAAC.NetworkID(), encs, enc, st, cache, router, queue).
+		SetSupplyConfig(design.Supply()).
+		SetJSONRPCConfig(design.JSONRPC())
+
+	if rl := design.RateLimit(); !rl.IsEmpty() {
+		handlers = handlers.SetRateLimiter(digest.NewAPIRateLimiter(rl))
+
+		cmd.log.Debug().Msg("digest api rate limiter attached")
+	}
 
 	h, err := cmd.setDigestNetworkClient(ctx, params, handlers)
 	if err != nil {
@@ -0,0 +1,323 @@
+package cmds
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/state"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/state/extension"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+)
+
+var (
+	StateSnapshotVersion                     = util.MustNewVersion("v0.0.1")
+	StateSnapshotContextKey  util.ContextKey = "state-snapshot"
+	maxStateSnapshotLineSize                 = 1 << 26 //nolint:gomnd //...
+)
+
+// StateSnapshotHeader is the first line of state snapshot file. Hash is the
+// sha256 of the encoded states, which follow the header line by line.
+type StateSnapshotHeader struct {
+	Version   util.Version   `json:"version"`
+	NetworkID base.NetworkID `json:"network_id"`
+	Height    base.Height    `json:"height"`
+	Count     uint64         `json:"count"`
+	Hash      util.Hash      `json:"hash"`
+}
+
+type stateSnapshotHeaderJSONUnmarshaler struct {
+	Version   util.Version          `json:"version"`
+	NetworkID base.NetworkID        `json:"network_id"`
+	Height    base.Height           `json:"height"`
+	Count     uint64                `json:"count"`
+	Hash      valuehash.HashDecoder `json:"hash"`
+}
+
+// IsStateSnapshotKey selects the states of snapshot; accounts, balances,
+// suffrage bonds, contract accounts, currency designs and network policy.
+func IsStateSnapshotKey(key string) bool {
+	switch {
+	case statecurrency.IsAccountStateKey(key),
+		statecurrency.IsBalanceStateKey(key),
+		statecurrency.IsDesignStateKey(key),
+		statecurrency.IsSuffrageBondStateKey(key),
+		extension.IsStateContractAccountKey(key),
+		key == isaac.NetworkPolicyStateKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// WriteStateSnapshot writes the states into the gzip compressed file. The
+// states are sorted by key.
+func WriteStateSnapshot(
+	f string,
+	enc encoder.Encoder,
+	networkID base.NetworkID,
+	height base.Height,
+	sts []base.State,
+) (StateSnapshotHeader, error) {
+	e := util.StringError("write state snapshot")
+
+	sort.Slice(sts, func(i, j int) bool {
+		return strings.Compare(sts[i].Key(), sts[j].Key()) < 0
+	})
+
+	lines := make([][]byte, len(sts))
+	hashbuf := bytes.NewBuffer(nil)
+
+	for i := range sts {
+		b, err := enc.Marshal(sts[i])
+		if err != nil {
+			return StateSnapshotHeader{}, e.WithMessage(err, "state, %q", sts[i].Key())
+		}
+
+		lines[i] = b
+		_, _ = hashbuf.Write(b)
+	}
+
+	header := StateSnapshotHeader{
+		Version:   StateSnapshotVersion,
+		NetworkID: networkID,
+		Height:    height,
+		Count:     uint64(len(sts)),
+		Hash:      valuehash.NewSHA256(hashbuf.Bytes()),
+	}
+
+	hb, err := util.MarshalJSON(header)
+	if err != nil {
+		return StateSnapshotHeader{}, e.Wrap(err)
+	}
+
+	out, err := os.OpenFile(f, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return StateSnapshotHeader{}, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	gw := gzip.NewWriter(out)
+
+	for _, b := range append([][]byte{hb}, lines...) {
+		if _, err := gw.Write(append(b, '\n')); err != nil {
+			return StateSnapshotHeader{}, e.Wrap(err)
+		}
+	}
+
+	if err := gw.Close(); err != nil {
+		return StateSnapshotHeader{}, e.Wrap(err)
+	}
+
+	return header, nil
+}
+
+// ReadStateSnapshot reads the state snapshot file and checks the version,
+// count and hash of states.
+func ReadStateSnapshot(f string, encs *encoder.Encoders) (StateSnapshotHeader, []base.State, error) {
+	e := util.StringError("read state snapshot")
+
+	in, err := os.Open(f)
+	if err != nil {
+		return StateSnapshotHeader{}, nil, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		return StateSnapshotHeader{}, nil, e.Wrap(err)
+	}
+
+	defer func() {
+		_ = gr.Close()
+	}()
+
+	header, sts, err := readStateSnapshot(gr, encs)
+	if err != nil {
+		return header, nil, e.Wrap(err)
+	}
+
+	return header, sts, nil
+}
+
+func readStateSnapshot(r io.Reader, encs *encoder.Encoders) (StateSnapshotHeader, []base.State, error) {
+	var header StateSnapshotHeader
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxStateSnapshotLineSize)
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return header, nil, err
+		}
+
+		return header, nil, errors.Errorf("empty state snapshot")
+	}
+
+	var uh stateSnapshotHeaderJSONUnmarshaler
+	if err := util.UnmarshalJSON(sc.Bytes(), &uh); err != nil {
+		return header, nil, errors.WithMessage(err, "header")
+	}
+
+	header = StateSnapshotHeader{
+		Version:   uh.Version,
+		NetworkID: uh.NetworkID,
+		Height:    uh.Height,
+		Count:     uh.Count,
+		Hash:      uh.Hash.Hash(),
+	}
+
+	if header.Version.Major() != StateSnapshotVersion.Major() {
+		return header, nil, errors.Errorf("unsupported state snapshot version, %q", header.Version)
+	}
+
+	hashbuf := bytes.NewBuffer(nil)
+	sts := make([]base.State, 0, header.Count)
+
+	for sc.Scan() {
+		b := sc.Bytes()
+		if len(b) < 1 {
+			continue
+		}
+
+		_, _ = hashbuf.Write(b)
+
+		var st base.State
+		if err := encoder.Decode(encs.JSON(), b, &st); err != nil {
+			return header, nil, errors.WithMessagef(err, "state, %d", len(sts))
+		}
+
+		if !IsStateSnapshotKey(st.Key()) {
+			return header, nil, errors.Errorf("unknown state in snapshot, %q", st.Key())
+		}
+
+		sts = append(sts, st)
+	}
+
+	if err := sc.Err(); err != nil {
+		return header, nil, err
+	}
+
+	switch {
+	case uint64(len(sts)) != header.Count:
+		return header, nil, errors.Errorf("states count not matched; header=%d states=%d", header.Count, len(sts))
+	case header.Hash == nil || !header.Hash.Equal(valuehash.NewSHA256(hashbuf.Bytes())):
+		return header, nil, errors.Errorf("states hash not matched")
+	}
+
+	return header, sts, nil
+}
+
+// snapshotStateMergeValues returns the merge values of snapshot states. The
+// states of snapshot replace the same states of genesis operations, and the
+// genesis balances of currency in snapshot are ignored.
+func snapshotStateMergeValues(sts []base.State) ([]base.StateMergeValue, func(string) bool) {
+	values := make([]base.StateMergeValue, len(sts))
+	keys := map[string]struct{}{}
+
+	var currencies []string
+
+	for i := range sts {
+		values[i] = state.NewStateMergeValue(sts[i].Key(), sts[i].Value())
+		keys[sts[i].Key()] = struct{}{}
+
+		if statecurrency.IsDesignStateKey(sts[i].Key()) {
+			currencies = append(currencies, strings.TrimPrefix(sts[i].Key(), statecurrency.DesignStateKeyPrefix))
+		}
+	}
+
+	return values, func(key string) bool {
+		if _, found := keys[key]; found {
+			return true
+		}
+
+		if !statecurrency.IsBalanceStateKey(key) {
+			return false
+		}
+
+		for i := range currencies {
+			if strings.HasSuffix(key, "-"+currencies[i]+statecurrency.BalanceStateKeySuffix) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// genesisStatesWriter pre-loads the states into genesis block with the states
+// of RegisterGenesisCurrency. The states of RegisterGenesisCurrency are
+// dropped when isReplaced returns true.
+type genesisStatesWriter struct {
+	isaac.BlockWriter
+	isReplaced func(string) bool
+	values     []base.StateMergeValue
+	sync.Mutex
+}
+
+func newGenesisStatesWriter(
+	w isaac.BlockWriter, values []base.StateMergeValue, isReplaced func(string) bool,
+) *genesisStatesWriter {
+	return &genesisStatesWriter{
+		BlockWriter: w,
+		values:      values,
+		isReplaced:  isReplaced,
+	}
+}
+
+func (w *genesisStatesWriter) SetStates(
+	ctx context.Context, index uint64, values []base.StateMergeValue, op base.Operation,
+) error {
+	w.Lock()
+	defer w.Unlock()
+
+	var nvalues []base.StateMergeValue
+
+	for i := range values {
+		if w.isReplaced(values[i].Key()) {
+			continue
+		}
+
+		nvalues = append(nvalues, values[i])
+	}
+
+	if ht, ok := op.Fact().(hint.Hinter); ok && ht.Hint().IsCompatible(currency.RegisterGenesisCurrencyFactHint) {
+		nvalues = append(nvalues, w.values...)
+
+		w.values = nil
+	}
+
+	return w.BlockWriter.SetStates(ctx, index, nvalues, op)
+}
+
+func (w *genesisStatesWriter) Manifest(ctx context.Context, previous base.Manifest) (base.Manifest, error) {
+	w.Lock()
+	n := len(w.values)
+	w.Unlock()
+
+	if n > 0 {
+		return nil, errors.Errorf("genesis states not loaded; RegisterGenesisCurrency operation not found")
+	}
+
+	return w.BlockWriter.Manifest(ctx, previous)
+}
@@ -8,4 +8,8 @@ type Storage struct { //nolint:govet //...
 	ValidateBlocks ValidateBlocksCommand          `cmd:"" help:"validate blocks in storage"`
 	Status         launchcmd.StorageStatusCommand `cmd:"" help:"storage status"`
 	Database       launchcmd.DatabaseCommand      `cmd:"" help:""`
+	RebuildDigest  RebuildDigestCommand           `cmd:"" help:"rebuild digest from block data files"`
+	AuditSupply    SupplyAuditCommand             `cmd:"" help:"reconcile balances with total supply of currencies"`
+	ExportState    ExportStateCommand             `cmd:"" help:"export states at height into state snapshot"`
+	ImportState    ImportStateCommand             `cmd:"" help:"init node with the states of state snapshot"`
 }
@@ -0,0 +1,183 @@
+package cmds
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/isaac"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/ps"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+var PNameSupplyAudit = ps.Name("supply-audit")
+
+// SupplyAuditCommand replays the states of blocks from genesis to the height
+// and compares the sum of balances of each currency with the total supply of
+// currency design. It fails when any currency is not balanced, so it can be
+// used in CI against the test chain.
+type SupplyAuditCommand struct { //nolint:govet //...
+	launch.DesignFlag
+	launch.PrivatekeyFlags
+	Height          launch.HeightFlag `name:"height" help:"audit the state at this height; default is last"`
+	Currency        []string          `name:"currency" help:"currency id to audit; default is all"`
+	log             *zerolog.Logger
+	launch.DevFlags `embed:"" prefix:"dev."`
+}
+
+func (cmd *SupplyAuditCommand) Run(pctx context.Context) error {
+	var log *logging.Logging
+	if err := util.LoadFromContextOK(pctx, launch.LoggingContextKey, &log); err != nil {
+		return err
+	}
+
+	for i := range cmd.Currency {
+		if err := types.CurrencyID(cmd.Currency[i]).IsValid(nil); err != nil {
+			return errors.WithMessagef(err, "invalid currency id, %q", cmd.Currency[i])
+		}
+	}
+
+	log.Log().Debug().
+		Interface("design", cmd.DesignFlag).
+		Interface("privatekey", cmd.PrivatekeyFlags).
+		Interface("dev", cmd.DevFlags).
+		Interface("height", cmd.Height).
+		Strs("currency", cmd.Currency).
+		Msg("flags")
+
+	cmd.log = log.Log()
+
+	nctx := util.ContextWithValues(pctx, map[util.ContextKey]interface{}{
+		launch.DesignFlagContextKey: cmd.DesignFlag,
+		launch.DevFlagsContextKey:   cmd.DevFlags,
+		launch.PrivatekeyContextKey: string(cmd.PrivatekeyFlags.Flag.Body()),
+	})
+
+	pps := ps.NewPS("cmd-supply-audit")
+	_ = pps.SetLogging(log)
+
+	_ = pps.
+		AddOK(launch.PNameEncoder, PEncoder, nil).
+		AddOK(launch.PNameDesign, launch.PLoadDesign, nil, launch.PNameEncoder).
+		AddOK(launch.PNameLocal, launch.PLocal, nil, launch.PNameDesign).
+		AddOK(launch.PNameBlockItemReaders, launch.PBlockItemReaders, nil, launch.PNameDesign).
+		AddOK(launch.PNameStorage, launch.PStorage, launch.PCloseStorage, launch.PNameLocal).
+		AddOK(PNameSupplyAudit, cmd.pSupplyAudit, nil, launch.PNameStorage)
+
+	_ = pps.POK(launch.PNameEncoder).
+		PostAddOK(launch.PNameAddHinters, PAddHinters)
+
+	_ = pps.POK(launch.PNameDesign).
+		PostAddOK(launch.PNameCheckDesign, launch.PCheckDesign)
+
+	_ = pps.POK(launch.PNameBlockItemReaders).
+		PreAddOK(launch.PNameBlockItemReadersDecompressFunc, launch.PBlockItemReadersDecompressFunc).
+		PostAddOK(launch.PNameRemotesBlockItemReaderFunc, launch.PRemotesBlockItemReaderFunc)
+
+	_ = pps.POK(launch.PNameStorage).
+		PreAddOK(launch.PNameCheckLocalFS, launch.PCheckLocalFS).
+		PreAddOK(launch.PNameLoadDatabase, launch.PLoadDatabase).
+		PostAddOK(launch.PNameCheckLeveldbStorage, launch.PCheckLeveldbStorage).
+		PostAddOK(launch.PNameLoadFromDatabase, launch.PLoadFromDatabase).
+		PostAddOK(launch.PNameCheckBlocksOfStorage, launch.PCheckBlocksOfStorage).
+		PostAddOK(launch.PNamePatchBlockItemReaders, launch.PPatchBlockItemReaders)
+
+	cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process ready")
+
+	nctx, err := pps.Run(nctx)
+	defer func() {
+		cmd.log.Debug().Interface("process", pps.Verbose()).Msg("process will be closed")
+
+		if _, err = pps.Close(nctx); err != nil {
+			cmd.log.Error().Err(err).Msg("failed to close")
+		}
+	}()
+
+	return err
+}
+
+func (cmd *SupplyAuditCommand) pSupplyAudit(pctx context.Context) (context.Context, error) {
+	e := util.StringError("supply audit")
+
+	var design launch.NodeDesign
+	var db isaac.Database
+	var newReaders func(context.Context, string, *isaac.BlockItemReadersArgs) (*isaac.BlockItemReaders, error)
+
+	if err := util.LoadFromContextOK(pctx,
+		launch.DesignContextKey, &design,
+		launch.CenterDatabaseContextKey, &db,
+		launch.NewBlockItemReadersFuncContextKey, &newReaders,
+	); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	height, err := lastHeightFromFlag(db, cmd.Height)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	readers, err := newReaders(pctx, launch.LocalFSDataDirectory(design.Storage.Base), nil)
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	audit := currency.NewSupplyAudit()
+
+	if err := replayStates(readers, height, cmd.log, audit.AddState); err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	results := cmd.filter(audit.Results())
+
+	b, err := util.MarshalJSONIndent(map[string]interface{}{
+		"height":     height,
+		"currencies": results,
+	})
+	if err != nil {
+		return pctx, e.Wrap(err)
+	}
+
+	_, _ = fmt.Fprintln(os.Stdout, string(b))
+
+	var unbalanced []string
+
+	for i := range results {
+		if !results[i].IsBalanced() {
+			unbalanced = append(unbalanced, results[i].Currency.String())
+		}
+	}
+
+	if len(unbalanced) > 0 {
+		return pctx, e.Errorf("supply not balanced; height=%d currencies=%v", height, unbalanced)
+	}
+
+	cmd.log.Info().Interface("height", height).Int("currencies", len(results)).Msg("supply balanced")
+
+	return pctx, nil
+}
+
+func (cmd *SupplyAuditCommand) filter(results []currency.SupplyAuditResult) []currency.SupplyAuditResult {
+	if len(cmd.Currency) < 1 {
+		return results
+	}
+
+	var l []currency.SupplyAuditResult
+
+	for i := range results {
+		for j := range cmd.Currency {
+			if results[i].Currency.String() == cmd.Currency[j] {
+				l = append(l, results[i])
+
+				break
+			}
+		}
+	}
+
+	return l
+}
@@ -56,17 +56,11 @@ func (cmd *TransferCommand) parseFlags() error {
 }
 
 func (cmd *TransferCommand) createOperation() (base.Operation, error) { // nolint:dupl
-	var items []currency.TransferItem
-	for i := range cmd.ReceiverAmount.Address() {
-		item := currency.NewTransferItemMultiAmounts(cmd.ReceiverAmount.Address()[i], []types.Amount{cmd.ReceiverAmount.Amount()[i]})
-		if err := item.IsValid(nil); err != nil {
-			return nil, err
-		}
-		items = append(items, item)
+	fact, err := newTransferFact(cmd.Token, cmd.sender, cmd.ReceiverAmount)
+	if err != nil {
+		return nil, err
 	}
 
-	fact := currency.NewTransferFact([]byte(cmd.Token), cmd.sender, items)
-
 	op, err := currency.NewTransfer(fact)
 	if err != nil {
 		return nil, errors.Wrap(err, "create transfer operation")
@@ -83,3 +77,18 @@ func (cmd *TransferCommand) createOperation() (base.Operation, error) { // nolin
 
 	return op, nil
 }
+
+func newTransferFact(
+	token string, sender base.Address, receiverAmount AddressCurrencyAmountFlag,
+) (currency.TransferFact, error) {
+	var items []currency.TransferItem
+	for i := range receiverAmount.Address() {
+		item := currency.NewTransferItemMultiAmounts(receiverAmount.Address()[i], []types.Amount{receiverAmount.Amount()[i]})
+		if err := item.IsValid(nil); err != nil {
+			return currency.TransferFact{}, err
+		}
+		items = append(items, item)
+	}
+
+	return currency.NewTransferFact([]byte(token), sender, items), nil
+}
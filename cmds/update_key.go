@@ -54,19 +54,10 @@ func (cmd *UpdateKeyCommand) parseFlags() error {
 	}
 	cmd.sender = a
 
-	{
-		ks := make([]types.AccountKey, len(cmd.Key.Values))
-		for i := range cmd.Key.Values {
-			ks[i] = cmd.Key.Values[i]
-		}
-
-		if kys, err := types.NewBaseAccountKeys(ks, cmd.Threshold); err != nil {
-			return err
-		} else if err := kys.IsValid(nil); err != nil {
-			return err
-		} else {
-			cmd.keys = kys
-		}
+	if kys, err := cmd.Key.AccountKeys(cmd.Threshold); err != nil {
+		return err
+	} else {
+		cmd.keys = kys
 	}
 
 	return nil
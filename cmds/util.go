@@ -25,6 +25,7 @@ var (
 	PNameDigestDesign                   = ps.Name("digest-design")
 	PNameGenerateGenesis                = ps.Name("mitum-currency-generate-genesis")
 	PNameDigestAPIHandlers              = ps.Name("mitum-currency-digest-api-handlers")
+	PNameDesignSigner                   = ps.Name("mitum-currency-design-signer")
 	PNameDigesterFollowUp               = ps.Name("mitum-currency-followup_digester")
 	BEncoderContextKey                  = util.ContextKey("bson-encoder")
 	ProposalOperationFactHintContextKey = util.ContextKey("proposal-operation-fact-hint")
@@ -113,24 +113,62 @@ func (op *BaseOperation) Sign(priv base.Privatekey, networkID base.NetworkID) er
 	return nil
 }
 
-func (op *BaseOperation) sign(priv base.Privatekey, networkID base.NetworkID) (found int, sign base.BaseSign, _ error) {
-	e := util.StringError("sign BaseOperation")
+// EthereumSign signs the fact by the ethereum sign scheme instead of the raw
+// fact hash.
+func (op *BaseOperation) EthereumSign(
+	priv base.Privatekey, networkID base.NetworkID, scheme EthereumSignScheme,
+) error {
+	sign, err := NewEthereumSign(priv, networkID, op.fact, scheme)
+	if err != nil {
+		return err
+	}
+
+	if index := op.signIndex(priv.Publickey()); index < 0 {
+		op.signs = append(op.signs, sign)
+	} else {
+		op.signs[index] = sign
+	}
+
+	op.h = op.hash()
 
-	found = -1
+	return nil
+}
 
+// SetSigns replaces the signs; it is for collecting the signs of the same fact,
+// which are signed by the different signers.
+func (op *BaseOperation) SetSigns(signs []base.Sign) error {
+	for i := range signs {
+		if signs[i] == nil {
+			return ErrSignInvalid.Wrap(errors.Errorf("empty sign"))
+		}
+	}
+
+	op.signs = signs
+	op.h = op.hash()
+
+	return nil
+}
+
+func (op *BaseOperation) signIndex(pub base.Publickey) int {
 	for i := range op.signs {
 		s := op.signs[i]
 		if s == nil {
 			continue
 		}
 
-		if s.Signer().Equal(priv.Publickey()) {
-			found = i
-
-			break
+		if s.Signer().Equal(pub) {
+			return i
 		}
 	}
 
+	return -1
+}
+
+func (op *BaseOperation) sign(priv base.Privatekey, networkID base.NetworkID) (found int, sign base.BaseSign, _ error) {
+	e := util.StringError("sign BaseOperation")
+
+	found = op.signIndex(priv.Publickey())
+
 	newsign, err := base.NewBaseSignFromFact(priv, networkID, op.fact)
 	if err != nil {
 		return found, sign, e.Wrap(err)
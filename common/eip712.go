@@ -0,0 +1,95 @@
+package common
+
+import (
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const EIP712PrimaryType = "MitumOperation"
+
+type EIP712TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// EIP712TypedData is the typed data of EthereumSignSchemeEIP712; the json
+// format is the parameter of eth_signTypedData_v4. Every field is string.
+type EIP712TypedData struct {
+	Types       map[string][]EIP712TypedDataField `json:"types"`
+	Domain      map[string]string                 `json:"domain"`
+	Message     map[string]string                 `json:"message"`
+	PrimaryType string                            `json:"primaryType"` //nolint:tagliatelle //...
+}
+
+// NewEIP712TypedData derives the typed data from the fact.
+func NewEIP712TypedData(networkID base.NetworkID, fact base.Fact, signedAt time.Time) EIP712TypedData {
+	var ht string
+	if i, ok := fact.(hint.Hinter); ok {
+		ht = i.Hint().String()
+	}
+
+	return EIP712TypedData{
+		Types: map[string][]EIP712TypedDataField{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+			},
+			EIP712PrimaryType: {
+				{Name: "network", Type: "string"},
+				{Name: "factHint", Type: "string"},
+				{Name: "factHash", Type: "string"},
+				{Name: "signedAt", Type: "string"},
+			},
+		},
+		PrimaryType: EIP712PrimaryType,
+		Domain: map[string]string{
+			"name":    EIP712DomainName,
+			"version": EIP712DomainVersion,
+		},
+		Message: map[string]string{
+			"network":  string(networkID),
+			"factHint": ht,
+			"factHash": fact.Hash().String(),
+			"signedAt": localtime.New(signedAt).Normalize().RFC3339(),
+		},
+	}
+}
+
+// Digest returns the EIP-712 hash, keccak256(0x19 0x01 || domainSeparator ||
+// hashStruct(message)).
+func (t EIP712TypedData) Digest() []byte {
+	return crypto.Keccak256(
+		[]byte{ethereumSignPrefix, byte(EthereumSignSchemeEIP712)},
+		t.hashStruct("EIP712Domain", t.Domain),
+		t.hashStruct(t.PrimaryType, t.Message),
+	)
+}
+
+func (t EIP712TypedData) hashStruct(name string, values map[string]string) []byte {
+	fields := t.Types[name]
+
+	s := name + "("
+	for i := range fields {
+		if i > 0 {
+			s += ","
+		}
+
+		s += fields[i].Type + " " + fields[i].Name
+	}
+
+	s += ")"
+
+	bs := make([][]byte, len(fields)+1)
+	bs[0] = crypto.Keccak256([]byte(s))
+
+	for i := range fields {
+		bs[i+1] = crypto.Keccak256([]byte(values[fields[i].Name]))
+	}
+
+	return crypto.Keccak256(util.ConcatBytesSlice(bs...))
+}
@@ -0,0 +1,179 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/localtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// EthereumSignScheme is the EIP-191 version byte of ethereum wallet signature.
+// The ethereum signature of operation is "0x19 <version byte> <65 bytes R || S
+// || V signature>"; the signed message is derived from the network id, fact and
+// signed time, so the signature made by the ethereum wallets can be verified
+// by the MEPublickey of account keys.
+type EthereumSignScheme byte
+
+const (
+	// EthereumSignSchemeEIP191 signs the text of EthereumSignMessage by
+	// personal_sign.
+	EthereumSignSchemeEIP191 EthereumSignScheme = 0x45
+	// EthereumSignSchemeEIP712 signs the typed data of EIP712TypedData by
+	// eth_signTypedData_v4.
+	EthereumSignSchemeEIP712 EthereumSignScheme = 0x01
+
+	ethereumSignPrefix    byte = 0x19
+	ethereumSignatureSize      = 65
+)
+
+var (
+	EIP712DomainName    = "mitum"
+	EIP712DomainVersion = "1"
+)
+
+// ECDSAPrivatekey is the privatekey which can make the ethereum signature.
+type ECDSAPrivatekey interface {
+	base.Privatekey
+	ECDSA() *ecdsa.PrivateKey
+}
+
+// ECDSAPublickey is the public key which can be recovered from the ethereum
+// signature.
+type ECDSAPublickey interface {
+	base.Publickey
+	ECDSA() *ecdsa.PublicKey
+}
+
+func (s EthereumSignScheme) String() string {
+	switch s {
+	case EthereumSignSchemeEIP191:
+		return "eip191"
+	case EthereumSignSchemeEIP712:
+		return "eip712"
+	default:
+		return fmt.Sprintf("unknown(0x%02x)", byte(s))
+	}
+}
+
+func (s EthereumSignScheme) IsValid([]byte) error {
+	switch s {
+	case EthereumSignSchemeEIP191, EthereumSignSchemeEIP712:
+		return nil
+	default:
+		return util.ErrInvalid.Errorf("unknown ethereum sign scheme, %v", s)
+	}
+}
+
+// Digest returns the hash signed by the ethereum wallet.
+func (s EthereumSignScheme) Digest(networkID base.NetworkID, fact base.Fact, signedAt time.Time) ([]byte, error) {
+	if fact == nil || fact.Hash() == nil {
+		return nil, util.ErrInvalid.Errorf("empty fact")
+	}
+
+	switch s {
+	case EthereumSignSchemeEIP191:
+		m := EthereumSignMessage(networkID, fact, signedAt)
+
+		return crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n" + strconv.Itoa(len(m)) + m)), nil
+	case EthereumSignSchemeEIP712:
+		return NewEIP712TypedData(networkID, fact, signedAt).Digest(), nil
+	default:
+		return nil, s.IsValid(nil)
+	}
+}
+
+// EthereumSignMessage is the personal message of EthereumSignSchemeEIP191.
+func EthereumSignMessage(networkID base.NetworkID, fact base.Fact, signedAt time.Time) string {
+	return fmt.Sprintf("network: %s\nfact: %s\nsigned_at: %s",
+		string(networkID), fact.Hash().String(), localtime.New(signedAt).Normalize().RFC3339())
+}
+
+// NewEthereumSign signs the fact like the ethereum wallet.
+func NewEthereumSign(
+	priv base.Privatekey, networkID base.NetworkID, fact base.Fact, scheme EthereumSignScheme,
+) (base.BaseSign, error) {
+	e := util.StringError("ethereum sign")
+
+	k, ok := priv.(ECDSAPrivatekey)
+	if !ok {
+		return base.BaseSign{}, e.Errorf("not ecdsa privatekey, %T", priv)
+	}
+
+	now := localtime.New(localtime.Now().UTC()).Normalize().Time
+
+	digest, err := scheme.Digest(networkID, fact, now)
+	if err != nil {
+		return base.BaseSign{}, e.Wrap(err)
+	}
+
+	sig, err := crypto.Sign(digest, k.ECDSA())
+	if err != nil {
+		return base.BaseSign{}, e.Wrap(err)
+	}
+
+	sig[ethereumSignatureSize-1] += 27 //nolint:gomnd // NOTE V of ethereum wallets
+
+	return base.NewBaseSign(
+		priv.Publickey(),
+		base.Signature(append([]byte{ethereumSignPrefix, byte(scheme)}, sig...)),
+		now,
+	), nil
+}
+
+// IsEthereumSignature checks the signature is made by ethereum sign scheme.
+func IsEthereumSignature(sig base.Signature) (EthereumSignScheme, bool) {
+	if len(sig) != ethereumSignatureSize+2 || sig[0] != ethereumSignPrefix {
+		return 0, false
+	}
+
+	scheme := EthereumSignScheme(sig[1])
+
+	return scheme, scheme.IsValid(nil) == nil
+}
+
+// VerifySign verifies the sign of fact; the ethereum signature is verified by
+// recovering the public key of signer.
+func VerifySign(sign base.Sign, networkID base.NetworkID, fact base.Fact) error {
+	if _, ok := sign.(base.NodeSign); ok {
+		return sign.Verify(networkID, fact.Hash().Bytes())
+	}
+
+	scheme, ok := IsEthereumSignature(sign.Signature())
+	if !ok {
+		return sign.Verify(networkID, fact.Hash().Bytes())
+	}
+
+	pub, ok := sign.Signer().(ECDSAPublickey)
+	if !ok {
+		return errors.Errorf("%v signature of not ecdsa public key, %T", scheme, sign.Signer())
+	}
+
+	digest, err := scheme.Digest(networkID, fact, sign.SignedAt())
+	if err != nil {
+		return err
+	}
+
+	sig := make([]byte, ethereumSignatureSize)
+	copy(sig, sign.Signature()[2:])
+
+	if v := sig[ethereumSignatureSize-1]; v >= 27 { //nolint:gomnd //...
+		sig[ethereumSignatureSize-1] = v - 27
+	}
+
+	recovered, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return base.ErrSignatureVerification.Wrap(err)
+	}
+
+	if crypto.PubkeyToAddress(*recovered) != crypto.PubkeyToAddress(*pub.ECDSA()) {
+		return base.ErrSignatureVerification.Errorf("%v signature not signed by signer", scheme)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package common_test
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type testEthereumFact struct {
+	h util.Hash
+	hint.BaseHinter
+}
+
+func (f testEthereumFact) Hash() util.Hash {
+	return f.h
+}
+
+func (testEthereumFact) Token() base.Token {
+	return base.Token("token")
+}
+
+func (testEthereumFact) IsValid([]byte) error {
+	return nil
+}
+
+// NOTE the signatures of ethereum wallet vectors are made by the first
+// account of the default hardhat and anvil mnemonic,
+// 0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266, with the personal_sign and
+// eth_signTypedData_v4 hashes of go-ethereum, accounts.TextHash and
+// apitypes.TypedDataAndHash, which the ethereum wallets follow.
+var testEthereumSignVectors = []struct {
+	scheme    common.EthereumSignScheme
+	signature string
+}{
+	{
+		scheme:    common.EthereumSignSchemeEIP191,
+		signature: "2e34acc1c7d33594798f8d9b063df3e92e1c37540918df32096f4a0abc6e4b563be51cfbad0ea6fc33083cd6e83dbf0a213d5571ed8566dba6b45d308febba591c",
+	},
+	{
+		scheme:    common.EthereumSignSchemeEIP712,
+		signature: "ef8a821740918acb08a44c67cf4714d43642d6876560d56b1a86c2897d1218df30175760548cbedb17086aa288d6e4d7af36aba3c68f38c63c6011455391068c1b",
+	},
+}
+
+var (
+	testEthereumNetworkID = base.NetworkID("mitum")
+	testEthereumSignedAt  = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	testEthereumFactHint  = hint.MustNewHint("mitum-currency-transfer-operation-fact-v0.0.1")
+)
+
+func newTestEthereumFact() testEthereumFact {
+	return testEthereumFact{
+		BaseHinter: hint.NewBaseHinter(testEthereumFactHint),
+		h:          common.NewHashFromBytes(crypto.Keccak256([]byte("fact"))),
+	}
+}
+
+func newTestEthereumSign(t *testing.T, pub base.Publickey, scheme common.EthereumSignScheme, s string) base.Sign {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base.NewBaseSign(pub, base.Signature(append([]byte{0x19, byte(scheme)}, b...)), testEthereumSignedAt)
+}
+
+func TestEthereumSignMessage(t *testing.T) {
+	m := common.EthereumSignMessage(testEthereumNetworkID, newTestEthereumFact(), testEthereumSignedAt)
+
+	expected := "network: mitum\n" +
+		"fact: 9a13cda0181d4bd6d07f2e467ddf45a1d971e14ca1bcd4c83949a6d830a15b7f\n" +
+		"signed_at: 2024-01-02T03:04:050000000000Z"
+
+	if m != expected {
+		t.Fatalf("unexpected message, %q", m)
+	}
+
+	td := common.NewEIP712TypedData(testEthereumNetworkID, newTestEthereumFact(), testEthereumSignedAt)
+
+	for k, v := range map[string]string{
+		"network":  "mitum",
+		"factHint": testEthereumFactHint.String(),
+		"factHash": "9a13cda0181d4bd6d07f2e467ddf45a1d971e14ca1bcd4c83949a6d830a15b7f",
+		"signedAt": "2024-01-02T03:04:050000000000Z",
+	} {
+		if td.Message[k] != v {
+			t.Fatalf("unexpected typed data message of %q, %q", k, td.Message[k])
+		}
+	}
+}
+
+func TestVerifyEthereumSign(t *testing.T) {
+	priv, err := types.LoadMEPrivatekey("ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := crypto.PubkeyToAddress(priv.ECDSA().PublicKey).Hex(); s != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Fatalf("unexpected ethereum address, %s", s)
+	}
+
+	fact := newTestEthereumFact()
+
+	for _, v := range testEthereumSignVectors {
+		t.Run(v.scheme.String(), func(t *testing.T) {
+			sign := newTestEthereumSign(t, priv.Publickey(), v.scheme, v.signature)
+
+			if scheme, ok := common.IsEthereumSignature(sign.Signature()); !ok || scheme != v.scheme {
+				t.Fatalf("unexpected scheme, %v", scheme)
+			}
+
+			if err := common.VerifySign(sign, testEthereumNetworkID, fact); err != nil {
+				t.Fatal(err)
+			}
+
+			t.Run("wrong signer", func(t *testing.T) {
+				sign := newTestEthereumSign(t, types.NewMEPrivatekey().Publickey(), v.scheme, v.signature)
+
+				if err := common.VerifySign(sign, testEthereumNetworkID, fact); err == nil {
+					t.Fatal("expected error")
+				}
+			})
+
+			t.Run("wrong signed at", func(t *testing.T) {
+				sign := base.NewBaseSign(priv.Publickey(), sign.Signature(), testEthereumSignedAt.Add(time.Second))
+
+				if err := common.VerifySign(sign, testEthereumNetworkID, fact); err == nil {
+					t.Fatal("expected error")
+				}
+			})
+
+			t.Run("wrong network id", func(t *testing.T) {
+				if err := common.VerifySign(sign, base.NetworkID("showme"), fact); err == nil {
+					t.Fatal("expected error")
+				}
+			})
+
+			t.Run("ed25519 signer", func(t *testing.T) {
+				sign := newTestEthereumSign(t, types.NewEd25519Privatekey().Publickey(), v.scheme, v.signature)
+
+				if err := common.VerifySign(sign, testEthereumNetworkID, fact); err == nil {
+					t.Fatal("expected error")
+				}
+			})
+		})
+	}
+
+	t.Run("sign", func(t *testing.T) {
+		for _, scheme := range []common.EthereumSignScheme{
+			common.EthereumSignSchemeEIP191, common.EthereumSignSchemeEIP712,
+		} {
+			sign, err := common.NewEthereumSign(priv, testEthereumNetworkID, fact, scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := common.VerifySign(sign, testEthereumNetworkID, fact); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := common.NewEthereumSign(
+			types.NewEd25519Privatekey(), testEthereumNetworkID, fact, common.EthereumSignSchemeEIP191); err == nil {
+			t.Fatal("expected error for ed25519 privatekey")
+		}
+
+		if _, err := common.NewEthereumSign(
+			priv, testEthereumNetworkID, fact, common.EthereumSignScheme(0x02)); err == nil {
+			t.Fatal("expected error for unknown scheme")
+		}
+	})
+}
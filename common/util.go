@@ -26,7 +26,7 @@ func IsValidSignFact(sf base.SignFact, networkID []byte) error {
 	// NOTE caller should check the duplication of Signs
 
 	for i := range sfs {
-		if err := sfs[i].Verify(networkID, sf.Fact().Hash().Bytes()); err != nil {
+		if err := VerifySign(sfs[i], networkID, sf.Fact()); err != nil {
 			return ErrSignInvalid.Wrap(errors.Errorf("verify sign: %v", err))
 		}
 	}
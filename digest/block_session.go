@@ -7,20 +7,28 @@ import (
 	"time"
 
 	"github.com/ProtoconNet/mitum-currency/v3/digest/isaac"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
 	stateextension "github.com/ProtoconNet/mitum-currency/v3/state/extension"
 	"github.com/ProtoconNet/mitum2/base"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/fixedtree"
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var bulkWriteLimit = 500
 
+// WriteModelPrepareFunc is kept for the compatibility of the existing modules;
+// StateDigest uses DigestWritePrepareFunc.
 type WriteModelPrepareFunc func(BlockSession, base.State) ([]mongo.WriteModel, error)
 
+// DigestWritePrepareFunc builds the DigestWrites of state for StateDigest.
+type DigestWritePrepareFunc func(*BlockSession, base.State) ([]DigestWrite, error)
+
 type BlockSessioner interface {
 	Prepare() error
 	Commit(context.Context) error
@@ -44,9 +52,11 @@ type BlockSession struct {
 	contractAccountModels []mongo.WriteModel
 	balanceModels         []mongo.WriteModel
 	currencyModels        []mongo.WriteModel
+	stateDigestWrites     map[string][]DigestWrite
 	statesValue           *sync.Map
 	balanceAddressList    []string
 	buildinfo             string
+	enc                   encoder.Encoder
 }
 
 func NewBlockSession(st *Database, blk base.BlockMap, ops []base.Operation, opsTree fixedtree.Tree, sts []base.State, proposal base.ProposalSignFact, vs string) (*BlockSession, error) {
@@ -59,8 +69,17 @@ func NewBlockSession(st *Database, blk base.BlockMap, ops []base.Operation, opsT
 		return nil, err
 	}
 
+	bs := newBlockSession(nst.digestDB.Encoder(), blk, ops, opsTree, sts, proposal, vs)
+	bs.st = nst
+
+	return bs, nil
+}
+
+// newBlockSession returns BlockSession without database; it only prepares the
+// write models.
+func newBlockSession(enc encoder.Encoder, blk base.BlockMap, ops []base.Operation, opsTree fixedtree.Tree, sts []base.State, proposal base.ProposalSignFact, vs string) *BlockSession {
 	return &BlockSession{
-		st:          nst,
+		enc:         enc,
 		block:       blk,
 		ops:         ops,
 		opsTree:     opsTree,
@@ -68,7 +87,7 @@ func NewBlockSession(st *Database, blk base.BlockMap, ops []base.Operation, opsT
 		proposal:    proposal,
 		statesValue: &sync.Map{},
 		buildinfo:   vs,
-	}, nil
+	}
 }
 
 func (bs *BlockSession) Prepare() error {
@@ -91,7 +110,11 @@ func (bs *BlockSession) Prepare() error {
 		return err
 	}
 
-	return bs.prepareAccounts()
+	if err := bs.prepareAccounts(); err != nil {
+		return err
+	}
+
+	return bs.prepareStateDigests()
 }
 
 func (bs *BlockSession) Commit(ctx context.Context) error {
@@ -101,48 +124,98 @@ func (bs *BlockSession) Commit(ctx context.Context) error {
 	started := time.Now()
 	defer func() {
 		bs.statesValue.Store("commit", time.Since(started))
+		metrics.DigestBlockSessionCommit.Since(started)
 		_ = bs.close()
 	}()
 
 	_, err := bs.st.digestDB.Client().WithSession(func(txnCtx mongo.SessionContext, collection func(string) *mongo.Collection) (interface{}, error) {
-		if err := bs.writeModels(txnCtx, defaultColNameBlock, bs.blockModels); err != nil {
-			return nil, err
-		}
+		cms := bs.collectionModels()
 
-		if len(bs.operationModels) > 0 {
-			if err := bs.writeModels(txnCtx, defaultColNameOperation, bs.operationModels); err != nil {
+		for i := range cms {
+			if err := bs.writeModels(txnCtx, cms[i].col, cms[i].models); err != nil {
 				return nil, err
 			}
 		}
 
-		if len(bs.currencyModels) > 0 {
-			if err := bs.writeModels(txnCtx, defaultColNameCurrency, bs.currencyModels); err != nil {
-				return nil, err
-			}
+		return nil, nil
+	})
+
+	return err
+}
+
+type collectionModels struct {
+	col    string
+	models []mongo.WriteModel
+}
+
+// collectionModels returns the prepared write models by the order of
+// collections to be written.
+func (bs *BlockSession) collectionModels() []collectionModels {
+	cms := []collectionModels{
+		{col: defaultColNameBlock, models: bs.blockModels},
+		{col: defaultColNameOperation, models: bs.operationModels},
+		{col: defaultColNameCurrency, models: bs.currencyModels},
+		{col: defaultColNameAccount, models: bs.accountModels},
+		{col: defaultColNameContractAccount, models: bs.contractAccountModels},
+		{col: defaultColNameBalance, models: bs.balanceModels},
+	}
+
+	sds := StateDigests()
+	for i := range sds {
+		writes := bs.stateDigestWrites[sds[i].Collection]
+
+		models := make([]mongo.WriteModel, len(writes))
+		for j := range writes {
+			models[j] = digestWriteModel(writes[j])
 		}
 
-		if len(bs.accountModels) > 0 {
-			if err := bs.writeModels(txnCtx, defaultColNameAccount, bs.accountModels); err != nil {
-				return nil, err
-			}
+		cms = append(cms, collectionModels{col: sds[i].Collection, models: models})
+	}
+
+	return cms
+}
+
+// digestWriteModel returns the mongodb write model of DigestWrite.
+func digestWriteModel(w DigestWrite) mongo.WriteModel {
+	filter := w.filter
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	switch w.kind {
+	case DigestWriteInsert:
+		return mongo.NewInsertOneModel().SetDocument(w.doc)
+	case DigestWriteReplace:
+		return mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(w.doc).SetUpsert(w.upsert)
+	case DigestWriteUpdate:
+		update := bson.D{}
+		if len(w.set) > 0 {
+			update = append(update, bson.E{Key: "$set", Value: w.set})
 		}
 
-		if len(bs.contractAccountModels) > 0 {
-			if err := bs.writeModels(txnCtx, defaultColNameContractAccount, bs.contractAccountModels); err != nil {
-				return nil, err
+		if len(w.unset) > 0 {
+			unset := make(bson.D, len(w.unset))
+			for i := range w.unset {
+				unset[i] = bson.E{Key: w.unset[i], Value: ""}
 			}
+
+			update = append(update, bson.E{Key: "$unset", Value: unset})
 		}
 
-		if len(bs.balanceModels) > 0 {
-			if err := bs.writeModels(txnCtx, defaultColNameBalance, bs.balanceModels); err != nil {
-				return nil, err
-			}
+		if w.many {
+			return mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update).SetUpsert(w.upsert)
 		}
 
-		return nil, nil
-	})
+		return mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(w.upsert)
+	case DigestWriteDelete:
+		if w.many {
+			return mongo.NewDeleteManyModel().SetFilter(filter)
+		}
 
-	return err
+		return mongo.NewDeleteOneModel().SetFilter(filter)
+	default:
+		return nil
+	}
 }
 
 func (bs *BlockSession) Close() error {
@@ -190,7 +263,7 @@ func (bs *BlockSession) prepareBlock() error {
 		bs.block.Manifest().ProposedAt(),
 	)
 
-	doc, err := NewManifestDoc(manifest, bs.st.digestDB.Encoder(), bs.block.Manifest().Height(), bs.ops, bs.block.SignedAt(), bs.proposal.ProposalFact().Proposer(), bs.proposal.ProposalFact().Point().Round(), bs.buildinfo)
+	doc, err := NewManifestDoc(manifest, bs.enc, bs.block.Manifest().Height(), bs.ops, bs.block.SignedAt(), bs.proposal.ProposalFact().Proposer(), bs.proposal.ProposalFact().Point().Round(), bs.buildinfo)
 	if err != nil {
 		return err
 	}
@@ -232,7 +305,7 @@ func (bs *BlockSession) prepareOperations() error {
 			}
 			d, err := NewOperationDoc(
 				op,
-				bs.st.digestDB.Encoder(),
+				bs.enc,
 				bs.block.Manifest().Height(),
 				bs.block.SignedAt(),
 				inState,
@@ -321,7 +394,7 @@ func (bs *BlockSession) prepareCurrencies() error {
 func (bs *BlockSession) handleAccountState(st base.State) ([]mongo.WriteModel, error) {
 	if rs, err := NewAccountValue(st); err != nil {
 		return nil, err
-	} else if doc, err := NewAccountDoc(rs, bs.st.digestDB.Encoder()); err != nil {
+	} else if doc, err := NewAccountDoc(rs, bs.enc); err != nil {
 		return nil, err
 	} else {
 		return []mongo.WriteModel{mongo.NewInsertOneModel().SetDocument(doc)}, nil
@@ -329,7 +402,7 @@ func (bs *BlockSession) handleAccountState(st base.State) ([]mongo.WriteModel, e
 }
 
 func (bs *BlockSession) handleBalanceState(st base.State) ([]mongo.WriteModel, string, error) {
-	doc, address, err := NewBalanceDoc(st, bs.st.digestDB.Encoder())
+	doc, address, err := NewBalanceDoc(st, bs.enc)
 	if err != nil {
 		return nil, "", err
 	}
@@ -337,7 +410,7 @@ func (bs *BlockSession) handleBalanceState(st base.State) ([]mongo.WriteModel, s
 }
 
 func (bs *BlockSession) handleContractAccountState(st base.State) ([]mongo.WriteModel, error) {
-	doc, err := NewContractAccountStatusDoc(st, bs.st.digestDB.Encoder())
+	doc, err := NewContractAccountStatusDoc(st, bs.enc)
 	if err != nil {
 		return nil, err
 	}
@@ -345,7 +418,7 @@ func (bs *BlockSession) handleContractAccountState(st base.State) ([]mongo.Write
 }
 
 func (bs *BlockSession) handleCurrencyState(st base.State) ([]mongo.WriteModel, error) {
-	doc, err := NewCurrencyDoc(st, bs.st.digestDB.Encoder())
+	doc, err := NewCurrencyDoc(st, bs.enc)
 	if err != nil {
 		return nil, err
 	}
@@ -389,10 +462,14 @@ func (bs *BlockSession) writeModelsChunk(ctx context.Context, col string, models
 	opts := options.BulkWrite().SetOrdered(false)
 	if res, err := bs.st.digestDB.Client().Collection(col).BulkWrite(ctx, models, opts); err != nil {
 		return err
-	} else if res != nil && res.InsertedCount < 1 {
+	} else if res != nil && res.InsertedCount < 1 && isBuiltinCollection(col) {
+		// NOTE the builtin collections are insert-only; the writes of StateDigest
+		// may match nothing, like the update of unchanged document.
 		return errors.Errorf("Not inserted to %s", col)
 	}
 
+	metrics.DigestMongoWrites.Add(uint64(len(models)), col)
+
 	return nil
 }
 
@@ -403,6 +480,11 @@ func (bs *BlockSession) close() error {
 	bs.accountModels = nil
 	bs.contractAccountModels = nil
 	bs.balanceModels = nil
+	bs.stateDigestWrites = nil
+
+	if bs.st == nil {
+		return nil
+	}
 
 	return bs.st.Close()
 }
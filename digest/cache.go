@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/valuehash"
 	"github.com/bluele/gcache"
@@ -254,10 +255,14 @@ func ScanCRLF(data []byte, atEOF bool) (int, []byte, error) {
 
 func LoadFromCache(cache Cache, key string, w http.ResponseWriter) error {
 	if b, err := cache.Get(MakeCacheKey(key)); err != nil {
+		metrics.DigestCacheRequests.Inc(metrics.CacheMiss)
+
 		return err
 	} else if err = WriteFromCache(b, w); err != nil {
 		return err
 	} else {
+		metrics.DigestCacheRequests.Inc(metrics.CacheHit)
+
 		return nil
 	}
 }
@@ -0,0 +1,116 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+// ErrDigestDiverged means the digested manifest is different with the block
+// map of node at same height, like after `storage import` or the local
+// database reset.
+var ErrDigestDiverged = util.NewIDError("digest diverged")
+
+// BlockMapFunc returns the block map of node by height.
+type BlockMapFunc func(base.Height) (base.BlockMap, bool, error)
+
+// LastConsistentHeight returns the highest height, where the digested manifest
+// hash is same with the block map of node. The digested heights over the last
+// height of node are regarded as diverged. Once diverged, the next blocks are
+// also diverged, so the height is found by binary search. The missing heights
+// in digest are decided by the digested block under them.
+func LastConsistentHeight(st Store, last base.Height, blockMap BlockMapFunc) (base.Height, error) {
+	top := st.LastBlock()
+	if top > last {
+		top = last
+	}
+
+	if top < base.GenesisHeight {
+		return base.NilHeight, nil
+	}
+
+	switch ok, err := isDigestConsistent(st, top, base.GenesisHeight, blockMap); {
+	case err != nil:
+		return base.NilHeight, err
+	case ok:
+		return top, nil
+	}
+
+	found := base.NilHeight
+	low, high := base.GenesisHeight, top-1
+
+	for low <= high {
+		mid := low + (high-low)/2
+
+		switch ok, err := isDigestConsistent(st, mid, low, blockMap); {
+		case err != nil:
+			return base.NilHeight, err
+		case ok:
+			found = mid
+			low = mid + 1
+		default:
+			high = mid - 1
+		}
+	}
+
+	return found, nil
+}
+
+// RollbackDiverged rolls back the digest to the last consistent height; the
+// blocks after the height should be digested again. It returns the last
+// consistent height and whether rolled back.
+func RollbackDiverged(
+	ctx context.Context,
+	st Store,
+	last base.Height,
+	blockMap BlockMapFunc,
+) (base.Height, bool, error) {
+	height, err := LastConsistentHeight(st, last, blockMap)
+	switch {
+	case err != nil:
+		return base.NilHeight, false, errors.WithMessage(err, "check digest consistency")
+	case height >= st.LastBlock():
+		return height, false, nil
+	}
+
+	if err := st.CleanByHeight(ctx, height+1); err != nil {
+		return base.NilHeight, false, errors.WithMessagef(err, "rollback digest to %d", height)
+	}
+
+	metrics.DigestRollbacks.Inc()
+
+	return height, true, nil
+}
+
+// isDigestConsistent checks the digested block at height. If the block is
+// missing in digest, the lower blocks down to low are checked instead; the
+// missing block itself is not divergence, but the diverged block under it
+// should not be hidden from the binary search. Without digested block down to
+// low, it is regarded as consistent.
+func isDigestConsistent(st Store, height, low base.Height, blockMap BlockMapFunc) (bool, error) {
+	for h := height; h >= low; h-- {
+		m, _, _, _, _, err := st.ManifestByHeight(h)
+
+		switch {
+		case err == nil:
+		case errors.Is(err, util.ErrNotFound):
+			continue
+		default:
+			return false, err
+		}
+
+		switch bm, found, err := blockMap(h); {
+		case err != nil:
+			return false, err
+		case !found:
+			return false, nil
+		default:
+			return bm.Manifest().Hash().Equal(m.Hash()), nil
+		}
+	}
+
+	return true, nil
+}
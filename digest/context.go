@@ -7,5 +7,6 @@ var (
 	ContextValueDigestDatabase util.ContextKey = "digest_database"
 	ContextValueDigestNetwork  util.ContextKey = "digest_network"
 	ContextValueDigester       util.ContextKey = "digester"
+	ContextValueDigestHandlers util.ContextKey = "digest_handlers"
 	ContextValueLocalNetwork   util.ContextKey = "local_network"
 )
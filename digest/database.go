@@ -3,6 +3,7 @@ package digest
 import (
 	"context"
 	"fmt"
+	"github.com/ProtoconNet/mitum-currency/v3/common"
 	digestmongo "github.com/ProtoconNet/mitum-currency/v3/digest/mongodb"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
 	"github.com/ProtoconNet/mitum-currency/v3/state/currency"
@@ -12,6 +13,7 @@ import (
 	isaacdatabase "github.com/ProtoconNet/mitum2/isaac/database"
 	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
 	"github.com/ProtoconNet/mitum2/util/logging"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -24,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 var maxLimit int64 = 50
@@ -35,6 +38,7 @@ var (
 	defaultColNameCurrency        = "digest_cr"
 	defaultColNameOperation       = "digest_op"
 	defaultColNameBlock           = "digest_bm"
+	defaultColNameSuffrageBond    = "digest_sb"
 )
 
 var AllCollections = []string{
@@ -45,15 +49,26 @@ var AllCollections = []string{
 	defaultColNameBlock,
 }
 
-var DigestStorageLastBlockKey = "digest_last_block"
+var (
+	DigestStorageLastBlockKey = "digest_last_block"
+	// DigestStorageActiveDatabaseKey is the info key of the database of design
+	// for the name of active digest database.
+	DigestStorageActiveDatabaseKey = "digest_active_database"
+)
 
 type Database struct {
 	sync.RWMutex
 	*logging.Logging
-	mitumDB   *isaacdatabase.Center
-	digestDB  *digestmongo.Database
+	mitumDB  *isaacdatabase.Center
+	digestDB *digestmongo.Database
+	// NOTE root is the database of design, which keeps the name of active
+	// digest database; nil if digestDB is the database of design.
+	root      *digestmongo.Database
 	readonly  bool
 	lastBlock base.Height
+	// NOTE opFilterReady is set when no operation document of older digest is
+	// left.
+	opFilterReady atomic.Bool
 }
 
 func NewDatabase(mitumDB *isaacdatabase.Center, digestDB *digestmongo.Database) (*Database, error) {
@@ -92,6 +107,39 @@ func (db *Database) New() (*Database, error) {
 	return NewDatabase(db.mitumDB, nst)
 }
 
+// NewByName returns new Database, which stores digest in the another database
+// of same mongodb server.
+func (db *Database) NewByName(name string) (*Database, error) {
+	if db.readonly {
+		return nil, errors.Errorf("Readonly mode")
+	}
+
+	nst, err := db.digestDB.NewByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ndb, err := NewDatabase(db.mitumDB, nst)
+	if err != nil {
+		return nil, err
+	}
+
+	ndb.root = db.rootDB()
+
+	return ndb, nil
+}
+
+func (db *Database) NewBlockSession(
+	blk base.BlockMap,
+	ops []base.Operation,
+	opsTree fixedtree.Tree,
+	sts []base.State,
+	proposal base.ProposalSignFact,
+	vs string,
+) (BlockSessioner, error) {
+	return NewBlockSession(db, blk, ops, opsTree, sts, proposal, vs)
+}
+
 func (db *Database) Readonly() bool {
 	return db.readonly
 }
@@ -146,6 +194,17 @@ func (db *Database) CreateIndex(dIndexes map[string][]mongo.IndexModel) error {
 		}
 	}
 
+	sds := StateDigests()
+	for i := range sds {
+		if len(sds[i].Indexes) < 1 {
+			continue
+		}
+
+		if err := db.digestDB.CreateIndex(sds[i].Collection, sds[i].Indexes, indexPrefix); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -195,13 +254,7 @@ func (db *Database) Clean() error {
 }
 
 func (db *Database) clean(ctx context.Context) error {
-	for _, col := range []string{
-		defaultColNameAccount,
-		defaultColNameBalance,
-		defaultColNameCurrency,
-		defaultColNameOperation,
-		defaultColNameBlock,
-	} {
+	for _, col := range digestCollections() {
 		if err := db.digestDB.Client().Collection(col).Drop(ctx); err != nil {
 			return err
 		}
@@ -237,13 +290,7 @@ func (db *Database) cleanByHeight(ctx context.Context, height base.Height) error
 	opts := options.BulkWrite().SetOrdered(true)
 	removeByHeight := mongo.NewDeleteManyModel().SetFilter(bson.M{"height": bson.M{"$gte": height}})
 
-	for _, col := range []string{
-		defaultColNameAccount,
-		defaultColNameBalance,
-		defaultColNameCurrency,
-		defaultColNameOperation,
-		defaultColNameBlock,
-	} {
+	for _, col := range digestCollections() {
 		res, err := db.digestDB.Client().Collection(col).BulkWrite(
 			ctx,
 			[]mongo.WriteModel{removeByHeight},
@@ -259,6 +306,80 @@ func (db *Database) cleanByHeight(ctx context.Context, height base.Height) error
 	return db.setLastBlock(height - 1)
 }
 
+// Name returns the name of mongodb database of digest.
+func (db *Database) Name() string {
+	return db.digestDB.Client().DatabaseName()
+}
+
+// Active returns the Database of the active digest database. The name of active
+// database is kept in the database of design, so the rebuilt digest database
+// is activated by SetActive without moving the collections. Without the active
+// database, it returns itself.
+func (db *Database) Active() (*Database, error) {
+	var name string
+
+	switch b, found, err := db.rootDB().Info(DigestStorageActiveDatabaseKey); {
+	case err != nil:
+		return nil, errors.Wrap(err, "get active digest database")
+	case !found:
+		return db, nil
+	default:
+		name = string(b)
+	}
+
+	if name == db.Name() {
+		return db, nil
+	}
+
+	st, err := db.digestDB.NewByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var nst *Database
+
+	if db.readonly {
+		nst, err = NewReadonlyDatabase(db.mitumDB, st)
+	} else {
+		nst, err = NewDatabase(db.mitumDB, st)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	nst.root = db.rootDB()
+
+	return nst, nil
+}
+
+// SetActive activates the target database; the name of target is saved in the
+// database of design by single write, so the active database is changed
+// atomically. The running node keeps the previous database until restarted,
+// and the previous database is not dropped.
+func (db *Database) SetActive(target *Database) error {
+	if db.readonly {
+		return errors.Errorf("Readonly mode")
+	}
+
+	switch _, found, err := loadLastBlock(target); {
+	case err != nil:
+		return err
+	case !found:
+		return errors.Errorf("Empty digest database, %q", target.Name())
+	}
+
+	return db.rootDB().SetInfo(DigestStorageActiveDatabaseKey, []byte(target.Name()))
+}
+
+func (db *Database) rootDB() *digestmongo.Database {
+	if db.root != nil {
+		return db.root
+	}
+
+	return db.digestDB
+}
+
 /*
 func (st *Database) Manifest(h mitumutil.Hash) (base.Manifest, bool, error) {
 	return st.mitum.Manifest(h)
@@ -327,12 +448,18 @@ func (db *Database) OperationsByAddress(
 	reverse bool,
 	offset string,
 	limit int64,
+	of OperationFilter,
 	callback func(mitumutil.Hash /* fact hash */, OperationValue) (bool, error),
 ) error {
+	if err := db.checkOperationFilter(of); err != nil {
+		return err
+	}
+
 	filter, err := buildOperationsFilterByAddress(address, offset, reverse)
 	if err != nil {
 		return err
 	}
+	filter = of.Merge(filter)
 
 	sr := 1
 	if reverse {
@@ -416,9 +543,75 @@ func (db *Database) Operation(
 	return va, true, nil
 }
 
-// Operations returns operation.Operations by order, height and index.
+// Operations returns operation.Operations by order, height and index. The
+// total number of operations filtered by OperationFilter is passed to callback.
+// *  offset: returns from next of offset, "<height>,<index>".
 func (db *Database) Operations(
+	of OperationFilter,
+	offset string,
+	reverse bool,
+	load bool,
+	limit int64,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
+) error {
+	if err := db.checkOperationFilter(of); err != nil {
+		return err
+	}
+
+	filter, err := buildOperationsFilterByOffset(offset, reverse)
+	if err != nil {
+		return err
+	}
+
+	return db.operations(of.Merge(filter), of.BSON(), load, reverse, limit, callback)
+}
+
+// checkOperationFilter rejects the filter, which needs the fields of operation
+// document, if the operation documents of older digest are left; they do not
+// have the fields and the filter silently misses them.
+func (db *Database) checkOperationFilter(of OperationFilter) error {
+	if !of.needsDigestedFields() || db.opFilterReady.Load() {
+		return nil
+	}
+
+	switch found, err := db.digestDB.Client().Exists(
+		defaultColNameOperation,
+		bson.D{{Key: "hint", Value: bson.M{"$exists": false}}},
+	); {
+	case err != nil:
+		return err
+	case found:
+		return ErrBadRequest.Errorf(
+			"operation filter not supported by the digest of older version; run `storage rebuild-digest`")
+	default:
+		db.opFilterReady.Store(true)
+
+		return nil
+	}
+}
+
+// OperationsByHeight returns operation.Operations of the block by order of
+// index.
+// *  offset: returns from next of offset index.
+func (db *Database) OperationsByHeight(
+	height base.Height,
+	offset string,
+	reverse bool,
+	load bool,
+	limit int64,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
+) error {
+	filter, err := buildOperationsByHeightFilterByOffset(height, offset, reverse)
+	if err != nil {
+		return err
+	}
+
+	return db.operations(filter, bson.M{}, load, reverse, limit, callback)
+}
+
+func (db *Database) operations(
 	filter bson.M,
+	countFilter bson.M,
 	load bool,
 	reverse bool,
 	limit int64,
@@ -445,7 +638,7 @@ func (db *Database) Operations(
 		opt = opt.SetProjection(bson.M{"fact": 1})
 	}
 
-	count, err := db.digestDB.Client().Count(context.Background(), defaultColNameOperation, bson.D{})
+	count, err := db.digestDB.Client().Count(context.Background(), defaultColNameOperation, countFilter)
 	if err != nil {
 		return err
 	}
@@ -473,11 +666,13 @@ func (db *Database) Operations(
 	)
 }
 
-// OperationsByHash returns operation.Operations by order, height and index.
+// OperationsByHash returns operation.Operations by the fact hashes.
 func (db *Database) OperationsByHash(
-	filter bson.M,
+	hashes []mitumutil.Hash,
 	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
 ) error {
+	filter := bson.M{"fact": bson.M{"$in": hashes}}
+
 	count, err := db.digestDB.Client().Count(context.Background(), defaultColNameOperation, bson.D{})
 	if err != nil {
 		return err
@@ -678,6 +873,288 @@ func (db *Database) balance(a base.Address) ([]types.Amount, base.Height, error)
 	return ams, lastHeight, nil
 }
 
+// BalanceHistory returns the balance states of currency of the account by
+// height. The balance state is stored only at the height it changed.
+// * fromHeight, toHeight: height range; base.NilHeight means no limit.
+// * offset: returns from next of offset height.
+func (db *Database) BalanceHistory(
+	address base.Address,
+	cid string,
+	fromHeight,
+	toHeight base.Height,
+	offset base.Height,
+	reverse bool,
+	limit int64,
+	callback func(base.State) (bool, error),
+) error {
+	filter := buildBalanceHistoryFilter(address, cid, fromHeight, toHeight, offset, reverse)
+
+	sr := 1
+	if reverse {
+		sr = -1
+	}
+
+	opt := options.Find().SetSort(util.NewBSONFilter("height", sr).D())
+
+	switch {
+	case limit <= 0: // no limit
+	case limit > maxLimit:
+		opt = opt.SetLimit(maxLimit)
+	default:
+		opt = opt.SetLimit(limit)
+	}
+
+	return db.digestDB.Client().Find(
+		context.Background(),
+		defaultColNameBalance,
+		filter,
+		func(cursor *mongo.Cursor) (bool, error) {
+			st, err := LoadBalance(cursor.Decode, db.digestDB.Encoders())
+			if err != nil {
+				return false, err
+			}
+
+			return callback(st)
+		},
+		opt,
+	)
+}
+
+// BalanceByHeight returns the balance state of currency of the account at the
+// given height, that is, the last balance state at or before the height.
+func (db *Database) BalanceByHeight(
+	address base.Address,
+	cid string,
+	height base.Height,
+) (base.State, bool, error) {
+	q := util.NewBSONFilter("address", address.String()).
+		Add("currency", cid).
+		Add("height", bson.M{"$lte": height}).D()
+
+	var sta base.State
+	if err := db.digestDB.Client().GetByFilter(
+		defaultColNameBalance,
+		q,
+		func(res *mongo.SingleResult) error {
+			i, err := LoadBalance(res.Decode, db.digestDB.Encoders())
+			if err != nil {
+				return err
+			}
+			sta = i
+
+			return nil
+		},
+		options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+	); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return sta, sta != nil, nil
+}
+
+// CurrencyHolders returns the accounts holding the currency, sorted by the
+// latest balance. The accounts with zero balance are not counted. The order
+// of balances over 34 digits may be approximated, because of Decimal128.
+func (db *Database) CurrencyHolders(
+	cid string,
+	offset,
+	limit int64,
+) ([]CurrencyHolder, int64, error) {
+	switch {
+	case limit <= 0:
+		limit = maxLimit
+	case limit > maxLimit:
+		limit = maxLimit
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"currency": cid}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "address", Value: 1}, {Key: "height", Value: -1}}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":    "$address",
+			"amount": bson.M{"$first": "$amount"},
+			"height": bson.M{"$first": "$height"},
+		}}},
+		bson.D{{Key: "$addFields", Value: bson.M{"amount_dec": bson.M{"$toDecimal": "$amount"}}}},
+		bson.D{{Key: "$match", Value: bson.M{"amount_dec": bson.M{"$gt": 0}}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "count"}},
+			"holders": bson.A{
+				bson.M{"$sort": bson.D{{Key: "amount_dec", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$skip": offset},
+				bson.M{"$limit": limit},
+			},
+		}}},
+	}
+
+	cursor, err := db.digestDB.Client().Collection(defaultColNameBalance).Aggregate(
+		context.Background(),
+		pipeline,
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	var docs []struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Holders []struct {
+			Address string      `bson:"_id"`
+			Amount  string      `bson:"amount"`
+			Height  base.Height `bson:"height"`
+		} `bson:"holders"`
+	}
+
+	if err := cursor.All(context.Background(), &docs); err != nil {
+		return nil, 0, err
+	}
+
+	if len(docs) < 1 || len(docs[0].Total) < 1 {
+		return nil, 0, nil
+	}
+
+	holders := make([]CurrencyHolder, len(docs[0].Holders))
+	for i := range docs[0].Holders {
+		d := docs[0].Holders[i]
+
+		am, err := common.NewBigFromString(d.Amount)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		holders[i] = CurrencyHolder{
+			Address: d.Address,
+			Amount:  am,
+			Height:  d.Height,
+			Rank:    offset + int64(i) + 1,
+		}
+	}
+
+	return holders, docs[0].Total[0].Count, nil
+}
+
+// CurrencyBalanceSum returns the sum of the latest balances of the currency
+// of the given addresses.
+func (db *Database) CurrencyBalanceSum(cid string, addresses []string) (common.Big, error) {
+	sum := common.ZeroBig
+
+	for i := range addresses {
+		q := util.NewBSONFilter("address", addresses[i]).Add("currency", cid).D()
+
+		var sta base.State
+		if err := db.digestDB.Client().GetByFilter(
+			defaultColNameBalance,
+			q,
+			func(res *mongo.SingleResult) error {
+				i, err := LoadBalance(res.Decode, db.digestDB.Encoders())
+				if err != nil {
+					return err
+				}
+				sta = i
+
+				return nil
+			},
+			options.FindOne().SetSort(util.NewBSONFilter("height", -1).D()),
+		); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+
+			return common.ZeroBig, err
+		}
+
+		am, err := currency.StateBalanceValue(sta)
+		if err != nil {
+			return common.ZeroBig, err
+		}
+
+		sum = sum.Add(am.Big())
+	}
+
+	return sum, nil
+}
+
+// CurrencySupplyBalances calls callback with the latest balances of the
+// accounts and the latest deposits of the suffrage bonds of the currency by one
+// aggregation. The amounts are summed by the caller, not by mongodb, because
+// Decimal128 may approximate the big amounts.
+func (db *Database) CurrencySupplyBalances(
+	cid string,
+	callback func(key string, amount common.Big) (bool, error),
+) error {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"currency": cid}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "address", Value: 1}, {Key: "height", Value: -1}}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":    "$address",
+			"amount": bson.M{"$first": "$amount"},
+		}}},
+		bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll": defaultColNameSuffrageBond,
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"currency": cid}},
+				bson.M{"$sort": bson.D{{Key: "node", Value: 1}, {Key: "height", Value: -1}}},
+				bson.M{"$group": bson.M{
+					"_id":    bson.M{"$concat": bson.A{"$node", currency.SuffrageBondStateKeySuffix}},
+					"amount": bson.M{"$first": "$amount"},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := db.digestDB.Client().Collection(defaultColNameBalance).Aggregate(
+		context.Background(),
+		pipeline,
+		options.Aggregate().SetAllowDiskUse(true),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = cursor.Close(context.Background())
+	}()
+
+	for cursor.Next(context.Background()) {
+		var doc struct {
+			Key    string `bson:"_id"`
+			Amount string `bson:"amount"`
+		}
+
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		am, err := common.NewBigFromString(doc.Amount)
+		if err != nil {
+			return err
+		}
+
+		switch keep, err := callback(doc.Key, am); {
+		case err != nil:
+			return err
+		case !keep:
+			return nil
+		}
+	}
+
+	return cursor.Err()
+}
+
 func (db *Database) contractAccountStatus(a base.Address) (types.ContractAccountStatus, base.Height, error) {
 	lastHeight := base.NilHeight
 
@@ -718,7 +1195,7 @@ func (db *Database) contractAccountStatus(a base.Address) (types.ContractAccount
 	}
 }
 
-func (db *Database) currencies() ([]string, error) {
+func (db *Database) Currencies() ([]string, error) {
 	var cids []string
 
 	for {
@@ -834,7 +1311,7 @@ func (db *Database) ManifestByHash(hash mitumutil.Hash) (base.Manifest, uint64,
 	}
 }
 
-func (db *Database) currency(cid string) (types.CurrencyDesign, base.State, error) {
+func (db *Database) Currency(cid string) (types.CurrencyDesign, base.State, error) {
 	q := util.NewBSONFilter("currency", cid).D()
 
 	opt := options.FindOne().SetSort(
@@ -868,7 +1345,7 @@ func (db *Database) currency(cid string) (types.CurrencyDesign, base.State, erro
 	}
 }
 
-func (db *Database) topHeightByPublickey(pub base.Publickey) (base.Height, error) {
+func (db *Database) TopHeightByPublickey(pub base.Publickey) (base.Height, error) {
 	var sas []string
 	switch r, err := db.digestDB.Client().Collection(defaultColNameAccount).Distinct(
 		context.Background(),
@@ -1119,6 +1596,62 @@ func buildOffset(height base.Height, index uint64) string {
 	return fmt.Sprintf("%d,%d", height, index)
 }
 
+func buildOperationsFilterByOffset(offset string, reverse bool) (bson.M, error) {
+	filter := bson.M{}
+	if len(offset) > 0 {
+		height, index, err := parseOffset(offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if reverse {
+			filter["$or"] = []bson.M{
+				{"height": bson.M{"$lt": height}},
+				{"$and": []bson.M{
+					{"height": height},
+					{"index": bson.M{"$lt": index}},
+				}},
+			}
+		} else {
+			filter["$or"] = []bson.M{
+				{"height": bson.M{"$gt": height}},
+				{"$and": []bson.M{
+					{"height": height},
+					{"index": bson.M{"$gt": index}},
+				}},
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+func buildOperationsByHeightFilterByOffset(height base.Height, offset string, reverse bool) (bson.M, error) {
+	var filter bson.M
+	if len(offset) < 1 {
+		return bson.M{"height": height}, nil
+	}
+
+	index, err := strconv.ParseUint(offset, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid index of offset")
+	}
+
+	if reverse {
+		filter = bson.M{
+			"height": height,
+			"index":  bson.M{"$lt": index},
+		}
+	} else {
+		filter = bson.M{
+			"height": height,
+			"index":  bson.M{"$gt": index},
+		}
+	}
+
+	return filter, nil
+}
+
 func buildOperationsFilterByAddress(address base.Address, offset string, reverse bool) (bson.M, error) {
 	filter := bson.M{"addresses": bson.M{"$in": []string{address.String()}}}
 	if len(offset) > 0 {
@@ -1149,6 +1682,46 @@ func buildOperationsFilterByAddress(address base.Address, offset string, reverse
 	return filter, nil
 }
 
+func buildBalanceHistoryFilter(
+	address base.Address,
+	cid string,
+	fromHeight,
+	toHeight base.Height,
+	offset base.Height,
+	reverse bool,
+) bson.M {
+	filter := bson.M{"address": address.String(), "currency": cid}
+
+	hf := bson.M{}
+	if fromHeight > base.NilHeight {
+		hf["$gte"] = fromHeight
+	}
+
+	if toHeight > base.NilHeight {
+		hf["$lte"] = toHeight
+	}
+
+	if offset > base.NilHeight {
+		if reverse {
+			if i, found := hf["$lte"]; !found || i.(base.Height) >= offset {
+				delete(hf, "$lte")
+				hf["$lt"] = offset
+			}
+		} else {
+			if i, found := hf["$gte"]; !found || i.(base.Height) <= offset {
+				delete(hf, "$gte")
+				hf["$gt"] = offset
+			}
+		}
+	}
+
+	if len(hf) > 0 {
+		filter["height"] = hf
+	}
+
+	return filter
+}
+
 func parseOffsetByString(s string) (base.Height, string, error) {
 	var a, b string
 	switch n := strings.SplitN(s, ",", 2); {
@@ -0,0 +1,1855 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
+	"github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/state/extension"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	leveldbstorage "github.com/ProtoconNet/mitum2/storage/leveldb"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/syndtr/goleveldb/leveldb"
+	leveldbStorage "github.com/syndtr/goleveldb/leveldb/storage"
+	leveldbutil "github.com/syndtr/goleveldb/leveldb/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	leveldbKeyPrefixInfo             = leveldbstorage.KeyPrefix{0x00, 0x00}
+	leveldbKeyPrefixHeight           = leveldbstorage.KeyPrefix{0x00, 0x01}
+	leveldbKeyPrefixManifest         = leveldbstorage.KeyPrefix{0x01, 0x00}
+	leveldbKeyPrefixManifestHash     = leveldbstorage.KeyPrefix{0x01, 0x01}
+	leveldbKeyPrefixOperation        = leveldbstorage.KeyPrefix{0x02, 0x00}
+	leveldbKeyPrefixOperationFact    = leveldbstorage.KeyPrefix{0x02, 0x01}
+	leveldbKeyPrefixOperationAddress = leveldbstorage.KeyPrefix{0x02, 0x02}
+	leveldbKeyPrefixAccount          = leveldbstorage.KeyPrefix{0x03, 0x00}
+	leveldbKeyPrefixAccountKey       = leveldbstorage.KeyPrefix{0x03, 0x01}
+	leveldbKeyPrefixBalance          = leveldbstorage.KeyPrefix{0x04, 0x00}
+	leveldbKeyPrefixCurrency         = leveldbstorage.KeyPrefix{0x05, 0x00}
+	leveldbKeyPrefixContractAccount  = leveldbstorage.KeyPrefix{0x06, 0x00}
+	leveldbKeyPrefixStateDigest      = leveldbstorage.KeyPrefix{0x07, 0x00}
+)
+
+// LeveldbDatabase stores the digested blocks in the local leveldb. The
+// documents are same with the mongodb collections; the keys are built by the
+// queried fields and height, so the queries of Database are done by the
+// ranged iteration. Every key written at a height is also logged by the
+// height, CleanByHeight removes them by the log.
+type LeveldbDatabase struct {
+	sync.RWMutex
+	*logging.Logging
+	st        *leveldbstorage.Storage
+	encs      *encoder.Encoders
+	enc       encoder.Encoder
+	lastBlock base.Height
+}
+
+func NewLeveldbDatabase(path string, encs *encoder.Encoders, enc encoder.Encoder) (*LeveldbDatabase, error) {
+	str, err := leveldbStorage.OpenFile(path, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open leveldb digest database, %q", path)
+	}
+
+	st, err := leveldbstorage.NewStorage(str, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &LeveldbDatabase{
+		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
+			return c.Str("module", "digest-leveldb-database")
+		}),
+		st:        st,
+		encs:      encs,
+		enc:       enc,
+		lastBlock: base.NilHeight,
+	}
+
+	switch b, found, err := st.Get(leveldbInfoKey(DigestStorageLastBlockKey)); {
+	case err != nil:
+		return nil, errors.Wrap(err, "get last block for digest")
+	case found:
+		h, err := base.ParseHeightBytes(b)
+		if err != nil {
+			return nil, err
+		}
+
+		db.lastBlock = h
+	}
+
+	return db, nil
+}
+
+func (db *LeveldbDatabase) Readonly() bool {
+	return false
+}
+
+func (db *LeveldbDatabase) Close() error {
+	return db.st.Close()
+}
+
+func (db *LeveldbDatabase) LastBlock() base.Height {
+	db.RLock()
+	defer db.RUnlock()
+
+	return db.lastBlock
+}
+
+func (db *LeveldbDatabase) SetLastBlock(height base.Height) error {
+	db.Lock()
+	defer db.Unlock()
+
+	if height <= db.lastBlock {
+		return nil
+	}
+
+	return db.setLastBlock(height)
+}
+
+func (db *LeveldbDatabase) setLastBlock(height base.Height) error {
+	if height <= base.NilHeight {
+		if err := db.st.Delete(leveldbInfoKey(DigestStorageLastBlockKey), nil); err != nil {
+			return err
+		}
+	} else if err := db.st.Put(leveldbInfoKey(DigestStorageLastBlockKey), height.Bytes(), nil); err != nil {
+		return err
+	}
+
+	db.lastBlock = height
+	db.Log().Debug().Int64("height", height.Int64()).Msg("set last block")
+
+	return nil
+}
+
+func (db *LeveldbDatabase) Clean() error {
+	db.Lock()
+	defer db.Unlock()
+
+	if err := db.st.Clean(); err != nil {
+		return err
+	}
+
+	db.lastBlock = base.NilHeight
+
+	return nil
+}
+
+func (db *LeveldbDatabase) CleanByHeight(ctx context.Context, height base.Height) error {
+	if height <= base.GenesisHeight {
+		return db.Clean()
+	}
+
+	db.Lock()
+	defer db.Unlock()
+
+	batch := &leveldb.Batch{}
+	defer batch.Reset()
+
+	pfx := leveldbstorage.NewPrefixKey(leveldbKeyPrefixHeight)
+	r := leveldbutil.BytesPrefix(pfx)
+	r.Start = leveldbstorage.NewPrefixKey(leveldbKeyPrefixHeight, height.Bytes())
+
+	if err := db.st.Iter(r, func(key, _ []byte) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		batch.Delete(key[len(pfx)+8:])
+		batch.Delete(key)
+
+		return true, nil
+	}, true); err != nil {
+		return err
+	}
+
+	if err := db.st.Batch(batch, nil); err != nil {
+		return err
+	}
+
+	return db.setLastBlock(height - 1)
+}
+
+func (db *LeveldbDatabase) NewBlockSession(
+	blk base.BlockMap,
+	ops []base.Operation,
+	opsTree fixedtree.Tree,
+	sts []base.State,
+	proposal base.ProposalSignFact,
+	vs string,
+) (BlockSessioner, error) {
+	return &leveldbBlockSession{
+		BlockSession: newBlockSession(db.enc, blk, ops, opsTree, sts, proposal, vs),
+		db:           db,
+	}, nil
+}
+
+// Manifests returns block.Manifests by order and height.
+func (db *LeveldbDatabase) Manifests(
+	load bool,
+	reverse bool,
+	offset base.Height,
+	limit int64,
+	callback func(base.Height, base.Manifest, uint64, string, string, uint64) (bool, error),
+) error {
+	r := leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixManifest))
+	if offset > base.NilHeight {
+		if reverse {
+			r.Limit = leveldbManifestKey(offset)
+		} else {
+			r.Start = leveldbManifestKey(offset + 1)
+		}
+	}
+
+	limit = leveldbLimit(limit)
+
+	var called int64
+
+	return db.st.Iter(r, func(_, raw []byte) (bool, error) {
+		va, ops, confirmed, proposer, round, err := LoadManifest(leveldbDecoder(raw), db.encs)
+		if err != nil {
+			return false, err
+		}
+
+		called++
+
+		switch keep, err := callback(va.Height(), va, ops, confirmed, proposer, round); {
+		case err != nil, !keep:
+			return false, err
+		default:
+			return limit < 1 || called < limit, nil
+		}
+	}, !reverse)
+}
+
+func (db *LeveldbDatabase) ManifestByHeight(
+	height base.Height,
+) (base.Manifest, uint64, string, string, uint64, error) {
+	switch b, found, err := db.st.Get(leveldbManifestKey(height)); {
+	case err != nil:
+		return nil, 0, "", "", 0, err
+	case !found:
+		return nil, 0, "", "", 0, mitumutil.ErrNotFound.Errorf("Block manifest")
+	default:
+		return LoadManifest(leveldbDecoder(b), db.encs)
+	}
+}
+
+func (db *LeveldbDatabase) ManifestByHash(
+	hash mitumutil.Hash,
+) (base.Manifest, uint64, string, string, uint64, error) {
+	switch b, found, err := db.st.Get(leveldbstorage.NewPrefixKey(leveldbKeyPrefixManifestHash, hash.Bytes())); {
+	case err != nil:
+		return nil, 0, "", "", 0, err
+	case !found:
+		return nil, 0, "", "", 0, mitumutil.ErrNotFound.Errorf("Block manifest")
+	default:
+		h, err := base.ParseHeightBytes(b)
+		if err != nil {
+			return nil, 0, "", "", 0, err
+		}
+
+		return db.ManifestByHeight(h)
+	}
+}
+
+// Operation returns operation.Operation. If load is false, just returns nil
+// Operation.
+func (db *LeveldbDatabase) Operation(
+	h mitumutil.Hash, /* fact hash */
+	load bool,
+) (OperationValue, bool /* exists */, error) {
+	key, found, err := db.st.Get(leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperationFact, h.Bytes()))
+	switch {
+	case err != nil:
+		return OperationValue{}, false, err
+	case !found:
+		return OperationValue{}, false, nil
+	case !load:
+		return OperationValue{}, true, nil
+	}
+
+	switch b, found, err := db.st.Get(key); {
+	case err != nil:
+		return OperationValue{}, false, err
+	case !found:
+		return OperationValue{}, false, nil
+	default:
+		va, err := LoadOperation(leveldbDecoder(b), db.encs)
+		if err != nil {
+			return OperationValue{}, false, err
+		}
+
+		return va, true, nil
+	}
+}
+
+// Operations returns operation.Operations by order, height and index. The
+// total number of operations filtered by OperationFilter is passed to callback.
+// *  offset: returns from next of offset, "<height>,<index>".
+func (db *LeveldbDatabase) Operations(
+	of OperationFilter,
+	offset string,
+	reverse bool,
+	load bool,
+	limit int64,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
+) error {
+	r := leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperation))
+
+	count, err := db.countOperations(r, of)
+	if err != nil {
+		return err
+	}
+
+	if len(offset) > 0 {
+		height, index, err := parseOffset(offset)
+		if err != nil {
+			return err
+		}
+
+		if reverse {
+			r.Limit = leveldbOperationKey(height, index)
+		} else {
+			r.Start = leveldbOperationKey(height, index+1)
+		}
+	}
+
+	return db.operations(r, of, load, reverse, limit, count, callback)
+}
+
+// OperationsByHeight returns operation.Operations of the block by order of
+// index.
+// *  offset: returns from next of offset index.
+func (db *LeveldbDatabase) OperationsByHeight(
+	height base.Height,
+	offset string,
+	reverse bool,
+	load bool,
+	limit int64,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
+) error {
+	count, err := db.countOperations(
+		leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperation)),
+		OperationFilter{},
+	)
+	if err != nil {
+		return err
+	}
+
+	r := leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperation, height.Bytes()))
+
+	if len(offset) > 0 {
+		index, err := strconv.ParseUint(offset, 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "invalid index of offset")
+		}
+
+		if reverse {
+			r.Limit = leveldbOperationKey(height, index)
+		} else {
+			r.Start = leveldbOperationKey(height, index+1)
+		}
+	}
+
+	return db.operations(r, OperationFilter{}, load, reverse, limit, count, callback)
+}
+
+func (db *LeveldbDatabase) operations(
+	r *leveldbutil.Range,
+	of OperationFilter,
+	load bool,
+	reverse bool,
+	limit int64,
+	count int64,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
+) error {
+	limit = leveldbLimit(limit)
+
+	var called int64
+
+	return db.st.Iter(r, func(_, raw []byte) (bool, error) {
+		switch ok, err := of.match(raw); {
+		case err != nil:
+			return false, err
+		case !ok:
+			return true, nil
+		}
+
+		called++
+
+		var keep bool
+
+		if load {
+			va, err := LoadOperation(leveldbDecoder(raw), db.encs)
+			if err != nil {
+				return false, err
+			}
+
+			if keep, err = callback(va.Operation().Fact().Hash(), va, count); err != nil {
+				return false, err
+			}
+		} else {
+			h, err := LoadOperationHash(leveldbDecoder(raw))
+			if err != nil {
+				return false, err
+			}
+
+			if keep, err = callback(h, OperationValue{}, count); err != nil {
+				return false, err
+			}
+		}
+
+		return keep && (limit < 1 || called < limit), nil
+	}, !reverse)
+}
+
+func (db *LeveldbDatabase) countOperations(r *leveldbutil.Range, of OperationFilter) (int64, error) {
+	var count int64
+
+	err := db.st.Iter(r, func(_, raw []byte) (bool, error) {
+		switch ok, err := of.match(raw); {
+		case err != nil:
+			return false, err
+		case ok:
+			count++
+		}
+
+		return true, nil
+	}, true)
+
+	return count, err
+}
+
+// OperationsByHash returns operation.Operations by the fact hashes.
+func (db *LeveldbDatabase) OperationsByHash(
+	hashes []mitumutil.Hash,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue, int64) (bool, error),
+) error {
+	count, err := db.countOperations(
+		leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperation)),
+		OperationFilter{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for i := range hashes {
+		switch va, found, err := db.Operation(hashes[i], true); {
+		case err != nil:
+			return err
+		case !found:
+			continue
+		default:
+			switch keep, err := callback(va.Operation().Fact().Hash(), va, count); {
+			case err != nil:
+				return err
+			case !keep:
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+// OperationsByAddress finds the operation.Operations, which are related with
+// the given Address.
+// *  offset: returns from next of offset, "<height>,<index>".
+func (db *LeveldbDatabase) OperationsByAddress(
+	address base.Address,
+	load,
+	reverse bool,
+	offset string,
+	limit int64,
+	of OperationFilter,
+	callback func(mitumutil.Hash /* fact hash */, OperationValue) (bool, error),
+) error {
+	a := leveldbStringKey(address.String())
+	r := leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperationAddress, a))
+
+	if len(offset) > 0 {
+		height, index, err := parseOffset(offset)
+		if err != nil {
+			return err
+		}
+
+		if reverse {
+			r.Limit = leveldbstorage.NewPrefixKey(
+				leveldbKeyPrefixOperationAddress, a, height.Bytes(), mitumutil.Uint64ToBytes(index))
+		} else {
+			r.Start = leveldbstorage.NewPrefixKey(
+				leveldbKeyPrefixOperationAddress, a, height.Bytes(), mitumutil.Uint64ToBytes(index+1))
+		}
+	}
+
+	limit = leveldbLimit(limit)
+
+	var called int64
+
+	return db.st.Iter(r, func(_, key []byte) (bool, error) {
+		raw, found, err := db.st.Get(key)
+		switch {
+		case err != nil:
+			return false, err
+		case !found:
+			return true, nil
+		}
+
+		switch ok, err := of.match(raw); {
+		case err != nil:
+			return false, err
+		case !ok:
+			return true, nil
+		}
+
+		called++
+
+		var keep bool
+
+		if load {
+			va, err := LoadOperation(leveldbDecoder(raw), db.encs)
+			if err != nil {
+				return false, err
+			}
+
+			if keep, err = callback(va.Operation().Fact().Hash(), va); err != nil {
+				return false, err
+			}
+		} else {
+			h, err := LoadOperationHash(leveldbDecoder(raw))
+			if err != nil {
+				return false, err
+			}
+
+			if keep, err = callback(h, OperationValue{}); err != nil {
+				return false, err
+			}
+		}
+
+		return keep && (limit < 1 || called < limit), nil
+	}, !reverse)
+}
+
+// Account returns AccountValue with the balance and contract account status.
+func (db *LeveldbDatabase) Account(a base.Address) (AccountValue, bool /* exists */, error) {
+	raw, found, err := db.last(leveldbutil.BytesPrefix(
+		leveldbstorage.NewPrefixKey(leveldbKeyPrefixAccount, leveldbStringKey(a.String()))))
+	switch {
+	case err != nil:
+		return AccountValue{}, false, err
+	case !found:
+		return AccountValue{}, false, nil
+	}
+
+	rs, err := LoadAccountValue(leveldbDecoder(raw), db.encs)
+	if err != nil {
+		return rs, false, err
+	}
+
+	// NOTE load balance
+	switch am, lastHeight, err := db.balance(a.String()); {
+	case err != nil:
+		return rs, false, err
+	case len(am) < 1:
+	default:
+		rs = rs.SetBalance(am).
+			SetHeight(lastHeight)
+	}
+
+	// NOTE load contract account status
+	switch sta, found, err := db.lastState(leveldbutil.BytesPrefix(
+		leveldbstorage.NewPrefixKey(leveldbKeyPrefixContractAccount, leveldbStringKey(a.String()))),
+		LoadContractAccountStatus,
+	); {
+	case err != nil, !found:
+	default:
+		if cas, err := extension.StateContractAccountValue(sta); err == nil {
+			rs = rs.SetContractAccountStatus(cas).
+				SetHeight(sta.Height())
+		}
+	}
+
+	return rs, true, nil
+}
+
+func (db *LeveldbDatabase) balance(a string) ([]types.Amount, base.Height, error) {
+	lastHeight := base.NilHeight
+
+	var ams []types.Amount
+	var lastKey []byte
+	var lastRaw []byte
+
+	addAmount := func() error {
+		if lastRaw == nil {
+			return nil
+		}
+
+		sta, err := LoadBalance(leveldbDecoder(lastRaw), db.encs)
+		if err != nil {
+			return err
+		}
+
+		am, err := currency.StateBalanceValue(sta)
+		if err != nil {
+			return err
+		}
+
+		ams = append(ams, am)
+
+		if h := sta.Height(); h > lastHeight {
+			lastHeight = h
+		}
+
+		return nil
+	}
+
+	// NOTE keys are sorted by currency and height, the last key of currency
+	// is the latest balance.
+	if err := db.st.Iter(
+		leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, leveldbStringKey(a))),
+		func(key, raw []byte) (bool, error) {
+			if lastKey != nil && !bytes.Equal(key[:len(key)-8], lastKey[:len(lastKey)-8]) {
+				if err := addAmount(); err != nil {
+					return false, err
+				}
+			}
+
+			lastKey = key
+			lastRaw = raw
+
+			return true, nil
+		},
+		true,
+	); err != nil {
+		return nil, lastHeight, err
+	}
+
+	if err := addAmount(); err != nil {
+		return nil, lastHeight, err
+	}
+
+	return ams, lastHeight, nil
+}
+
+// AccountsByPublickey finds Accounts, which are related with the given
+// Publickey.
+// *  offset: returns from next of offset, usually it is "<height>,<address>".
+func (db *LeveldbDatabase) AccountsByPublickey(
+	pub base.Publickey,
+	loadBalance bool,
+	offsetHeight base.Height,
+	offsetAddress string,
+	limit int64,
+	callback func(AccountValue) (bool, error),
+) error {
+	if offsetHeight <= base.NilHeight {
+		return errors.Errorf("Offset height should be over nil height")
+	}
+
+	sas, err := db.addressesByPublickey(pub, offsetHeight)
+	if err != nil {
+		return err
+	}
+
+	var called int64
+
+	for i := range sas {
+		if len(offsetAddress) > 0 && sas[i] <= offsetAddress {
+			continue
+		}
+
+		if limit > 0 && called == limit {
+			return nil
+		}
+
+		raw, found, err := db.last(leveldbutil.BytesPrefix(
+			leveldbstorage.NewPrefixKey(leveldbKeyPrefixAccount, leveldbStringKey(sas[i]))))
+		switch {
+		case err != nil:
+			return err
+		case !found:
+			continue
+		}
+
+		doc, err := loadBriefAccountDoc(leveldbDecoder(raw))
+		if err != nil {
+			return err
+		}
+
+		if !doc.pubExists(pub) {
+			continue
+		}
+
+		va, err := LoadAccountValue(leveldbDecoder(raw), db.encs)
+		if err != nil {
+			return err
+		}
+
+		if loadBalance { // NOTE load balance
+			am, lastHeight, err := db.balance(sas[i])
+			if err != nil {
+				return err
+			}
+
+			va = va.SetBalance(am).
+				SetHeight(lastHeight)
+		}
+
+		called++
+
+		switch keep, err := callback(va); {
+		case err != nil:
+			return err
+		case !keep:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (db *LeveldbDatabase) TopHeightByPublickey(pub base.Publickey) (base.Height, error) {
+	sas, err := db.addressesByPublickey(pub, base.NilHeight)
+	switch {
+	case err != nil:
+		return base.NilHeight, err
+	case len(sas) < 1:
+		return base.NilHeight, nil
+	}
+
+	var top base.Height
+
+	for i := range sas {
+		var h base.Height
+
+		if err := db.st.Iter(
+			leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixAccount, leveldbStringKey(sas[i]))),
+			func(key, _ []byte) (bool, error) {
+				h = leveldbKeyHeight(key)
+
+				return false, nil
+			},
+			false,
+		); err != nil {
+			return base.NilHeight, err
+		}
+
+		if h > top {
+			top = h
+		}
+	}
+
+	return top, nil
+}
+
+// addressesByPublickey returns the sorted addresses, which have used the
+// publickey at or before the given height.
+func (db *LeveldbDatabase) addressesByPublickey(pub base.Publickey, height base.Height) ([]string, error) {
+	pfx := leveldbstorage.NewPrefixKey(leveldbKeyPrefixAccountKey, leveldbStringKey(pub.String()))
+
+	var sas []string
+
+	if err := db.st.Iter(leveldbutil.BytesPrefix(pfx), func(key, _ []byte) (bool, error) {
+		if height > base.NilHeight && leveldbKeyHeight(key) > height {
+			return true, nil
+		}
+
+		a := string(key[len(pfx) : len(key)-9])
+		if len(sas) < 1 || sas[len(sas)-1] != a {
+			sas = append(sas, a)
+		}
+
+		return true, nil
+	}, true); err != nil {
+		return nil, err
+	}
+
+	return sas, nil
+}
+
+// BalanceHistory returns the balance states of currency of the account by
+// height.
+func (db *LeveldbDatabase) BalanceHistory(
+	address base.Address,
+	cid string,
+	fromHeight,
+	toHeight base.Height,
+	offset base.Height,
+	reverse bool,
+	limit int64,
+	callback func(base.State) (bool, error),
+) error {
+	a := leveldbStringKey(address.String())
+	c := leveldbStringKey(cid)
+
+	r := leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c))
+
+	if fromHeight > base.NilHeight {
+		r.Start = leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c, fromHeight.Bytes())
+	}
+
+	if toHeight > base.NilHeight {
+		r.Limit = leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c, (toHeight + 1).Bytes())
+	}
+
+	if offset > base.NilHeight {
+		if reverse {
+			if toHeight <= base.NilHeight || toHeight >= offset {
+				r.Limit = leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c, offset.Bytes())
+			}
+		} else if fromHeight <= offset {
+			r.Start = leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c, (offset + 1).Bytes())
+		}
+	}
+
+	limit = leveldbLimit(limit)
+
+	var called int64
+
+	return db.st.Iter(r, func(_, raw []byte) (bool, error) {
+		sta, err := LoadBalance(leveldbDecoder(raw), db.encs)
+		if err != nil {
+			return false, err
+		}
+
+		called++
+
+		switch keep, err := callback(sta); {
+		case err != nil, !keep:
+			return false, err
+		default:
+			return limit < 1 || called < limit, nil
+		}
+	}, !reverse)
+}
+
+// BalanceByHeight returns the balance state of currency of the account at the
+// given height, that is, the last balance state at or before the height.
+func (db *LeveldbDatabase) BalanceByHeight(
+	address base.Address,
+	cid string,
+	height base.Height,
+) (base.State, bool, error) {
+	a := leveldbStringKey(address.String())
+	c := leveldbStringKey(cid)
+
+	r := leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c))
+	r.Limit = leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance, a, c, (height + 1).Bytes())
+
+	return db.lastState(r, LoadBalance)
+}
+
+// CurrencyHolders returns the accounts holding the currency, sorted by the
+// latest balance. The accounts with zero balance are not counted.
+func (db *LeveldbDatabase) CurrencyHolders(
+	cid string,
+	offset,
+	limit int64,
+) ([]CurrencyHolder, int64, error) {
+	switch {
+	case limit <= 0:
+		limit = maxLimit
+	case limit > maxLimit:
+		limit = maxLimit
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	var holders []CurrencyHolder
+	var last *CurrencyHolder
+
+	pfx := leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance)
+	c := leveldbStringKey(cid)
+
+	addHolder := func() {
+		if last != nil && last.Amount.OverZero() {
+			holders = append(holders, *last)
+		}
+
+		last = nil
+	}
+
+	if err := db.st.Iter(leveldbutil.BytesPrefix(pfx), func(key, raw []byte) (bool, error) {
+		rest := key[len(pfx) : len(key)-8]
+
+		i := bytes.IndexByte(rest, 0x00)
+		if i < 0 || !bytes.Equal(rest[i+1:], c) {
+			return true, nil
+		}
+
+		a := string(rest[:i])
+		if last != nil && last.Address != a {
+			addHolder()
+		}
+
+		var doc struct {
+			Amount string `bson:"amount"`
+		}
+
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return false, err
+		}
+
+		am, err := common.NewBigFromString(doc.Amount)
+		if err != nil {
+			return false, err
+		}
+
+		last = &CurrencyHolder{Address: a, Amount: am, Height: leveldbKeyHeight(key)}
+
+		return true, nil
+	}, true); err != nil {
+		return nil, 0, err
+	}
+
+	addHolder()
+
+	sort.SliceStable(holders, func(i, j int) bool {
+		switch k := holders[i].Amount.Compare(holders[j].Amount); {
+		case k != 0:
+			return k > 0
+		default:
+			return holders[i].Address < holders[j].Address
+		}
+	})
+
+	total := int64(len(holders))
+	if offset >= total {
+		return nil, total, nil
+	}
+
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	l := holders[offset:end]
+	for i := range l {
+		l[i].Rank = offset + int64(i) + 1
+	}
+
+	return l, total, nil
+}
+
+// CurrencyBalanceSum returns the sum of the latest balances of the currency
+// of the given addresses.
+func (db *LeveldbDatabase) CurrencyBalanceSum(cid string, addresses []string) (common.Big, error) {
+	sum := common.ZeroBig
+
+	for i := range addresses {
+		sta, found, err := db.lastState(leveldbutil.BytesPrefix(
+			leveldbstorage.NewPrefixKey(
+				leveldbKeyPrefixBalance, leveldbStringKey(addresses[i]), leveldbStringKey(cid))),
+			LoadBalance,
+		)
+		switch {
+		case err != nil:
+			return common.ZeroBig, err
+		case !found:
+			continue
+		}
+
+		am, err := currency.StateBalanceValue(sta)
+		if err != nil {
+			return common.ZeroBig, err
+		}
+
+		sum = sum.Add(am.Big())
+	}
+
+	return sum, nil
+}
+
+// CurrencySupplyBalances calls callback with the latest balances of the
+// accounts and the latest deposits of the suffrage bonds of the currency. The
+// balances are iterated once by key order.
+func (db *LeveldbDatabase) CurrencySupplyBalances(
+	cid string,
+	callback func(key string, amount common.Big) (bool, error),
+) error {
+	c := leveldbStringKey(cid)
+
+	var lastKey string
+	var lastAmount *common.Big
+
+	flush := func() (bool, error) {
+		if lastAmount == nil {
+			return true, nil
+		}
+
+		am := *lastAmount
+		lastAmount = nil
+
+		return callback(lastKey, am)
+	}
+
+	iter := func(pfx []byte, key func([]byte) (string, bool)) (bool, error) {
+		keep := true
+
+		if err := db.st.Iter(leveldbutil.BytesPrefix(pfx), func(k, raw []byte) (bool, error) {
+			a, ok := key(k[len(pfx) : len(k)-8])
+			if !ok {
+				return true, nil
+			}
+
+			if lastAmount != nil && lastKey != a {
+				switch i, err := flush(); {
+				case err != nil:
+					return false, err
+				case !i:
+					keep = false
+
+					return false, nil
+				}
+			}
+
+			var doc struct {
+				Amount string `bson:"amount"`
+			}
+
+			if err := bson.Unmarshal(raw, &doc); err != nil {
+				return false, err
+			}
+
+			am, err := common.NewBigFromString(doc.Amount)
+			if err != nil {
+				return false, err
+			}
+
+			lastKey, lastAmount = a, &am
+
+			return true, nil
+		}, true); err != nil {
+			return false, err
+		}
+
+		if !keep {
+			return false, nil
+		}
+
+		return flush()
+	}
+
+	// NOTE balance key is <address>0x00<currency>0x00<height>.
+	switch keep, err := iter(leveldbstorage.NewPrefixKey(leveldbKeyPrefixBalance), func(rest []byte) (string, bool) {
+		i := bytes.IndexByte(rest, 0x00)
+		if i < 0 || !bytes.Equal(rest[i+1:], c) {
+			return "", false
+		}
+
+		return string(rest[:i]), true
+	}); {
+	case err != nil:
+		return err
+	case !keep:
+		return nil
+	}
+
+	// NOTE the documents of suffrage bond are iterated by height, so the last
+	// document of node is the latest; the key of callback is the state key of
+	// bond.
+	bonds := map[string]common.Big{}
+
+	if err := db.stateDigestDocs(defaultColNameSuffrageBond, func(_ []byte, raw bson.Raw) (bool, error) {
+		var doc struct {
+			Node     string `bson:"node"`
+			Currency string `bson:"currency"`
+			Amount   string `bson:"amount"`
+		}
+
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return false, err
+		}
+
+		if doc.Currency != cid {
+			return true, nil
+		}
+
+		am, err := common.NewBigFromString(doc.Amount)
+		if err != nil {
+			return false, err
+		}
+
+		bonds[doc.Node+currency.SuffrageBondStateKeySuffix] = am
+
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(bonds))
+	for k := range bonds {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for i := range keys {
+		switch keep, err := callback(keys[i], bonds[keys[i]]); {
+		case err != nil:
+			return err
+		case !keep:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (db *LeveldbDatabase) Currencies() ([]string, error) {
+	pfx := leveldbstorage.NewPrefixKey(leveldbKeyPrefixCurrency)
+
+	var cids []string
+
+	if err := db.st.Iter(leveldbutil.BytesPrefix(pfx), func(key, _ []byte) (bool, error) {
+		cid := string(key[len(pfx) : len(key)-9])
+		if len(cids) < 1 || cids[len(cids)-1] != cid {
+			cids = append(cids, cid)
+		}
+
+		return true, nil
+	}, true); err != nil {
+		return nil, err
+	}
+
+	return cids, nil
+}
+
+func (db *LeveldbDatabase) Currency(cid string) (types.CurrencyDesign, base.State, error) {
+	switch sta, found, err := db.lastState(
+		leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixCurrency, leveldbStringKey(cid))),
+		LoadCurrency,
+	); {
+	case err != nil:
+		return types.CurrencyDesign{}, nil, err
+	case !found:
+		return types.CurrencyDesign{}, nil, mitumutil.ErrNotFound.Errorf("Currency, %q", cid)
+	default:
+		de, err := currency.GetDesignFromState(sta)
+		if err != nil {
+			return types.CurrencyDesign{}, nil, err
+		}
+
+		return de, sta, nil
+	}
+}
+
+func (db *LeveldbDatabase) last(r *leveldbutil.Range) ([]byte, bool, error) {
+	var b []byte
+
+	if err := db.st.Iter(r, func(_, raw []byte) (bool, error) {
+		b = raw
+
+		return false, nil
+	}, false); err != nil {
+		return nil, false, err
+	}
+
+	return b, b != nil, nil
+}
+
+func (db *LeveldbDatabase) lastState(
+	r *leveldbutil.Range,
+	load func(func(interface{}) error, *encoder.Encoders) (base.State, error),
+) (base.State, bool, error) {
+	switch raw, found, err := db.last(r); {
+	case err != nil, !found:
+		return nil, false, err
+	default:
+		sta, err := load(leveldbDecoder(raw), db.encs)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return sta, true, nil
+	}
+}
+
+type leveldbBlockSession struct {
+	*BlockSession
+	db *LeveldbDatabase
+}
+
+// Commit writes the prepared documents into leveldb in one batch.
+func (bs *leveldbBlockSession) Commit(ctx context.Context) error {
+	bs.Lock()
+	defer bs.Unlock()
+
+	started := time.Now()
+	defer func() {
+		bs.statesValue.Store("commit", time.Since(started))
+		metrics.DigestBlockSessionCommit.Since(started)
+		_ = bs.close()
+	}()
+
+	batch := &leveldb.Batch{}
+	defer batch.Reset()
+
+	var height base.Height
+	if bs.block != nil {
+		height = bs.block.Manifest().Height()
+	}
+
+	cms := bs.collectionModels()
+
+	for i := range cms {
+		if !isBuiltinCollection(cms[i].col) {
+			w := newLeveldbStateDigestWriter(bs.db, batch, cms[i].col, height)
+			writes := bs.stateDigestWrites[cms[i].col]
+
+			for j := range writes {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				if err := w.write(writes[j]); err != nil {
+					return errors.WithMessagef(err, "state digest, %q", cms[i].col)
+				}
+			}
+
+			continue
+		}
+
+		for j := range cms[i].models {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			m, ok := cms[i].models[j].(*mongo.InsertOneModel)
+			if !ok {
+				return errors.Errorf("Expected InsertOneModel, not %T", cms[i].models[j])
+			}
+
+			raw, err := bson.Marshal(m.Document)
+			if err != nil {
+				return err
+			}
+
+			items, err := leveldbDocItems(cms[i].col, raw)
+			if err != nil {
+				return err
+			}
+
+			for k := range items {
+				batch.Put(items[k].key, items[k].value)
+				batch.Put(leveldbstorage.NewPrefixKey(leveldbKeyPrefixHeight, height.Bytes(), items[k].key), nil)
+			}
+		}
+	}
+
+	return bs.db.st.Batch(batch, nil)
+}
+
+// leveldbStateDigestWriter applies the DigestWrites of StateDigest to the
+// documents of collection. The matched documents are found by iterating the collection, so it is for the small
+// collections like the other queries of LeveldbDatabase.
+//
+// The replaced or updated document is written at the current height like
+// mongodb, so CleanByHeight removes it and does not restore the previous one.
+type leveldbStateDigestWriter struct {
+	db      *LeveldbDatabase
+	batch   *leveldb.Batch
+	written map[string][]byte
+	deleted map[string]struct{}
+	col     string
+	height  base.Height
+	seq     uint64
+}
+
+func newLeveldbStateDigestWriter(
+	db *LeveldbDatabase, batch *leveldb.Batch, col string, height base.Height,
+) *leveldbStateDigestWriter {
+	return &leveldbStateDigestWriter{
+		db:      db,
+		batch:   batch,
+		col:     col,
+		height:  height,
+		written: map[string][]byte{},
+		deleted: map[string]struct{}{},
+	}
+}
+
+func (w *leveldbStateDigestWriter) write(dw DigestWrite) error {
+	if err := dw.IsValid(nil); err != nil {
+		return err
+	}
+
+	switch dw.kind {
+	case DigestWriteInsert:
+		return w.insert(dw.doc)
+	case DigestWriteReplace:
+		return w.replace(dw.filter, dw.doc, dw.upsert)
+	case DigestWriteUpdate:
+		return w.update(dw.filter, dw.set, dw.unset, dw.upsert, dw.many)
+	case DigestWriteDelete:
+		return w.remove(dw.filter, dw.many)
+	default:
+		return errors.Errorf("Unknown write kind, %d", dw.kind)
+	}
+}
+
+func (w *leveldbStateDigestWriter) insert(doc interface{}) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	w.put(raw)
+
+	return nil
+}
+
+func (w *leveldbStateDigestWriter) replace(filter bson.D, doc interface{}, upsert bool) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	keys, err := w.find(filter, false)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(keys) > 0:
+		w.delete(keys[0])
+	case !upsert:
+		return nil
+	}
+
+	w.put(raw)
+
+	return nil
+}
+
+func (w *leveldbStateDigestWriter) update(filter, set bson.D, unset []string, upsert, many bool) error {
+	keys, err := w.find(filter, many)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) < 1 {
+		if !upsert {
+			return nil
+		}
+
+		raw, err := bson.Marshal(leveldbUpdateDoc(leveldbFilterFields(filter), set, unset))
+		if err != nil {
+			return err
+		}
+
+		w.put(raw)
+
+		return nil
+	}
+
+	for i := range keys {
+		b, err := w.get(keys[i])
+		if err != nil {
+			return err
+		}
+
+		var doc bson.D
+		if err := bson.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+
+		raw, err := bson.Marshal(leveldbUpdateDoc(doc, set, unset))
+		if err != nil {
+			return err
+		}
+
+		w.delete(keys[i])
+		w.put(raw)
+	}
+
+	return nil
+}
+
+func (w *leveldbStateDigestWriter) remove(filter bson.D, many bool) error {
+	keys, err := w.find(filter, many)
+	if err != nil {
+		return err
+	}
+
+	for i := range keys {
+		w.delete(keys[i])
+	}
+
+	return nil
+}
+
+// put writes the document by the height of block, not by the height field of
+// document; the sequence of key increases by put, so the documents of this
+// session are kept by the written order.
+func (w *leveldbStateDigestWriter) put(raw []byte) {
+	key := leveldbstorage.NewPrefixKey(
+		leveldbKeyPrefixStateDigest,
+		leveldbStringKey(w.col),
+		w.height.Bytes(),
+		mitumutil.Uint64ToBytes(w.seq),
+	)
+
+	w.seq++
+
+	w.batch.Put(key, raw)
+	w.batch.Put(leveldbstorage.NewPrefixKey(leveldbKeyPrefixHeight, w.height.Bytes(), key), nil)
+
+	w.written[string(key)] = raw
+	delete(w.deleted, string(key))
+}
+
+func (w *leveldbStateDigestWriter) delete(key string) {
+	w.batch.Delete([]byte(key))
+	w.batch.Delete(leveldbstorage.NewPrefixKey(
+		leveldbKeyPrefixHeight, leveldbStateDigestKeyHeight(w.col, []byte(key)).Bytes(), []byte(key)))
+
+	delete(w.written, key)
+	w.deleted[key] = struct{}{}
+}
+
+func (w *leveldbStateDigestWriter) get(key string) ([]byte, error) {
+	if raw, found := w.written[key]; found {
+		return raw, nil
+	}
+
+	switch raw, found, err := w.db.st.Get([]byte(key)); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, mitumutil.ErrNotFound.Errorf("state digest document")
+	default:
+		return raw, nil
+	}
+}
+
+// find returns the keys of matched documents; the stored documents come first
+// by the key order, and then the documents written in this session.
+func (w *leveldbStateDigestWriter) find(filter bson.D, many bool) ([]string, error) {
+	f, err := leveldbFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+
+	if err := w.db.stateDigestDocs(w.col, func(key []byte, raw bson.Raw) (bool, error) {
+		if _, found := w.deleted[string(key)]; found {
+			return true, nil
+		}
+
+		if _, found := w.written[string(key)]; found {
+			return true, nil
+		}
+
+		switch ok, err := f(raw); {
+		case err != nil:
+			return false, err
+		case ok:
+			keys = append(keys, string(key))
+		}
+
+		return many || len(keys) < 1, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if !many && len(keys) > 0 {
+		return keys, nil
+	}
+
+	written := make([]string, 0, len(w.written))
+	for k := range w.written {
+		written = append(written, k)
+	}
+
+	sort.Strings(written)
+
+	for i := range written {
+		switch ok, err := f(w.written[written[i]]); {
+		case err != nil:
+			return nil, err
+		case !ok:
+			continue
+		}
+
+		keys = append(keys, written[i])
+
+		if !many {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
+// stateDigestDocs iterates the documents of StateDigest collection by the
+// height and order.
+func (db *LeveldbDatabase) stateDigestDocs(col string, callback func([]byte, bson.Raw) (bool, error)) error {
+	return db.st.Iter(
+		leveldbutil.BytesPrefix(leveldbstorage.NewPrefixKey(leveldbKeyPrefixStateDigest, leveldbStringKey(col))),
+		func(key, raw []byte) (bool, error) {
+			return callback(key, raw)
+		},
+		true,
+	)
+}
+
+// leveldbFilter returns the matcher of the equality filter.
+func leveldbFilter(filter bson.D) (func(bson.Raw) (bool, error), error) {
+	var elems []bson.RawElement
+
+	if len(filter) > 0 {
+		b, err := bson.Marshal(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		i, err := bson.Raw(b).Elements()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		elems = i
+	}
+
+	return func(doc bson.Raw) (bool, error) {
+		for i := range elems {
+			v, err := doc.LookupErr(strings.Split(elems[i].Key(), ".")...)
+			if err != nil {
+				return false, nil //nolint:nilerr //...
+			}
+
+			if !leveldbEqualValue(v, elems[i].Value()) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}, nil
+}
+
+// leveldbEqualValue compares the values; the numbers are compared by value
+// regardless of the bson number types.
+func leveldbEqualValue(a, b bson.RawValue) bool {
+	if a.Equal(b) {
+		return true
+	}
+
+	if ai, ok := leveldbIntValue(a); ok {
+		if bi, ok := leveldbIntValue(b); ok {
+			return ai == bi
+		}
+	}
+
+	af, aok := leveldbFloatValue(a)
+	bf, bok := leveldbFloatValue(b)
+
+	return aok && bok && af == bf
+}
+
+func leveldbIntValue(v bson.RawValue) (int64, bool) {
+	switch v.Type {
+	case bsontype.Int32:
+		return int64(v.Int32()), true
+	case bsontype.Int64:
+		return v.Int64(), true
+	default:
+		return 0, false
+	}
+}
+
+func leveldbFloatValue(v bson.RawValue) (float64, bool) {
+	switch v.Type {
+	case bsontype.Int32:
+		return float64(v.Int32()), true
+	case bsontype.Int64:
+		return float64(v.Int64()), true
+	case bsontype.Double:
+		return v.Double(), true
+	default:
+		return 0, false
+	}
+}
+
+// leveldbFilterFields returns the fields of equality filter to build the
+// upserted document.
+func leveldbFilterFields(filter bson.D) bson.D {
+	fields := bson.D{}
+
+	for i := range filter {
+		if strings.Contains(filter[i].Key, ".") {
+			continue
+		}
+
+		fields = append(fields, filter[i])
+	}
+
+	return fields
+}
+
+func leveldbUpdateDoc(doc, set bson.D, unset []string) bson.D {
+	n := make(bson.D, 0, len(doc)+len(set))
+
+	for i := range doc {
+		if !leveldbContains(unset, doc[i].Key) {
+			n = append(n, doc[i])
+		}
+	}
+
+end:
+	for i := range set {
+		for j := range n {
+			if n[j].Key == set[i].Key {
+				n[j].Value = set[i].Value
+
+				continue end
+			}
+		}
+
+		n = append(n, set[i])
+	}
+
+	return n
+}
+
+type leveldbDocFields struct {
+	Address     string          `bson:"address"`
+	Currency    string          `bson:"currency"`
+	Hint        string          `bson:"hint"`
+	ConfirmedAt time.Time       `bson:"confirmed_at"`
+	Fact        valuehash.Bytes `bson:"fact"`
+	Block       valuehash.Bytes `bson:"block"`
+	Addresses   []string        `bson:"addresses"`
+	Currencies  []string        `bson:"currencies"`
+	Pubs        []string        `bson:"pubs"`
+	Height      base.Height     `bson:"height"`
+	Index       uint64          `bson:"index"`
+	InState     bool            `bson:"in_state"`
+}
+
+type leveldbDocItem struct {
+	key   []byte
+	value []byte
+}
+
+// leveldbDocItems returns the document and it's index items of collection.
+// The documents of StateDigest are written by leveldbStateDigestWriter.
+func leveldbDocItems(col string, raw []byte) ([]leveldbDocItem, error) {
+	var doc leveldbDocFields
+	if col != defaultColNameBlock {
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	switch col {
+	case defaultColNameBlock:
+		var m struct {
+			Block  valuehash.Bytes `bson:"block"`
+			Height base.Height     `bson:"height"`
+		}
+
+		if err := bson.Unmarshal(raw, &m); err != nil {
+			return nil, err
+		}
+
+		return []leveldbDocItem{
+			{key: leveldbManifestKey(m.Height), value: raw},
+			{key: leveldbstorage.NewPrefixKey(leveldbKeyPrefixManifestHash, m.Block), value: m.Height.Bytes()},
+		}, nil
+	case defaultColNameOperation:
+		key := leveldbOperationKey(doc.Height, doc.Index)
+
+		items := []leveldbDocItem{
+			{key: key, value: raw},
+			{key: leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperationFact, doc.Fact), value: key},
+		}
+
+		for i := range doc.Addresses {
+			items = append(items, leveldbDocItem{
+				key: leveldbstorage.NewPrefixKey(
+					leveldbKeyPrefixOperationAddress,
+					leveldbStringKey(doc.Addresses[i]),
+					doc.Height.Bytes(),
+					mitumutil.Uint64ToBytes(doc.Index),
+				),
+				value: key,
+			})
+		}
+
+		return items, nil
+	case defaultColNameAccount:
+		a := leveldbStringKey(doc.Address)
+
+		items := []leveldbDocItem{
+			{key: leveldbstorage.NewPrefixKey(leveldbKeyPrefixAccount, a, doc.Height.Bytes()), value: raw},
+		}
+
+		for i := range doc.Pubs {
+			items = append(items, leveldbDocItem{
+				key: leveldbstorage.NewPrefixKey(
+					leveldbKeyPrefixAccountKey, leveldbStringKey(doc.Pubs[i]), a, doc.Height.Bytes()),
+			})
+		}
+
+		return items, nil
+	case defaultColNameContractAccount:
+		return []leveldbDocItem{{
+			key: leveldbstorage.NewPrefixKey(
+				leveldbKeyPrefixContractAccount, leveldbStringKey(doc.Address), doc.Height.Bytes()),
+			value: raw,
+		}}, nil
+	case defaultColNameBalance:
+		return []leveldbDocItem{{
+			key: leveldbstorage.NewPrefixKey(
+				leveldbKeyPrefixBalance,
+				leveldbStringKey(doc.Address),
+				leveldbStringKey(doc.Currency),
+				doc.Height.Bytes(),
+			),
+			value: raw,
+		}}, nil
+	case defaultColNameCurrency:
+		return []leveldbDocItem{{
+			key: leveldbstorage.NewPrefixKey(
+				leveldbKeyPrefixCurrency, leveldbStringKey(doc.Currency), doc.Height.Bytes()),
+			value: raw,
+		}}, nil
+	default:
+		return nil, errors.Errorf("Unknown digest collection, %q", col)
+	}
+}
+
+// match checks the operation document by filter like OperationFilter.BSON.
+func (f OperationFilter) match(raw []byte) (bool, error) {
+	if f.IsEmpty() {
+		return true, nil
+	}
+
+	var doc leveldbDocFields
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return false, err
+	}
+
+	switch {
+	case len(f.hints) > 0 && !leveldbContains(f.hints, doc.Hint):
+		return false, nil
+	case f.inState != nil && *f.inState != doc.InState:
+		return false, nil
+	case f.fromHeight != nil && doc.Height < *f.fromHeight,
+		f.toHeight != nil && doc.Height > *f.toHeight:
+		return false, nil
+	case !f.fromDate.IsZero() && doc.ConfirmedAt.Before(f.fromDate),
+		!f.toDate.IsZero() && doc.ConfirmedAt.After(f.toDate):
+		return false, nil
+	}
+
+	if len(f.currencies) > 0 {
+		var found bool
+		for i := range doc.Currencies {
+			if leveldbContains(f.currencies, doc.Currencies[i]) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false, nil
+		}
+	}
+
+	for i := range f.counterparty {
+		if !leveldbContains(doc.Addresses, f.counterparty[i]) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func leveldbContains(l []string, s string) bool {
+	for i := range l {
+		if l[i] == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func leveldbDecoder(raw []byte) func(interface{}) error {
+	return func(v interface{}) error {
+		if r, ok := v.(*bson.Raw); ok {
+			*r = raw
+
+			return nil
+		}
+
+		return bson.Unmarshal(raw, v)
+	}
+}
+
+func leveldbLimit(limit int64) int64 {
+	switch {
+	case limit <= 0: // no limit
+		return 0
+	case limit > maxLimit:
+		return maxLimit
+	default:
+		return limit
+	}
+}
+
+func leveldbInfoKey(key string) []byte {
+	return leveldbstorage.NewPrefixKey(leveldbKeyPrefixInfo, []byte(key))
+}
+
+func leveldbManifestKey(height base.Height) []byte {
+	return leveldbstorage.NewPrefixKey(leveldbKeyPrefixManifest, height.Bytes())
+}
+
+func leveldbOperationKey(height base.Height, index uint64) []byte {
+	return leveldbstorage.NewPrefixKey(leveldbKeyPrefixOperation, height.Bytes(), mitumutil.Uint64ToBytes(index))
+}
+
+// leveldbStringKey returns the key part of string; the string is terminated
+// by 0x00 not to be mixed with the longer string.
+func leveldbStringKey(s string) []byte {
+	return append([]byte(s), 0x00)
+}
+
+// leveldbStateDigestKeyHeight returns the height of StateDigest document key.
+func leveldbStateDigestKeyHeight(col string, key []byte) base.Height {
+	i := len(leveldbstorage.NewPrefixKey(leveldbKeyPrefixStateDigest, leveldbStringKey(col)))
+	h, _ := mitumutil.BigBytesToInt64(key[i : i+8])
+
+	return base.Height(h)
+}
+
+// leveldbKeyHeight returns the height at the end of key.
+func leveldbKeyHeight(key []byte) base.Height {
+	i, _ := mitumutil.BigBytesToInt64(key[len(key)-8:])
+
+	return base.Height(i)
+}
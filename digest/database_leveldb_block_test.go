@@ -0,0 +1,417 @@
+package digest_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/cmds"
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/digest"
+	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+	isaacblock "github.com/ProtoconNet/mitum2/isaac/block"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+)
+
+var testNetworkID = base.NetworkID("digest-test")
+
+type testLeveldbBlocks struct {
+	t      *testing.T
+	db     *digest.LeveldbDatabase
+	enc    encoder.Encoder
+	priv   base.Privatekey
+	sender base.Address
+	cid    types.CurrencyID
+}
+
+func newTestLeveldbBlocks(t *testing.T) *testLeveldbBlocks {
+	t.Helper()
+
+	jenc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(jenc, jenc)
+	benc := bsonenc.NewEncoder()
+
+	if err := encs.AddEncoder(benc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmds.LoadHinters(encs); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := digest.NewLeveldbDatabase(t.TempDir(), encs, benc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	priv := types.NewMEPrivatekey()
+
+	key, err := types.NewBaseAccountKey(priv.Publickey(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := types.NewBaseAccountKeys([]types.AccountKey{key}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sender, err := types.NewAddressFromKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testLeveldbBlocks{t: t, db: db, enc: jenc, priv: priv, sender: sender, cid: types.CurrencyID("MCC")}
+}
+
+func (b *testLeveldbBlocks) keys() types.AccountKeys {
+	key, _ := types.NewBaseAccountKey(b.priv.Publickey(), 100)
+	keys, _ := types.NewBaseAccountKeys([]types.AccountKey{key}, 100)
+
+	return keys
+}
+
+func (b *testLeveldbBlocks) transfer(receiver base.Address, amount int64) base.Operation {
+	b.t.Helper()
+
+	fact := currency.NewTransferFact(
+		mitumutil.UUID().Bytes(),
+		b.sender,
+		[]currency.TransferItem{currency.NewTransferItemMultiAmounts(
+			receiver, []types.Amount{types.NewAmount(common.NewBig(amount), b.cid)})},
+	)
+
+	op, err := currency.NewTransfer(fact)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	if err := op.Sign(b.priv, testNetworkID); err != nil {
+		b.t.Fatal(err)
+	}
+
+	return op
+}
+
+func (b *testLeveldbBlocks) commit(height base.Height, ops []base.Operation, sts []base.State) {
+	b.t.Helper()
+
+	nodes := make([]fixedtree.Node, len(ops))
+	for i := range ops {
+		nodes[i] = base.NewInStateOperationFixedtreeNode(ops[i].Fact().Hash(), "")
+	}
+
+	opsTree := fixedtree.EmptyTree()
+	if len(nodes) > 0 {
+		i, err := fixedtree.NewTree(base.OperationFixedtreeHint, nodes)
+		if err != nil {
+			b.t.Fatal(err)
+		}
+
+		opsTree = i
+	}
+
+	proposal := isaac.NewProposalSignFact(isaac.NewProposalFact(
+		base.NewPoint(height, 0), base.NewStringAddress("node0"), valuehash.RandomSHA256(), nil))
+
+	blockmap := isaacblock.NewBlockMap()
+	blockmap.SetManifest(isaac.NewManifest(
+		height,
+		valuehash.RandomSHA256(),
+		proposal.Fact().Hash(),
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		valuehash.RandomSHA256(),
+		time.Now().UTC(),
+	))
+
+	bs, err := b.db.NewBlockSession(blockmap, ops, opsTree, sts, proposal, "test")
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	if err := bs.Prepare(); err != nil {
+		b.t.Fatal(err)
+	}
+
+	if err := bs.Commit(context.Background()); err != nil {
+		b.t.Fatal(err)
+	}
+
+	if err := b.db.SetLastBlock(height); err != nil {
+		b.t.Fatal(err)
+	}
+}
+
+func (b *testLeveldbBlocks) accountState(height base.Height) base.State {
+	b.t.Helper()
+
+	ac, err := types.NewAccount(b.sender, b.keys())
+	if err != nil {
+		b.t.Fatal(err)
+	}
+
+	return common.NewBaseState(height, statecurrency.AccountStateKey(b.sender),
+		statecurrency.NewAccountStateValue(ac), nil, nil)
+}
+
+func (b *testLeveldbBlocks) balanceState(height base.Height, amount int64) base.State {
+	return common.NewBaseState(height, statecurrency.BalanceStateKey(b.sender, b.cid),
+		statecurrency.NewBalanceStateValue(types.NewAmount(common.NewBig(amount), b.cid)), nil, nil)
+}
+
+func (b *testLeveldbBlocks) balanceHistory() []int64 {
+	b.t.Helper()
+
+	var amounts []int64
+
+	if err := b.db.BalanceHistory(b.sender, b.cid.String(), base.NilHeight, base.NilHeight, base.NilHeight, false, 0,
+		func(st base.State) (bool, error) {
+			am, err := statecurrency.StateBalanceValue(st)
+			if err != nil {
+				return false, err
+			}
+
+			amounts = append(amounts, am.Big().Int64())
+
+			return true, nil
+		},
+	); err != nil {
+		b.t.Fatal(err)
+	}
+
+	return amounts
+}
+
+func TestLeveldbDatabaseBlockSession(t *testing.T) {
+	b := newTestLeveldbBlocks(t)
+
+	receiverKey, err := types.NewBaseAccountKey(types.NewMEPrivatekey().Publickey(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiverKeys, err := types.NewBaseAccountKeys([]types.AccountKey{receiverKey}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiver, err := types.NewAddressFromKeys(receiverKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op0 := b.transfer(receiver, 10)
+
+	b.commit(base.GenesisHeight, []base.Operation{op0}, []base.State{
+		b.accountState(base.GenesisHeight),
+		b.balanceState(base.GenesisHeight, 100),
+	})
+
+	op1 := b.transfer(receiver, 20)
+
+	node := base.NewStringAddress("node0")
+
+	b.commit(base.GenesisHeight+1, []base.Operation{op1}, []base.State{
+		b.balanceState(base.GenesisHeight+1, 80),
+		common.NewBaseState(base.GenesisHeight+1, statecurrency.SuffrageBondStateKey(node),
+			statecurrency.NewSuffrageBondStateValue(b.sender, types.NewAmount(common.NewBig(20), b.cid)), nil, nil),
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		for _, height := range []base.Height{base.GenesisHeight, base.GenesisHeight + 1} {
+			m, count, _, _, _, err := b.db.ManifestByHeight(height)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.Height() != height || count != 1 {
+				t.Fatalf("unexpected manifest, height=%v count=%d", m.Height(), count)
+			}
+		}
+	})
+
+	t.Run("operation", func(t *testing.T) {
+		va, found, err := b.db.Operation(op1.Fact().Hash(), true)
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case !found:
+			t.Fatal("operation not found")
+		case !va.Operation().Fact().Hash().Equal(op1.Fact().Hash()):
+			t.Fatalf("unexpected operation, %v", va.Operation().Fact().Hash())
+		case va.Height() != base.GenesisHeight+1:
+			t.Fatalf("unexpected height, %v", va.Height())
+		}
+
+		of, err := digest.ParseOperationFilter(url.Values{}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var hashes []string
+
+		if err := b.db.OperationsByAddress(b.sender, false, false, "", 0, of,
+			func(h mitumutil.Hash, _ digest.OperationValue) (bool, error) {
+				hashes = append(hashes, h.String())
+
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(hashes) != 2 || hashes[0] != op0.Fact().Hash().String() || hashes[1] != op1.Fact().Hash().String() {
+			t.Fatalf("unexpected operations, %v", hashes)
+		}
+	})
+
+	t.Run("operations by height", func(t *testing.T) {
+		for _, i := range []struct {
+			height base.Height
+			op     base.Operation
+		}{
+			{height: base.GenesisHeight, op: op0},
+			{height: base.GenesisHeight + 1, op: op1},
+		} {
+			var hashes []string
+
+			if err := b.db.OperationsByHeight(i.height, "", false, false, 0,
+				func(h mitumutil.Hash, _ digest.OperationValue, _ int64) (bool, error) {
+					hashes = append(hashes, h.String())
+
+					return true, nil
+				},
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(hashes) != 1 || hashes[0] != i.op.Fact().Hash().String() {
+				t.Fatalf("unexpected operations of height %v, %v", i.height, hashes)
+			}
+		}
+
+		var n int
+
+		if err := b.db.Operations(digest.OperationFilter{}, "", false, false, 0,
+			func(mitumutil.Hash, digest.OperationValue, int64) (bool, error) {
+				n++
+
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if n != 2 {
+			t.Fatalf("expected 2 operations, but %d", n)
+		}
+	})
+
+	t.Run("operation filter", func(t *testing.T) {
+		of, err := digest.ParseOperationFilter(url.Values{
+			"counterparty": []string{receiver.String() + "," + b.sender.String()},
+			"from_height":  []string{(base.GenesisHeight + 1).String()},
+		}, b.enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var hashes []string
+
+		if err := b.db.OperationsByAddress(b.sender, false, false, "", 0, of,
+			func(h mitumutil.Hash, _ digest.OperationValue) (bool, error) {
+				hashes = append(hashes, h.String())
+
+				return true, nil
+			},
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(hashes) != 1 || hashes[0] != op1.Fact().Hash().String() {
+			t.Fatalf("unexpected operations, %v", hashes)
+		}
+
+		for _, s := range []string{"unknown", receiver.String() + ",0x00fca"} {
+			if _, err := digest.ParseOperationFilter(url.Values{"counterparty": []string{s}}, b.enc); err == nil {
+				t.Fatalf("expected error for counterparty, %q", s)
+			}
+		}
+	})
+
+	t.Run("account", func(t *testing.T) {
+		va, found, err := b.db.Account(b.sender)
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case !found:
+			t.Fatal("account not found")
+		case !va.Account().Address().Equal(b.sender):
+			t.Fatalf("unexpected account, %v", va.Account().Address())
+		case len(va.Balance()) != 1 || va.Balance()[0].Big().Int64() != 80:
+			t.Fatalf("unexpected balance, %v", va.Balance())
+		}
+
+		if amounts := b.balanceHistory(); len(amounts) != 2 || amounts[0] != 100 || amounts[1] != 80 {
+			t.Fatalf("unexpected balance history, %v", amounts)
+		}
+	})
+
+	t.Run("supply balances", func(t *testing.T) {
+		balances := map[string]int64{}
+
+		if err := b.db.CurrencySupplyBalances(b.cid.String(), func(key string, am common.Big) (bool, error) {
+			balances[key] = am.Int64()
+
+			return true, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		bondKey := statecurrency.SuffrageBondStateKey(node)
+
+		if len(balances) != 2 || balances[b.sender.String()] != 80 || balances[bondKey] != 20 {
+			t.Fatalf("unexpected supply balances, %v", balances)
+		}
+	})
+
+	t.Run("clean by height", func(t *testing.T) {
+		if err := b.db.CleanByHeight(context.Background(), base.GenesisHeight+1); err != nil {
+			t.Fatal(err)
+		}
+
+		if h := b.db.LastBlock(); h != base.GenesisHeight {
+			t.Fatalf("unexpected last block, %v", h)
+		}
+
+		if _, _, _, _, _, err := b.db.ManifestByHeight(base.GenesisHeight + 1); err == nil {
+			t.Fatal("expected error for cleaned manifest")
+		}
+
+		switch _, found, err := b.db.Operation(op1.Fact().Hash(), false); {
+		case err != nil:
+			t.Fatal(err)
+		case found:
+			t.Fatal("cleaned operation found")
+		}
+
+		if amounts := b.balanceHistory(); len(amounts) != 1 || amounts[0] != 100 {
+			t.Fatalf("unexpected balance history, %v", amounts)
+		}
+	})
+}
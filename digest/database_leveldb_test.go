@@ -0,0 +1,269 @@
+package digest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/syndtr/goleveldb/leveldb"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const testStateDigestCollection = "test_state_digest"
+
+const testStateDigestKeyPrefix = "test-state-digest:"
+
+func init() {
+	// NOTE the balance of state is kept by the state key; the document is
+	// replaced by the latest state.
+	if err := RegisterStateDigest(StateDigest{
+		Collection: testStateDigestCollection,
+		IsStateKey: func(key string) bool {
+			return strings.HasPrefix(key, testStateDigestKeyPrefix)
+		},
+		Writes: func(bs *BlockSession, st base.State) ([]DigestWrite, error) {
+			am, err := statecurrency.StateBalanceValue(st)
+			if err != nil {
+				return nil, err
+			}
+
+			return []DigestWrite{
+				NewDigestReplace(
+					bson.D{{Key: "key", Value: st.Key()}},
+					testStateDigestDoc{Key: st.Key(), Value: am.Big().Int64(), Height: st.Height()},
+					true,
+				),
+			}, nil
+		},
+	}); err != nil {
+		panic(err)
+	}
+}
+
+type testStateDigestDoc struct {
+	Key    string      `bson:"key"`
+	Value  int64       `bson:"value"`
+	Height base.Height `bson:"height"`
+}
+
+func newTestLeveldbDatabase(t *testing.T) *LeveldbDatabase {
+	t.Helper()
+
+	enc := jsonenc.NewEncoder()
+	encs := encoder.NewEncoders(enc, enc)
+
+	db, err := NewLeveldbDatabase(t.TempDir(), encs, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return db
+}
+
+func writeTestStateDigest(t *testing.T, db *LeveldbDatabase, height base.Height, writes ...DigestWrite) {
+	t.Helper()
+
+	batch := &leveldb.Batch{}
+	w := newLeveldbStateDigestWriter(db, batch, testStateDigestCollection, height)
+
+	for i := range writes {
+		if err := w.write(writes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.st.Batch(batch, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testStateDigestDocs(t *testing.T, db *LeveldbDatabase) []testStateDigestDoc {
+	t.Helper()
+
+	var docs []testStateDigestDoc
+
+	if err := db.stateDigestDocs(testStateDigestCollection, func(_ []byte, raw bson.Raw) (bool, error) {
+		var doc testStateDigestDoc
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return false, err
+		}
+
+		docs = append(docs, doc)
+
+		return true, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	return docs
+}
+
+func checkTestStateDigestDocs(t *testing.T, docs []testStateDigestDoc, expected ...testStateDigestDoc) {
+	t.Helper()
+
+	if len(docs) != len(expected) {
+		t.Fatalf("expected %d documents, but %d: %v", len(expected), len(docs), docs)
+	}
+
+	for i := range expected {
+		if docs[i] != expected[i] {
+			t.Fatalf("expected %v, but %v", expected[i], docs[i])
+		}
+	}
+}
+
+func TestLeveldbStateDigestReplace(t *testing.T) {
+	db := newTestLeveldbDatabase(t)
+
+	writeTestStateDigest(t, db, 0,
+		NewDigestInsert(testStateDigestDoc{Key: "a", Value: 1, Height: 0}),
+		NewDigestInsert(testStateDigestDoc{Key: "b", Value: 2, Height: 0}),
+	)
+
+	writeTestStateDigest(t, db, 1,
+		NewDigestReplace(bson.D{{Key: "key", Value: "a"}}, testStateDigestDoc{Key: "a", Value: 3, Height: 1}, false),
+		// NOTE not upserted
+		NewDigestReplace(bson.D{{Key: "key", Value: "c"}}, testStateDigestDoc{Key: "c", Value: 4, Height: 1}, false),
+		NewDigestReplace(bson.D{{Key: "key", Value: "d"}}, testStateDigestDoc{Key: "d", Value: 5, Height: 1}, true),
+	)
+
+	checkTestStateDigestDocs(t, testStateDigestDocs(t, db),
+		testStateDigestDoc{Key: "b", Value: 2, Height: 0},
+		testStateDigestDoc{Key: "a", Value: 3, Height: 1},
+		testStateDigestDoc{Key: "d", Value: 5, Height: 1},
+	)
+
+	if err := db.CleanByHeight(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	checkTestStateDigestDocs(t, testStateDigestDocs(t, db),
+		testStateDigestDoc{Key: "b", Value: 2, Height: 0},
+	)
+}
+
+func TestLeveldbStateDigestUpdate(t *testing.T) {
+	db := newTestLeveldbDatabase(t)
+
+	writeTestStateDigest(t, db, 0,
+		NewDigestInsert(testStateDigestDoc{Key: "a", Value: 1, Height: 0}),
+		NewDigestInsert(testStateDigestDoc{Key: "b", Value: 1, Height: 0}),
+		NewDigestInsert(testStateDigestDoc{Key: "c", Value: 2, Height: 0}),
+	)
+
+	writeTestStateDigest(t, db, 1,
+		// NOTE int32 filter matches int64 field
+		NewDigestUpdate(
+			bson.D{{Key: "value", Value: int32(1)}},
+			bson.D{{Key: "value", Value: int64(9)}, {Key: "height", Value: base.Height(1)}},
+			nil, false, true,
+		),
+		// NOTE the document written in same session is also updated
+		NewDigestUpdate(
+			bson.D{{Key: "key", Value: "a"}},
+			bson.D{{Key: "value", Value: int64(10)}},
+			nil, false, false,
+		),
+		NewDigestUpdate(
+			bson.D{{Key: "key", Value: "d"}},
+			bson.D{{Key: "value", Value: int64(11)}, {Key: "height", Value: base.Height(1)}},
+			nil, true, false,
+		),
+	)
+
+	checkTestStateDigestDocs(t, testStateDigestDocs(t, db),
+		testStateDigestDoc{Key: "c", Value: 2, Height: 0},
+		testStateDigestDoc{Key: "b", Value: 9, Height: 1},
+		testStateDigestDoc{Key: "a", Value: 10, Height: 1},
+		testStateDigestDoc{Key: "d", Value: 11, Height: 1},
+	)
+
+	t.Run("unsupported operator", func(t *testing.T) {
+		w := newLeveldbStateDigestWriter(db, &leveldb.Batch{}, testStateDigestCollection, 2)
+
+		for _, dw := range []DigestWrite{
+			NewDigestUpdate(bson.D{{Key: "key", Value: "a"}}, bson.D{{Key: "$inc", Value: 1}}, nil, false, false),
+			NewDigestUpdate(bson.D{{Key: "key", Value: "a"}}, bson.D{{Key: "a.b", Value: 1}}, nil, false, false),
+			NewDigestUpdate(bson.D{{Key: "key", Value: "a"}}, nil, nil, false, false),
+			NewDigestDelete(bson.D{{Key: "value", Value: bson.M{"$gt": 1}}}, false),
+			NewDigestDelete(bson.D{{Key: "$or", Value: bson.A{}}}, false),
+			NewDigestInsert(nil),
+			{},
+		} {
+			if err := w.write(dw); err == nil {
+				t.Fatalf("expected error, %v", dw)
+			}
+		}
+	})
+}
+
+func TestLeveldbStateDigestDelete(t *testing.T) {
+	db := newTestLeveldbDatabase(t)
+
+	writeTestStateDigest(t, db, 0,
+		NewDigestInsert(testStateDigestDoc{Key: "a", Value: 1, Height: 0}),
+		NewDigestInsert(testStateDigestDoc{Key: "b", Value: 1, Height: 0}),
+		NewDigestInsert(testStateDigestDoc{Key: "c", Value: 1, Height: 0}),
+	)
+
+	writeTestStateDigest(t, db, 1,
+		NewDigestDelete(bson.D{{Key: "value", Value: 1}}, false),
+		NewDigestInsert(testStateDigestDoc{Key: "d", Value: 2, Height: 1}),
+	)
+
+	checkTestStateDigestDocs(t, testStateDigestDocs(t, db),
+		testStateDigestDoc{Key: "b", Value: 1, Height: 0},
+		testStateDigestDoc{Key: "c", Value: 1, Height: 0},
+		testStateDigestDoc{Key: "d", Value: 2, Height: 1},
+	)
+
+	writeTestStateDigest(t, db, 2,
+		NewDigestDelete(bson.D{{Key: "value", Value: 1}}, true),
+	)
+
+	checkTestStateDigestDocs(t, testStateDigestDocs(t, db),
+		testStateDigestDoc{Key: "d", Value: 2, Height: 1},
+	)
+}
+
+func TestLeveldbBlockSessionStateDigest(t *testing.T) {
+	db := newTestLeveldbDatabase(t)
+
+	newState := func(height base.Height, key string, amount int64) base.State {
+		return common.NewBaseState(height, testStateDigestKeyPrefix+key,
+			statecurrency.NewBalanceStateValue(types.NewAmount(common.NewBig(amount), types.CurrencyID("MCC"))), nil, nil)
+	}
+
+	commit := func(sts ...base.State) {
+		bs := &leveldbBlockSession{
+			BlockSession: newBlockSession(db.enc, nil, nil, fixedtree.EmptyTree(), sts, nil, ""),
+			db:           db,
+		}
+
+		if err := bs.Prepare(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := bs.Commit(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit(newState(0, "a", 1), newState(0, "b", 2), newState(0, "a", 3))
+
+	checkTestStateDigestDocs(t, testStateDigestDocs(t, db),
+		testStateDigestDoc{Key: testStateDigestKeyPrefix + "b", Value: 2, Height: 0},
+		testStateDigestDoc{Key: testStateDigestKeyPrefix + "a", Value: 3, Height: 0},
+	)
+}
@@ -17,6 +17,7 @@ import (
 	"math/big"
 	"net"
 	"net/url"
+	"reflect"
 	"sort"
 	"time"
 )
@@ -39,13 +40,20 @@ var (
 )
 
 type YamlDigestDesign struct {
-	NetworkYAML  *LocalNetwork         `yaml:"network,omitempty"`
-	CacheYAML    *string               `yaml:"cache,omitempty"`
-	DatabaseYAML *config.DatabaseYAML  `yaml:"database"`
-	ConnInfo     []quicstream.ConnInfo `yaml:"conn_info,omitempty"`
-	network      config.LocalNetwork
-	database     config.BaseDatabase
-	cache        *url.URL
+	NetworkYAML   *LocalNetwork         `yaml:"network,omitempty"`
+	CacheYAML     *string               `yaml:"cache,omitempty"`
+	DatabaseYAML  *config.DatabaseYAML  `yaml:"database"`
+	ConnInfo      []quicstream.ConnInfo `yaml:"conn_info,omitempty"`
+	RateLimitYAML *RateLimitYAML        `yaml:"rate_limit,omitempty"`
+	AuthYAML      *APIKeyAuthYAML       `yaml:"auth,omitempty"`
+	SupplyYAML    *SupplyYAML           `yaml:"supply,omitempty"`
+	JSONRPCYAML   *JSONRPCYAML          `yaml:"json_rpc,omitempty"`
+	network       config.LocalNetwork
+	database      config.BaseDatabase
+	cache         *url.URL
+	rateLimit     RateLimitConfig
+	supply        SupplyConfig
+	jsonRPC       JSONRPCConfig
 }
 
 func (d *YamlDigestDesign) Set(ctx context.Context) (context.Context, error) {
@@ -135,6 +143,24 @@ func (d *YamlDigestDesign) Set(ctx context.Context) (context.Context, error) {
 		d.database = st
 	}
 
+	rl, err := NewRateLimitConfig(d.RateLimitYAML, d.AuthYAML)
+	if err != nil {
+		return ctx, e.Wrap(err)
+	}
+	d.rateLimit = rl
+
+	sc, err := NewSupplyConfig(d.SupplyYAML)
+	if err != nil {
+		return ctx, e.Wrap(err)
+	}
+	d.supply = sc
+
+	jc, err := NewJSONRPCConfig(d.JSONRPCYAML)
+	if err != nil {
+		return ctx, e.Wrap(err)
+	}
+	d.jsonRPC = jc
+
 	return ctx, nil
 }
 
@@ -150,6 +176,18 @@ func (d *YamlDigestDesign) Database() config.BaseDatabase {
 	return d.database
 }
 
+func (d *YamlDigestDesign) RateLimit() RateLimitConfig {
+	return d.rateLimit
+}
+
+func (d *YamlDigestDesign) Supply() SupplyConfig {
+	return d.supply
+}
+
+func (d *YamlDigestDesign) JSONRPC() JSONRPCConfig {
+	return d.jsonRPC
+}
+
 func (d YamlDigestDesign) MarshalZerologObject(e *zerolog.Event) {
 	e.
 		Interface("network", d.network).
@@ -170,6 +208,22 @@ func (d YamlDigestDesign) Equal(b YamlDigestDesign) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual(d.RateLimitYAML, b.RateLimitYAML) {
+		return false
+	}
+
+	if !reflect.DeepEqual(d.AuthYAML, b.AuthYAML) {
+		return false
+	}
+
+	if !reflect.DeepEqual(d.SupplyYAML, b.SupplyYAML) {
+		return false
+	}
+
+	if !reflect.DeepEqual(d.JSONRPCYAML, b.JSONRPCYAML) {
+		return false
+	}
+
 	if len(d.ConnInfo) != len(b.ConnInfo) {
 		return false
 	}
@@ -49,7 +49,7 @@ type Digester struct {
 	sync.RWMutex
 	*util.ContextDaemon
 	*logging.Logging
-	database      *Database
+	database      Store
 	localfsRoot   string
 	blockChan     chan base.BlockMap
 	errChan       chan error
@@ -57,10 +57,13 @@ type Digester struct {
 	fromRemotes   isaac.RemotesBlockItemReadFunc
 	networkID     base.NetworkID
 	buildInfo     string
+	gapsl         sync.RWMutex
+	digested      map[base.Height]struct{}
+	top           base.Height
 }
 
 func NewDigester(
-	st *Database,
+	st Store,
 	root string,
 	sourceReaders *isaac.BlockItemReaders,
 	fromRemotes isaac.RemotesBlockItemReadFunc,
@@ -80,6 +83,8 @@ func NewDigester(
 		fromRemotes:   fromRemotes,
 		networkID:     networkID,
 		buildInfo:     vs,
+		digested:      map[base.Height]struct{}{},
+		top:           base.NilHeight,
 	}
 
 	di.ContextDaemon = util.NewContextDaemon(di.start)
@@ -98,6 +103,9 @@ func (di *Digester) start(ctx context.Context) error {
 		di.errChan <- err
 	}
 
+	ticker := time.NewTicker(DefaultDigestGapsInterval)
+	defer ticker.Stop()
+
 end:
 	for {
 		select {
@@ -105,9 +113,11 @@ end:
 			di.Log().Debug().Msg("stopped")
 
 			break end
+		case <-ticker.C:
+			di.fillGaps(ctx)
 		case blk := <-di.blockChan:
 			err := util.Retry(ctx, func() (bool, error) {
-				if err := di.digest(ctx, blk); err != nil {
+				if err := di.digest(ctx, blk.Manifest().Height()); err != nil {
 					go errch(NewDigestError(err, blk.Manifest().Height()))
 					if errors.Is(err, context.Canceled) {
 						return false, e.Wrap(err)
@@ -144,42 +154,79 @@ func (di *Digester) Digest(blocks []base.BlockMap) {
 	}
 }
 
-func (di *Digester) digest(ctx context.Context, blk base.BlockMap) error {
+func (di *Digester) digest(ctx context.Context, height base.Height) error {
 	e := util.StringError("digest block")
 
 	di.Lock()
 	defer di.Unlock()
 
+	switch err := di.digestHeight(ctx, height); {
+	case err == nil:
+		return nil
+	case !errors.Is(err, ErrDigestDiverged):
+		return e.Wrap(err)
+	}
+
+	// NOTE the digested blocks diverged from the block maps; rollback and
+	// digest again.
+	last, _, err := RollbackDiverged(ctx, di.database, height, di.blockMap)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	di.resetDigested(last)
+
+	di.Log().Info().
+		Int64("height", height.Int64()).
+		Int64("last_consistent", last.Int64()).
+		Msg("digest rolled back by diverged block")
+
+	for h := last + 1; h <= height; h++ {
+		if err := di.digestHeight(ctx, h); err != nil {
+			return e.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+func (di *Digester) digestHeight(ctx context.Context, height base.Height) error {
+	itemf := di.itemFunc(ctx)
+
 	var bm base.BlockMap
 
-	switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](di.sourceReaders.Item, blk.Manifest().Height(), base.BlockItemMap, nil); {
+	switch i, found, err := isaac.BlockItemReadersDecode[base.BlockMap](itemf, height, base.BlockItemMap, nil); {
 	case err != nil:
-		return e.Wrap(err)
+		return err
 	case !found:
-		return e.Wrap(util.ErrNotFound.Errorf("Blockmap"))
+		return util.ErrNotFound.Errorf("Blockmap")
 	default:
 		if err := i.IsValid(di.networkID); err != nil {
-			return e.Wrap(err)
+			return err
 		}
 
 		bm = i
 	}
 
-	pr, ops, sts, opsTree, _, _, err := isaacblock.LoadBlockItemsFromReader(bm, di.sourceReaders.Item, blk.Manifest().Height())
+	pr, ops, sts, opsTree, _, _, err := isaacblock.LoadBlockItemsFromReader(bm, itemf, height)
 	if err != nil {
-		return e.Wrap(err)
+		return err
 	}
 
-	if err := DigestBlock(ctx, di.database, blk, ops, opsTree, sts, pr, di.buildInfo); err != nil {
-		return e.Wrap(err)
+	if err := DigestBlock(ctx, di.database, bm, ops, opsTree, sts, pr, di.buildInfo); err != nil {
+		return err
 	}
 
-	return di.database.SetLastBlock(blk.Manifest().Height())
+	return di.setDigested(height)
+}
+
+func (di *Digester) blockMap(height base.Height) (base.BlockMap, bool, error) {
+	return isaac.BlockItemReadersDecode[base.BlockMap](di.sourceReaders.Item, height, base.BlockItemMap, nil)
 }
 
 func DigestBlock(
 	ctx context.Context,
-	st *Database,
+	st Store,
 	blk base.BlockMap,
 	ops []base.Operation,
 	opsTree fixedtree.Tree,
@@ -188,10 +235,14 @@ func DigestBlock(
 	vs string,
 ) error {
 	if m, _, _, _, _, _ := st.ManifestByHeight(blk.Manifest().Height()); m != nil {
+		if !m.Hash().Equal(blk.Manifest().Hash()) {
+			return ErrDigestDiverged.Errorf("height=%d", blk.Manifest().Height())
+		}
+
 		return nil
 	}
 
-	bs, err := NewBlockSession(st, blk, ops, opsTree, sts, proposal, vs)
+	bs, err := st.NewBlockSession(blk, ops, opsTree, sts, proposal, vs)
 	if err != nil {
 		return err
 	}
@@ -134,3 +134,44 @@ func (doc ContractAccountStatusDoc) MarshalBSON() ([]byte, error) {
 
 	return bsonenc.Marshal(m)
 }
+
+// SuffrageBondDoc is the deposit of suffrage candidate node; it is kept for
+// the supply audit, because the bonded deposit is deducted from the balance.
+type SuffrageBondDoc struct {
+	mongodbstorage.BaseDoc
+	st   base.State
+	bond currency.SuffrageBondStateValue
+}
+
+func NewSuffrageBondDoc(st base.State, enc encoder.Encoder) (SuffrageBondDoc, error) {
+	bond, err := currency.StateSuffrageBondValue(st)
+	if err != nil {
+		return SuffrageBondDoc{}, errors.Wrap(err, "SuffrageBondDoc needs SuffrageBond state")
+	}
+
+	b, err := mongodbstorage.NewBaseDoc(nil, st, enc)
+	if err != nil {
+		return SuffrageBondDoc{}, err
+	}
+
+	return SuffrageBondDoc{
+		BaseDoc: b,
+		st:      st,
+		bond:    bond,
+	}, nil
+}
+
+func (doc SuffrageBondDoc) MarshalBSON() ([]byte, error) {
+	m, err := doc.BaseDoc.M()
+	if err != nil {
+		return nil, err
+	}
+
+	m["node"] = doc.st.Key()[:len(doc.st.Key())-len(currency.SuffrageBondStateKeySuffix)]
+	m["address"] = doc.bond.Account.String()
+	m["currency"] = doc.bond.Amount.Currency().String()
+	m["height"] = doc.st.Height()
+	m["amount"] = doc.bond.Amount.Big().String()
+
+	return bsonenc.Marshal(m)
+}
@@ -12,10 +12,11 @@ import (
 
 type OperationDoc struct {
 	mongodbstorage.BaseDoc
-	va        OperationValue
-	op        base.Operation
-	addresses []string
-	height    base.Height
+	va         OperationValue
+	op         base.Operation
+	addresses  []string
+	currencies []string
+	height     base.Height
 }
 
 func NewOperationDoc(
@@ -46,11 +47,12 @@ func NewOperationDoc(
 	}
 
 	return OperationDoc{
-		BaseDoc:   b,
-		va:        va,
-		op:        op,
-		addresses: addresses,
-		height:    height,
+		BaseDoc:    b,
+		va:         va,
+		op:         op,
+		addresses:  addresses,
+		currencies: operationCurrencies(op),
+		height:     height,
 	}, nil
 }
 
@@ -64,6 +66,10 @@ func (doc OperationDoc) MarshalBSON() ([]byte, error) {
 	m["fact"] = doc.op.Fact().Hash()
 	m["height"] = doc.height
 	m["index"] = doc.va.index
+	m["hint"] = doc.op.Hint().Type().String()
+	m["in_state"] = doc.va.inState
+	m["currencies"] = doc.currencies
+	m["confirmed_at"] = doc.va.confirmedAt
 
 	return bsonenc.Marshal(m)
 }
@@ -0,0 +1,142 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+)
+
+var (
+	DefaultDigestGapsInterval = time.Second * 10
+	maxDigestGapsHeights      = 1000
+)
+
+// DigestGaps is the heights, which are missed between the last digested block
+// and the highest digested block. Heights has up to 1000 heights; Count is the
+// number of all the missed heights.
+type DigestGaps struct {
+	LastBlock base.Height   `json:"last_block"`
+	Top       base.Height   `json:"top"`
+	Count     int64         `json:"count"`
+	Heights   []base.Height `json:"heights"`
+}
+
+// Gaps returns the missed heights of digester.
+func (di *Digester) Gaps() DigestGaps {
+	di.gapsl.RLock()
+	defer di.gapsl.RUnlock()
+
+	last := di.database.LastBlock()
+
+	gaps := DigestGaps{LastBlock: last, Top: last, Heights: []base.Height{}}
+	if di.top <= last {
+		return gaps
+	}
+
+	gaps.Top = di.top
+
+	for h := last + 1; h < di.top; h++ {
+		if _, found := di.digested[h]; found {
+			continue
+		}
+
+		gaps.Count++
+
+		if len(gaps.Heights) < maxDigestGapsHeights {
+			gaps.Heights = append(gaps.Heights, h)
+		}
+	}
+
+	return gaps
+}
+
+// setDigested marks the height as digested. LastBlock is not advanced across
+// the missed heights; the heights over the gap are kept until the gap is
+// filled.
+func (di *Digester) setDigested(height base.Height) error {
+	di.gapsl.Lock()
+	defer di.gapsl.Unlock()
+
+	if height > di.top {
+		di.top = height
+	}
+
+	last := di.database.LastBlock()
+
+	switch {
+	case height <= last:
+		return nil
+	case height > last+1:
+		di.digested[height] = struct{}{}
+
+		return nil
+	}
+
+	top := height
+
+	for {
+		if _, found := di.digested[top+1]; !found {
+			break
+		}
+
+		delete(di.digested, top+1)
+		top++
+	}
+
+	return di.database.SetLastBlock(top)
+}
+
+// resetDigested forgets the digested heights over the height, like after
+// rollback.
+func (di *Digester) resetDigested(height base.Height) {
+	di.gapsl.Lock()
+	defer di.gapsl.Unlock()
+
+	for h := range di.digested {
+		if h > height {
+			delete(di.digested, h)
+		}
+	}
+
+	if di.top > height {
+		di.top = height
+	}
+}
+
+// fillGaps digests the missed heights again from the local block items or
+// from the remote nodes.
+func (di *Digester) fillGaps(ctx context.Context) {
+	gaps := di.Gaps()
+	if gaps.Count < 1 {
+		return
+	}
+
+	di.Log().Debug().Interface("gaps", gaps).Msg("found digest gaps; trying to fill")
+
+	heights := gaps.Heights
+
+	for i := range heights {
+		if err := di.digest(ctx, heights[i]); err != nil {
+			di.Log().Error().Err(err).Int64("height", heights[i].Int64()).Msg("failed to fill digest gap")
+
+			return
+		}
+
+		metrics.DigestGapsFilled.Inc()
+
+		di.Log().Info().Int64("height", heights[i].Int64()).Msg("digest gap filled")
+	}
+}
+
+// itemFunc reads the block items from sourceReaders; if the item file is not
+// in local, it is read from the remote nodes.
+func (di *Digester) itemFunc(ctx context.Context) isaac.BlockItemReadersItemFunc {
+	if di.fromRemotes == nil {
+		return di.sourceReaders.Item
+	}
+
+	return isaac.BlockItemReadersItemFuncWithRemote(di.sourceReaders, di.fromRemotes, nil)(ctx)
+}
@@ -3,6 +3,7 @@ package digest
 import (
 	"context"
 	"fmt"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	"github.com/ProtoconNet/mitum-currency/v3/types"
 	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
 	"github.com/ProtoconNet/mitum2/network/quicstream"
@@ -31,8 +32,11 @@ var (
 
 var (
 	HandlerPathNodeInfo                   = `/`
+	HandlerPathMetrics                    = `/metrics`
 	HandlerPathCurrencies                 = `/currency`
 	HandlerPathCurrency                   = `/currency/{currency_id:` + types.ReCurrencyID + `}`
+	HandlerPathCurrencyHolders            = `/currency/{currency_id:` + types.ReCurrencyID + `}/holders`
+	HandlerPathCurrencySupplyAudit        = `/currency/{currency_id:` + types.ReCurrencyID + `}/supply/audit`
 	HandlerPathManifests                  = `/block/manifests`
 	HandlerPathOperations                 = `/block/operations`
 	HandlerPathOperationsByHash           = `/block/operations/facts`
@@ -42,13 +46,18 @@ var (
 	HandlerPathOperationsByHeight         = `/block/{height:[0-9]+}/operations`
 	HandlerPathManifestByHeight           = `/block/{height:[0-9]+}/manifest`
 	HandlerPathManifestByHash             = `/block/{hash:(?i)[0-9a-z][0-9a-z]+}/manifest`
-	HandlerPathAccount                    = `/account/{address:(?i)` + types.REStringAddressString + `}`            // revive:disable-line:line-length-limit
-	HandlerPathAccountOperations          = `/account/{address:(?i)` + types.REStringAddressString + `}/operations` // revive:disable-line:line-length-limit
+	HandlerPathAccount                    = `/account/{address:(?i)` + types.REStringAddressString + `}`                                                          // revive:disable-line:line-length-limit
+	HandlerPathAccountOperations          = `/account/{address:(?i)` + types.REStringAddressString + `}/operations`                                               // revive:disable-line:line-length-limit
+	HandlerPathAccountBalanceHistory      = `/account/{address:(?i)` + types.REStringAddressString + `}/balance/{currency_id:` + types.ReCurrencyID + `}/history` // revive:disable-line:line-length-limit
 	HandlerPathAccounts                   = `/accounts`
 	HandlerPathOperationBuildFactTemplate = `/builder/operation/fact/template/{fact:[\w][\w\-]*}`
 	HandlerPathOperationBuildFact         = `/builder/operation/fact`
 	HandlerPathOperationBuildSign         = `/builder/operation/sign`
 	HandlerPathOperationBuild             = `/builder/operation`
+	HandlerPathDigestGaps                 = `/digest/gaps`
+	HandlerPathJSONRPC                    = `/jsonrpc`
+	HandlerPathMultisig                   = `/multisig`
+	HandlerPathMultisigByFact             = `/multisig/{hash:(?i)[0-9a-z][0-9a-z]+}`
 	HandlerPathSend                       = `/builder/send`
 	HandlerPathQueueSend                  = `/builder/send/queue`
 	HandelrPathEventOperation             = `/event/operation/{hash:(?i)[0-9a-z][0-9a-z]+}`
@@ -76,7 +85,7 @@ type Handlers struct {
 	networkID       base.NetworkID
 	encs            *encoder.Encoders
 	enc             encoder.Encoder
-	database        *Database
+	database        Store
 	cache           Cache
 	queue           chan RequestWrapper
 	nodeInfoHandler NodeInfoHandler
@@ -87,6 +96,11 @@ type Handlers struct {
 	itemsLimiter    func(string /* request type */) int64
 	rg              *singleflight.Group
 	expireNotFilled time.Duration
+	rateLimiter     *APIRateLimiter
+	supply          SupplyConfig
+	jsonRPC         JSONRPCConfig
+	multisig        *MultisigPool
+	digester        *util.Locked[*Digester]
 }
 
 func NewHandlers(
@@ -94,7 +108,7 @@ func NewHandlers(
 	networkID base.NetworkID,
 	encs *encoder.Encoders,
 	enc encoder.Encoder,
-	st *Database,
+	st Store,
 	cache Cache,
 	router *mux.Router,
 	queue chan RequestWrapper,
@@ -117,13 +131,21 @@ func NewHandlers(
 		itemsLimiter:    DefaultItemsLimiter,
 		rg:              &singleflight.Group{},
 		expireNotFilled: time.Second * 3,
+		multisig:        NewMultisigPool(DefaultMultisigLifespan, DefaultMultisigLimit),
+		digester:        util.EmptyLocked[*Digester](),
 	}
 }
 
 func (hd *Handlers) Initialize() error {
+	allowedHeaders := []string{"content-type"}
+	if hd.rateLimiter != nil {
+		allowedHeaders = append(allowedHeaders, hd.rateLimiter.KeyHeader())
+	}
+
 	cors := handlers.CORS(
 		handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "OPTIONS"}),
-		handlers.AllowedHeaders([]string{"content-type"}),
+		handlers.AllowedHeaders(allowedHeaders),
+		handlers.ExposedHeaders([]string{"Retry-After"}),
 		handlers.AllowedOrigins([]string{"*"}),
 		handlers.AllowCredentials(),
 	)
@@ -140,6 +162,26 @@ func (hd *Handlers) SetLimiter(f func(string) int64) *Handlers {
 	return hd
 }
 
+// SetRateLimiter sets the per ip and per api key rate limiter. It should be
+// called before Initialize.
+func (hd *Handlers) SetRateLimiter(rl *APIRateLimiter) *Handlers {
+	hd.rateLimiter = rl
+
+	return hd
+}
+
+func (hd *Handlers) SetSupplyConfig(c SupplyConfig) *Handlers {
+	hd.supply = c
+
+	return hd
+}
+
+func (hd *Handlers) SetJSONRPCConfig(c JSONRPCConfig) *Handlers {
+	hd.jsonRPC = c
+
+	return hd
+}
+
 func (hd *Handlers) Cache() Cache {
 	return hd.cache
 }
@@ -163,6 +205,10 @@ func (hd *Handlers) setHandlers() {
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathCurrency, hd.handleCurrency, true, get, get).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathCurrencyHolders, hd.handleCurrencyHolders, true, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathCurrencySupplyAudit, hd.handleCurrencySupplyAudit, false, get, get).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathManifests, hd.handleManifests, true, get, get).
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathOperations, hd.handleOperations, true, get, get).
@@ -185,6 +231,8 @@ func (hd *Handlers) setHandlers() {
 		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathAccountOperations, hd.handleAccountOperations, true, get, get).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathAccountBalanceHistory, hd.handleAccountBalanceHistory, true, get, get).
+		Methods(http.MethodOptions, "GET")
 	_ = hd.setHandler(HandlerPathAccounts, hd.handleAccounts, true, get, get).
 		Methods(http.MethodOptions, "GET")
 	// _ = hd.setHandler(HandlerPathOperationBuildFactTemplate, hd.handleOperationBuildFactTemplate, true).
@@ -199,11 +247,65 @@ func (hd *Handlers) setHandlers() {
 		Methods(http.MethodOptions, http.MethodPost)
 	_ = hd.setHandler(HandlerPathQueueSend, hd.handleQueueSend, false, post, post).
 		Methods(http.MethodOptions, http.MethodPost)
+	_ = hd.setHandler(HandlerPathMultisig, hd.handleMultisig, false, post, post).
+		Methods(http.MethodOptions, http.MethodPost)
+	// NOTE POST of multisig by fact appends the signs, so it is limited like
+	// the other POST.
+	_ = hd.setNamedHandler(HandlerPathMultisigByFact+"#post", HandlerPathMultisigByFact,
+		hd.handleMultisigByFact, false, post, post).
+		Methods(http.MethodPost)
+	_ = hd.setHandler(HandlerPathMultisigByFact, hd.handleMultisigByFact, false, get, get).
+		Methods(http.MethodOptions, http.MethodGet)
 	_ = hd.setHandler(HandlerPathNodeInfo, hd.handleNodeInfo, true, get, get).
 		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathMetrics, metrics.Default.Handler().ServeHTTP, false, get, get).
+		Methods(http.MethodOptions, "GET")
+	_ = hd.setHandler(HandlerPathDigestGaps, hd.handleDigestGaps, false, get, get).
+		Methods(http.MethodOptions, "GET")
+
+	if !hd.jsonRPC.IsEmpty() {
+		_ = hd.setHandler(HandlerPathJSONRPC, hd.handleJSONRPC, false, get, get).
+			Methods(http.MethodOptions, http.MethodPost)
+	}
+
+	sds := StateDigests()
+	for i := range sds {
+		if sds[i].SetHandlers != nil {
+			sds[i].SetHandlers(hd)
+		}
+	}
+}
+
+// SetHandler adds the http handler to digest API; it is for the handlers of
+// StateDigest.
+func (hd *Handlers) SetHandler(prefix string, h network.HTTPHandlerFunc, useCache bool, rps, burst int) *mux.Route {
+	return hd.setHandler(prefix, h, useCache, rps, burst)
+}
+
+func (hd *Handlers) Database() Store {
+	return hd.database
+}
+
+func (hd *Handlers) Encoder() encoder.Encoder {
+	return hd.enc
 }
 
 func (hd *Handlers) setHandler(prefix string, h network.HTTPHandlerFunc, useCache bool, rps, burst int) *mux.Route {
+	var name string
+	if prefix == "" || prefix == "/" {
+		name = "root"
+	} else {
+		name = prefix
+	}
+
+	return hd.setNamedHandler(name, prefix, h, useCache, rps, burst)
+}
+
+// setNamedHandler is setHandler with the route name; the same path can have
+// the multiple routes by methods with different limits.
+func (hd *Handlers) setNamedHandler(
+	name, prefix string, h network.HTTPHandlerFunc, useCache bool, rps, burst int,
+) *mux.Route {
 	var handler http.Handler
 	if !useCache {
 		handler = http.HandlerFunc(h)
@@ -213,13 +315,6 @@ func (hd *Handlers) setHandler(prefix string, h network.HTTPHandlerFunc, useCach
 		handler = ch
 	}
 
-	var name string
-	if prefix == "" || prefix == "/" {
-		name = "root"
-	} else {
-		name = prefix
-	}
-
 	var route *mux.Route
 	if r := hd.router.Get(name); r != nil {
 		route = r
@@ -229,6 +324,16 @@ func (hd *Handlers) setHandler(prefix string, h network.HTTPHandlerFunc, useCach
 
 	handler = RateLimiter(rps, burst)(handler)
 
+	if hd.rateLimiter != nil {
+		handler = hd.rateLimiter.MiddlewareByRequest(func(r *http.Request) RateLimitKind {
+			return RateLimitKindFromRequest(prefix, r)
+		})(handler)
+	}
+
+	if isAdminPath(prefix) {
+		handler = hd.rateLimiter.RequireAPIKey(handler)
+	}
+
 	/*
 		if rules, found := hd.rateLimit[prefix]; found {
 			handler = process.NewRateLimitMiddleware(
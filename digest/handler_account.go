@@ -129,13 +129,20 @@ func (hd *Handlers) handleAccountOperations(w http.ResponseWriter, r *http.Reque
 	offset := ParseStringQuery(r.URL.Query().Get("offset"))
 	reverse := ParseBoolQuery(r.URL.Query().Get("reverse"))
 
-	cachekey := CacheKey(r.URL.Path, StringOffsetQuery(offset), StringBoolQuery("reverse", reverse))
+	of, err := ParseOperationFilter(r.URL.Query(), hd.enc)
+	if err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := CacheKey(r.URL.Path, StringOffsetQuery(offset), StringBoolQuery("reverse", reverse), of.Query())
 	if err := LoadFromCache(hd.cache, cachekey, w); err == nil {
 		return
 	}
 
 	if v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
-		i, filled, err := hd.handleAccountOperationsInGroup(address, offset, reverse, limit)
+		i, filled, err := hd.handleAccountOperationsInGroup(address, offset, reverse, limit, of)
 
 		return []interface{}{i, filled}, err
 	}); err != nil {
@@ -167,6 +174,7 @@ func (hd *Handlers) handleAccountOperationsInGroup(
 	offset string,
 	reverse bool,
 	l int64,
+	of OperationFilter,
 ) ([]byte, bool, error) {
 	var limit int64
 	if l < 0 {
@@ -177,7 +185,7 @@ func (hd *Handlers) handleAccountOperationsInGroup(
 
 	var vas []Hal
 	if err := hd.database.OperationsByAddress(
-		address, true, reverse, offset, limit,
+		address, true, reverse, offset, limit, of,
 		func(_ mitumutil.Hash, va OperationValue) (bool, error) {
 			hal, err := hd.buildOperationHal(va)
 			if err != nil {
@@ -194,7 +202,7 @@ func (hd *Handlers) handleAccountOperationsInGroup(
 	//	return nil, false, mitumutil.ErrNotFound.Errorf("operations in handleAccountsOperations")
 	//}
 
-	i, err := hd.buildAccountOperationsHal(address, vas, offset, reverse)
+	i, err := hd.buildAccountOperationsHal(address, vas, offset, reverse, of)
 	if err != nil {
 		return nil, false, err
 	}
@@ -208,6 +216,7 @@ func (hd *Handlers) buildAccountOperationsHal(
 	vas []Hal,
 	offset string,
 	reverse bool,
+	of OperationFilter,
 ) (Hal, error) {
 	var hal Hal
 
@@ -220,6 +229,7 @@ func (hd *Handlers) buildAccountOperationsHal(
 	if err != nil {
 		return nil, err
 	}
+	baseSelf = AddQueryValue(baseSelf, of.Query())
 
 	self := baseSelf
 	if len(offset) > 0 {
@@ -419,7 +429,7 @@ func (hd *Handlers) accountsByPublickey(
 	offsetHeight := base.NilHeight
 	var lastaddress base.Address
 
-	switch h, err := hd.database.topHeightByPublickey(pub); {
+	switch h, err := hd.database.TopHeightByPublickey(pub); {
 	case err != nil:
 		return offsetHeight, nil, nil, err
 	case h == base.NilHeight:
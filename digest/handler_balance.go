@@ -0,0 +1,253 @@
+package digest
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+type balanceHistoryQuery struct {
+	height base.Height
+	from   base.Height
+	to     base.Height
+	offset base.Height
+}
+
+func (hd *Handlers) handleAccountBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	var address base.Address
+	if a, err := base.DecodeAddress(strings.TrimSpace(mux.Vars(r)["address"]), hd.enc); err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	} else if err := a.IsValid(nil); err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	} else {
+		address = a
+	}
+
+	cid, err, status := ParseRequest(w, r, "currency_id")
+	if err != nil {
+		HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	q, err := parseBalanceHistoryQuery(r)
+	if err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	limit := ParseLimitQuery(r.URL.Query().Get("limit"))
+	reverse := ParseBoolQuery(r.URL.Query().Get("reverse"))
+
+	cachekey := CacheKey(r.URL.Path, r.URL.Query().Encode())
+	if err := LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		if q.height > base.NilHeight {
+			b, err := hd.handleAccountBalanceByHeightInGroup(address, cid, q.height)
+
+			return []interface{}{b, true}, err
+		}
+
+		i, filled, err := hd.handleAccountBalanceHistoryInGroup(address, cid, q, reverse, limit)
+
+		return []interface{}{i, filled}, err
+	}); err != nil {
+		HTTP2HandleError(w, err)
+	} else {
+		var b []byte
+		var filled bool
+		{
+			l := v.([]interface{})
+			b = l[0].([]byte)
+			filled = l[1].(bool)
+		}
+
+		HTTP2WriteHalBytes(hd.enc, w, b, http.StatusOK)
+
+		if !shared {
+			expire := hd.expireNotFilled
+			if filled && (q.offset > base.NilHeight || (q.height > base.NilHeight && q.height <= hd.database.LastBlock())) {
+				expire = time.Hour * 30
+			}
+
+			HTTP2WriteCache(w, cachekey, expire)
+		}
+	}
+}
+
+func (hd *Handlers) handleAccountBalanceHistoryInGroup(
+	address base.Address,
+	cid string,
+	q balanceHistoryQuery,
+	reverse bool,
+	l int64,
+) ([]byte, bool, error) {
+	var limit int64
+	if l < 0 {
+		limit = hd.itemsLimiter("balance-history")
+	} else {
+		limit = l
+	}
+
+	var vas []Hal
+	var last base.Height
+	if err := hd.database.BalanceHistory(
+		address, cid, q.from, q.to, q.offset, reverse, limit,
+		func(st base.State) (bool, error) {
+			hal, err := hd.buildBalanceHal(st)
+			if err != nil {
+				return false, err
+			}
+			vas = append(vas, hal)
+			last = st.Height()
+
+			return true, nil
+		},
+	); err != nil {
+		return nil, false, err
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathAccountBalanceHistory, "address", address.String(), "currency_id", cid)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rangeQuery := baseSelf
+	if q.from > base.NilHeight {
+		rangeQuery = AddQueryValue(rangeQuery, "from="+q.from.String())
+	}
+
+	if q.to > base.NilHeight {
+		rangeQuery = AddQueryValue(rangeQuery, "to="+q.to.String())
+	}
+
+	self := rangeQuery
+	if q.offset > base.NilHeight {
+		self = AddQueryValue(self, StringOffsetQuery(q.offset.String()))
+	}
+
+	if reverse {
+		self = AddQueryValue(self, StringBoolQuery("reverse", reverse))
+	}
+
+	var hal Hal = NewBaseHal(vas, NewHalLink(self, nil))
+
+	h, err := hd.combineURL(HandlerPathAccount, "address", address.String())
+	if err != nil {
+		return nil, false, err
+	}
+	hal = hal.AddLink("account", NewHalLink(h, nil))
+
+	if len(vas) > 0 {
+		next := AddQueryValue(rangeQuery, StringOffsetQuery(last.String()))
+		if reverse {
+			next = AddQueryValue(next, StringBoolQuery("reverse", reverse))
+		}
+
+		hal = hal.AddLink("next", NewHalLink(next, nil))
+	}
+
+	hal = hal.AddLink("reverse", NewHalLink(AddQueryValue(rangeQuery, StringBoolQuery("reverse", !reverse)), nil))
+
+	b, err := hd.enc.Marshal(hal)
+
+	return b, int64(len(vas)) == limit, err
+}
+
+func (hd *Handlers) handleAccountBalanceByHeightInGroup(
+	address base.Address,
+	cid string,
+	height base.Height,
+) ([]byte, error) {
+	switch st, found, err := hd.database.BalanceByHeight(address, cid, height); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, mitumutil.ErrNotFound.Errorf("Balance of %v, %v at height %v", address, cid, height)
+	default:
+		hal, err := hd.buildBalanceHal(st)
+		if err != nil {
+			return nil, err
+		}
+
+		return hd.enc.Marshal(hal)
+	}
+}
+
+func (hd *Handlers) buildBalanceHal(st base.State) (Hal, error) {
+	am, err := currency.StateBalanceValue(st)
+	if err != nil {
+		return nil, err
+	}
+
+	var hal Hal = NewBaseHal(st, HalLink{})
+	hal = hal.AddExtras("currency", am.Currency().String()).
+		AddExtras("amount", am.Big().String())
+
+	h, err := hd.combineURL(HandlerPathBlockByHeight, "height", st.Height().String())
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("block", NewHalLink(h, nil))
+
+	for i := range st.Operations() {
+		h, err := hd.combineURL(HandlerPathOperation, "hash", st.Operations()[i].String())
+		if err != nil {
+			return nil, err
+		}
+		hal = hal.AddLink("operations", NewHalLink(h, nil))
+	}
+
+	return hal, nil
+}
+
+func parseBalanceHistoryQuery(r *http.Request) (balanceHistoryQuery, error) {
+	q := balanceHistoryQuery{
+		height: base.NilHeight,
+		from:   base.NilHeight,
+		to:     base.NilHeight,
+		offset: base.NilHeight,
+	}
+
+	for _, i := range []struct {
+		h    *base.Height
+		name string
+	}{
+		{h: &q.height, name: "height"},
+		{h: &q.from, name: "from"},
+		{h: &q.to, name: "to"},
+		{h: &q.offset, name: "offset"},
+	} {
+		s := ParseStringQuery(r.URL.Query().Get(i.name))
+		if len(s) < 1 {
+			continue
+		}
+
+		h, err := base.ParseHeightString(s)
+		if err != nil {
+			return q, errors.WithMessagef(err, "invalid %s query", i.name)
+		}
+
+		*i.h = h
+	}
+
+	if q.from > base.NilHeight && q.to > base.NilHeight && q.from > q.to {
+		return q, errors.Errorf("Invalid height range, from %v > to %v", q.from, q.to)
+	}
+
+	return q, nil
+}
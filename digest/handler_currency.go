@@ -35,7 +35,7 @@ func (hd *Handlers) handleCurrenciesInGroup() ([]byte, error) {
 	var hal Hal = NewBaseHal(nil, NewHalLink(HandlerPathCurrencies, nil))
 	hal = hal.AddLink("currency:{currency_id}", NewHalLink(HandlerPathCurrency, nil).SetTemplated())
 
-	cids, err := hd.database.currencies()
+	cids, err := hd.database.Currencies()
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +89,7 @@ func (hd *Handlers) handleCurrencyInGroup(cid string) ([]byte, error) {
 	var de types.CurrencyDesign
 	var st base.State
 
-	de, st, err := hd.database.currency(cid)
+	de, st, err := hd.database.Currency(cid)
 	if err != nil {
 		return nil, err
 	}
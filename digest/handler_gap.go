@@ -0,0 +1,48 @@
+package digest
+
+import (
+	"net/http"
+
+	"github.com/ProtoconNet/mitum2/base"
+)
+
+// SetDigester sets the digester for the digest gaps handler. The digester can
+// be set after the handlers are initialized.
+func (hd *Handlers) SetDigester(di *Digester) *Handlers {
+	_ = hd.digester.SetValue(di)
+
+	return hd
+}
+
+func (hd *Handlers) handleDigestGaps(w http.ResponseWriter, r *http.Request) {
+	di, isempty := hd.digester.Value()
+	if isempty || di == nil {
+		HTTP2NotSupported(w, nil)
+
+		return
+	}
+
+	hal, err := hd.buildDigestGapsHal(di.Gaps())
+	if err != nil {
+		HTTP2HandleError(w, err)
+
+		return
+	}
+
+	HTTP2WriteHal(hd.enc, w, hal, http.StatusOK)
+}
+
+func (hd *Handlers) buildDigestGapsHal(gaps DigestGaps) (Hal, error) {
+	var hal Hal = NewBaseHal(gaps, NewHalLink(HandlerPathDigestGaps, nil))
+
+	if gaps.LastBlock >= base.GenesisHeight {
+		h, err := hd.combineURL(HandlerPathBlockByHeight, "height", gaps.LastBlock.String())
+		if err != nil {
+			return nil, err
+		}
+
+		hal = hal.AddLink("last_block", NewHalLink(h, nil))
+	}
+
+	return hal, nil
+}
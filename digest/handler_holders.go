@@ -0,0 +1,102 @@
+package digest
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func (hd *Handlers) handleCurrencyHolders(w http.ResponseWriter, r *http.Request) {
+	cid, err, status := ParseRequest(w, r, "currency_id")
+	if err != nil {
+		HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	limit := ParseLimitQuery(r.URL.Query().Get("limit"))
+
+	var offset int64
+	if s := ParseStringQuery(r.URL.Query().Get("offset")); len(s) > 0 {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || i < 0 {
+			HTTP2ProblemWithError(w, errors.Errorf("Invalid offset, %q", s), http.StatusBadRequest)
+
+			return
+		}
+
+		offset = i
+	}
+
+	cachekey := CacheKey(r.URL.Path, StringOffsetQuery(strconv.FormatInt(offset, 10)), strconv.FormatInt(limit, 10))
+	if err := LoadFromCache(hd.cache, cachekey, w); err == nil {
+		return
+	}
+
+	if v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
+		return hd.handleCurrencyHoldersInGroup(cid, offset, limit)
+	}); err != nil {
+		HTTP2HandleError(w, err)
+	} else {
+		HTTP2WriteHalBytes(hd.enc, w, v.([]byte), http.StatusOK)
+
+		if !shared {
+			HTTP2WriteCache(w, cachekey, time.Second*3)
+		}
+	}
+}
+
+func (hd *Handlers) handleCurrencyHoldersInGroup(cid string, offset, l int64) ([]byte, error) {
+	limit := l
+	if limit < 0 {
+		limit = hd.itemsLimiter("currency-holders")
+	}
+
+	de, _, err := hd.database.Currency(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	holders, total, err := hd.database.CurrencyHolders(cid, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := hd.supply.Excluded(de)
+
+	excludedSum, err := hd.database.CurrencyBalanceSum(cid, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	baseSelf, err := hd.combineURL(HandlerPathCurrencyHolders, "currency_id", cid)
+	if err != nil {
+		return nil, err
+	}
+
+	self := baseSelf
+	if offset > 0 {
+		self = AddQueryValue(self, StringOffsetQuery(strconv.FormatInt(offset, 10)))
+	}
+
+	var hal Hal = NewBaseHal(holders, NewHalLink(self, nil))
+
+	h, err := hd.combineURL(HandlerPathCurrency, "currency_id", cid)
+	if err != nil {
+		return nil, err
+	}
+	hal = hal.AddLink("currency", NewHalLink(h, nil))
+
+	if next := offset + int64(len(holders)); len(holders) > 0 && next < total {
+		hal = hal.AddLink("next", NewHalLink(AddQueryValue(baseSelf, StringOffsetQuery(strconv.FormatInt(next, 10))), nil))
+	}
+
+	hal = hal.AddExtras("total_holders", total).
+		AddExtras("total_supply", de.TotalSupply().String()).
+		AddExtras("circulating_supply", de.TotalSupply().Sub(excludedSum).String()).
+		AddExtras("excluded_addresses", excluded)
+
+	return hd.enc.Marshal(hal)
+}
@@ -0,0 +1,517 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/valuehash"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	JSONRPCVersion = "2.0"
+
+	JSONRPCErrorParse          = -32700
+	JSONRPCErrorInvalidRequest = -32600
+	JSONRPCErrorMethodNotFound = -32601
+	JSONRPCErrorInvalidParams  = -32602
+	JSONRPCErrorInternal       = -32603
+	// JSONRPCErrorLimitExceeded is the error of the ethereum clients for the
+	// request over the rate limit.
+	JSONRPCErrorLimitExceeded = -32005
+
+	jsonRPCMaxBodySize = 1 << 20
+	jsonRPCMaxBatch    = 100
+)
+
+var jsonRPCZeroHash = "0x" + strings.Repeat("0", 64) //nolint:gomnd //...
+
+// JSONRPCError is the error object of JSON-RPC 2.0 response.
+type JSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newJSONRPCError(code int, format string, args ...interface{}) *JSONRPCError {
+	return &JSONRPCError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("json rpc error(%d): %s", e.Code, e.Message)
+}
+
+type jsonRPCRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	ID      json.RawMessage   `json:"id,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+}
+
+// handleJSONRPC serves the read only subset of ethereum JSON-RPC API, so the
+// ethereum tools can query the blocks, operations and balances of the
+// configured currency. Both single and batch requests are supported; every
+// call of batch is charged to the rate limiter like the single request, and
+// the calls over the limit get the JSONRPCErrorLimitExceeded error.
+func (hd *Handlers) handleJSONRPC(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, jsonRPCMaxBodySize))
+	if err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusInternalServerError)
+
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+
+	if len(body) < 1 || body[0] != '[' {
+		res := hd.jsonRPCCall(body)
+		if res == nil {
+			w.WriteHeader(http.StatusNoContent)
+
+			return
+		}
+
+		writeJSONRPCResponse(w, res)
+
+		return
+	}
+
+	var reqs []json.RawMessage
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		writeJSONRPCResponse(w, jsonRPCErrorResponse(nil, newJSONRPCError(JSONRPCErrorParse, "parse error")))
+
+		return
+	}
+
+	switch {
+	case len(reqs) < 1:
+		writeJSONRPCResponse(w,
+			jsonRPCErrorResponse(nil, newJSONRPCError(JSONRPCErrorInvalidRequest, "empty batch")))
+
+		return
+	case len(reqs) > jsonRPCMaxBatch:
+		writeJSONRPCResponse(w,
+			jsonRPCErrorResponse(nil, newJSONRPCError(JSONRPCErrorInvalidRequest, "too many requests in batch")))
+
+		return
+	}
+
+	ress := make([]*jsonRPCResponse, 0, len(reqs))
+
+	for i := range reqs {
+		var res *jsonRPCResponse
+
+		// NOTE the first call is charged by the rate limit middleware.
+		if i > 0 && !hd.jsonRPCAllow(r) {
+			res = jsonRPCLimitExceededResponse(reqs[i])
+		} else {
+			res = hd.jsonRPCCall(reqs[i])
+		}
+
+		if res != nil {
+			ress = append(ress, res)
+		}
+	}
+
+	if len(ress) < 1 {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	writeJSONRPCResponse(w, ress)
+}
+
+// jsonRPCCall returns nil for notification, the request without id.
+func (hd *Handlers) jsonRPCCall(b []byte) *jsonRPCResponse {
+	var req jsonRPCRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return jsonRPCErrorResponse(nil, newJSONRPCError(JSONRPCErrorParse, "parse error"))
+		}
+
+		return jsonRPCErrorResponse(nil, newJSONRPCError(JSONRPCErrorInvalidRequest, "invalid request"))
+	}
+
+	if req.JSONRPC != JSONRPCVersion || len(req.Method) < 1 {
+		return jsonRPCErrorResponse(req.ID, newJSONRPCError(JSONRPCErrorInvalidRequest, "invalid request"))
+	}
+
+	result, err := hd.jsonRPCMethod(req.Method, req.Params)
+
+	if len(req.ID) < 1 {
+		return nil
+	}
+
+	if err != nil {
+		var rerr *JSONRPCError
+		if !errors.As(err, &rerr) {
+			// NOTE the internal error is not exposed to client.
+			hd.Log().Err(err).Str("method", req.Method).Msg("json rpc call")
+
+			rerr = newJSONRPCError(JSONRPCErrorInternal, "internal error")
+		}
+
+		return jsonRPCErrorResponse(req.ID, rerr)
+	}
+
+	rb, err := json.Marshal(result)
+	if err != nil {
+		hd.Log().Err(err).Str("method", req.Method).Msg("json rpc call; marshal result")
+
+		return jsonRPCErrorResponse(req.ID, newJSONRPCError(JSONRPCErrorInternal, "internal error"))
+	}
+
+	return &jsonRPCResponse{JSONRPC: JSONRPCVersion, ID: req.ID, Result: rb}
+}
+
+func (hd *Handlers) jsonRPCAllow(r *http.Request) bool {
+	if hd.rateLimiter == nil {
+		return true
+	}
+
+	allowed, _, err := hd.rateLimiter.Allow(r, RateLimitKindRead)
+
+	return err == nil && allowed
+}
+
+// jsonRPCLimitExceededResponse returns nil for notification.
+func jsonRPCLimitExceededResponse(b []byte) *jsonRPCResponse {
+	var req struct {
+		ID json.RawMessage `json:"id,omitempty"`
+	}
+
+	if err := json.Unmarshal(b, &req); err == nil && len(req.ID) < 1 {
+		return nil
+	}
+
+	return jsonRPCErrorResponse(req.ID, newJSONRPCError(JSONRPCErrorLimitExceeded, "rate limit exceeded"))
+}
+
+func (hd *Handlers) jsonRPCMethod(method string, params []json.RawMessage) (interface{}, error) {
+	switch method {
+	case "eth_chainId":
+		return hexutil.EncodeUint64(hd.jsonRPC.ChainID()), nil
+	case "net_version":
+		return strconv.FormatUint(hd.jsonRPC.ChainID(), 10), nil
+	case "eth_blockNumber":
+		height := hd.database.LastBlock()
+		if height < base.GenesisHeight {
+			height = base.GenesisHeight
+		}
+
+		return hexutil.EncodeUint64(uint64(height.Int64())), nil
+	case "eth_getBalance":
+		return hd.jsonRPCGetBalance(params)
+	case "eth_getBlockByNumber":
+		return hd.jsonRPCGetBlockByNumber(params)
+	case "eth_getTransactionByHash":
+		return hd.jsonRPCGetTransactionByHash(params)
+	default:
+		return nil, newJSONRPCError(JSONRPCErrorMethodNotFound, "method not found, %q", method)
+	}
+}
+
+func (hd *Handlers) jsonRPCGetBalance(params []json.RawMessage) (interface{}, error) {
+	var s, tag string
+	if err := jsonRPCParams(params, 1, &s, &tag); err != nil {
+		return nil, err
+	}
+
+	address, err := addressFromJSONRPC(s)
+	if err != nil {
+		return nil, err
+	}
+
+	height, err := hd.jsonRPCHeight(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	st, found, err := hd.database.BalanceByHeight(address, hd.jsonRPC.Currency().String(), height)
+
+	switch {
+	case err != nil:
+		return nil, err
+	case !found:
+		return hexutil.EncodeBig(big.NewInt(0)), nil
+	}
+
+	am, err := statecurrency.StateBalanceValue(st)
+	if err != nil {
+		return nil, err
+	}
+
+	return hexutil.EncodeBig(am.Big().Int), nil
+}
+
+func (hd *Handlers) jsonRPCGetBlockByNumber(params []json.RawMessage) (interface{}, error) {
+	var tag string
+	var full bool
+
+	if err := jsonRPCParams(params, 1, &tag, &full); err != nil {
+		return nil, err
+	}
+
+	height, err := hd.jsonRPCHeight(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	m, opsCount, _, _, _, err := hd.database.ManifestByHeight(height)
+
+	switch {
+	case errors.Is(err, mitumutil.ErrNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	txs := make([]interface{}, 0, opsCount)
+
+	if opsCount > 0 {
+		if err := hd.database.OperationsByHeight(height, "", false, full, int64(opsCount),
+			func(h mitumutil.Hash, va OperationValue, _ int64) (bool, error) {
+				if !full {
+					txs = append(txs, hexutil.Encode(h.Bytes()))
+
+					return true, nil
+				}
+
+				txs = append(txs, hd.jsonRPCTransaction(va, m))
+
+				return true, nil
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	parent := jsonRPCZeroHash
+	if m.Previous() != nil {
+		parent = hexutil.Encode(m.Previous().Bytes())
+	}
+
+	return map[string]interface{}{
+		"number":           hexutil.EncodeUint64(uint64(m.Height().Int64())),
+		"hash":             hexutil.Encode(m.Hash().Bytes()),
+		"parentHash":       parent,
+		"timestamp":        hexutil.EncodeUint64(uint64(m.ProposedAt().Unix())),
+		"transactionsRoot": jsonRPCHash(m.OperationsTree()),
+		"stateRoot":        jsonRPCHash(m.StatesTree()),
+		"receiptsRoot":     jsonRPCZeroHash,
+		"sha3Uncles":       jsonRPCZeroHash,
+		"logsBloom":        "0x" + strings.Repeat("0", 512), //nolint:gomnd //...
+		"miner":            "0x" + strings.Repeat("0", 40),  //nolint:gomnd //...
+		"nonce":            "0x0000000000000000",
+		"extraData":        "0x",
+		"difficulty":       "0x0",
+		"totalDifficulty":  "0x0",
+		"size":             "0x0",
+		"gasLimit":         "0x0",
+		"gasUsed":          "0x0",
+		"uncles":           []string{},
+		"transactions":     txs,
+	}, nil
+}
+
+func (hd *Handlers) jsonRPCGetTransactionByHash(params []json.RawMessage) (interface{}, error) {
+	var s string
+	if err := jsonRPCParams(params, 1, &s); err != nil {
+		return nil, err
+	}
+
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) < 1 {
+		return nil, newJSONRPCError(JSONRPCErrorInvalidParams, "invalid transaction hash, %q", s)
+	}
+
+	va, found, err := hd.database.Operation(valuehash.NewBytes(b), true)
+
+	switch {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, nil
+	}
+
+	m, _, _, _, _, err := hd.database.ManifestByHeight(va.Height())
+	if err != nil && !errors.Is(err, mitumutil.ErrNotFound) {
+		return nil, err
+	}
+
+	return hd.jsonRPCTransaction(va, m), nil
+}
+
+// jsonRPCTransaction builds the ethereum transaction object of operation; the
+// transaction hash is the fact hash. The to and value are filled only for the
+// transfer of the configured currency.
+func (hd *Handlers) jsonRPCTransaction(va OperationValue, m base.Manifest) map[string]interface{} {
+	fact := va.Operation().Fact()
+
+	tx := map[string]interface{}{
+		"hash":             hexutil.Encode(fact.Hash().Bytes()),
+		"blockNumber":      hexutil.EncodeUint64(uint64(va.Height().Int64())),
+		"blockHash":        nil,
+		"transactionIndex": hexutil.EncodeUint64(va.Index()),
+		"from":             nil,
+		"to":               nil,
+		"value":            "0x0",
+		"input":            "0x",
+		"nonce":            "0x0",
+		"gas":              "0x0",
+		"gasPrice":         "0x0",
+		"type":             "0x0",
+	}
+
+	if m != nil {
+		tx["blockHash"] = hexutil.Encode(m.Hash().Bytes())
+	}
+
+	if i, ok := fact.(interface{ Sender() base.Address }); ok {
+		tx["from"] = addressToJSONRPC(i.Sender())
+	}
+
+	if i, ok := fact.(currency.TransferFact); ok {
+		value := big.NewInt(0)
+
+		items := i.Items()
+		for j := range items {
+			ams := items[j].Amounts()
+			for k := range ams {
+				if ams[k].Currency() == hd.jsonRPC.Currency() {
+					value.Add(value, ams[k].Big().Int)
+				}
+			}
+		}
+
+		if len(items) == 1 {
+			tx["to"] = addressToJSONRPC(items[0].Receiver())
+		}
+
+		tx["value"] = hexutil.EncodeBig(value)
+	}
+
+	return tx
+}
+
+// jsonRPCHeight parses the block number or tag; "latest", "pending", "safe"
+// and "finalized" are the last block of digest.
+func (hd *Handlers) jsonRPCHeight(tag string) (base.Height, error) {
+	switch tag {
+	case "", "latest", "pending", "safe", "finalized":
+		return hd.database.LastBlock(), nil
+	case "earliest":
+		return base.GenesisHeight, nil
+	}
+
+	n, err := hexutil.DecodeUint64(tag)
+	if err != nil {
+		return base.NilHeight, newJSONRPCError(JSONRPCErrorInvalidParams, "invalid block number, %q", tag)
+	}
+
+	return base.Height(int64(n)), nil //nolint:gosec //...
+}
+
+// jsonRPCParams decodes the positional params; the params after required are
+// optional.
+func jsonRPCParams(params []json.RawMessage, required int, vs ...interface{}) error {
+	switch {
+	case len(params) < required:
+		return newJSONRPCError(JSONRPCErrorInvalidParams, "missing params; %d < %d", len(params), required)
+	case len(params) > len(vs):
+		return newJSONRPCError(JSONRPCErrorInvalidParams, "too many params; %d > %d", len(params), len(vs))
+	}
+
+	for i := range params {
+		if err := json.Unmarshal(params[i], vs[i]); err != nil {
+			return newJSONRPCError(JSONRPCErrorInvalidParams, "invalid param %d; %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// addressFromJSONRPC converts the ethereum address to the mitum currency
+// address.
+func addressFromJSONRPC(s string) (base.Address, error) {
+	if !strings.HasPrefix(s, "0x") || len(s) != 42 { //nolint:gomnd //...
+		return nil, newJSONRPCError(JSONRPCErrorInvalidParams, "invalid address, %q", s)
+	}
+
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, newJSONRPCError(JSONRPCErrorInvalidParams, "invalid address, %q", s)
+	}
+
+	var buf [42]byte
+
+	copy(buf[:2], "0x")
+	hex.Encode(buf[2:], b)
+
+	return types.NewAddress(string(types.ChecksumHex(buf))), nil
+}
+
+// addressToJSONRPC returns the ethereum address of the mitum currency
+// address; nil for the zero address.
+func addressToJSONRPC(a base.Address) interface{} {
+	if a == nil {
+		return nil
+	}
+
+	s := strings.TrimSuffix(a.String(), types.AddressHint.Type().String())
+	if !strings.HasPrefix(s, "0x") || len(s) != 42 { //nolint:gomnd //...
+		return nil
+	}
+
+	return s
+}
+
+func jsonRPCHash(h mitumutil.Hash) string {
+	if h == nil {
+		return jsonRPCZeroHash
+	}
+
+	return hexutil.Encode(h.Bytes())
+}
+
+func jsonRPCErrorResponse(id json.RawMessage, err *JSONRPCError) *jsonRPCResponse {
+	if len(id) < 1 {
+		id = json.RawMessage("null")
+	}
+
+	return &jsonRPCResponse{JSONRPC: JSONRPCVersion, ID: id, Error: err}
+}
+
+func writeJSONRPCResponse(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
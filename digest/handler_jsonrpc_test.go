@@ -0,0 +1,434 @@
+package digest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/digest"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/launch"
+	"github.com/ProtoconNet/mitum2/util/logging"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/mux"
+)
+
+type testJSONRPCResponse struct {
+	ID     json.RawMessage      `json:"id"`
+	Result json.RawMessage      `json:"result"`
+	Error  *digest.JSONRPCError `json:"error"`
+}
+
+func newTestJSONRPCHandler(t *testing.T, b *testLeveldbBlocks, rl *digest.APIRateLimiter) http.Handler {
+	t.Helper()
+
+	ctx := context.WithValue(context.Background(), launch.LoggingContextKey, logging.NewLogging(nil))
+
+	c, err := digest.NewJSONRPCConfig(&digest.JSONRPCYAML{ChainID: 1234, Currency: b.cid.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := mux.NewRouter()
+
+	hd := digest.NewHandlers(ctx, testNetworkID, nil, b.enc, b.db,
+		digest.NewLocalMemCache(100, time.Second), router, nil).
+		SetJSONRPCConfig(c)
+
+	if rl != nil {
+		hd = hd.SetRateLimiter(rl)
+	}
+
+	if err := hd.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	return router
+}
+
+func callTestJSONRPC(t *testing.T, h http.Handler, body string) (int, []byte) {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, digest.HandlerPathJSONRPC, strings.NewReader(body))
+	r.RemoteAddr = "127.0.0.1:1234"
+	r.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w.Code, w.Body.Bytes()
+}
+
+func callTestJSONRPCSingle(t *testing.T, h http.Handler, body string) testJSONRPCResponse {
+	t.Helper()
+
+	code, b := callTestJSONRPC(t, h, body)
+	if code != http.StatusOK {
+		t.Fatalf("unexpected status, %d: %s", code, string(b))
+	}
+
+	var res testJSONRPCResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		t.Fatalf("unmarshal response: %v: %s", err, string(b))
+	}
+
+	return res
+}
+
+func ethereumAddress(a base.Address) string {
+	return strings.TrimSuffix(a.String(), types.AddressHint.Type().String())
+}
+
+func TestJSONRPC(t *testing.T) {
+	b := newTestLeveldbBlocks(t)
+
+	receiverKey, err := types.NewBaseAccountKey(types.NewMEPrivatekey().Publickey(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiverKeys, err := types.NewBaseAccountKeys([]types.AccountKey{receiverKey}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiver, err := types.NewAddressFromKeys(receiverKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.commit(base.GenesisHeight, nil, []base.State{
+		b.accountState(base.GenesisHeight),
+		b.balanceState(base.GenesisHeight, 100),
+	})
+
+	op := b.transfer(receiver, 20)
+
+	b.commit(base.GenesisHeight+1, []base.Operation{op}, []base.State{
+		b.balanceState(base.GenesisHeight+1, 80),
+	})
+
+	h := newTestJSONRPCHandler(t, b, nil)
+
+	txhash := hexutil.Encode(op.Fact().Hash().Bytes())
+
+	t.Run("methods", func(t *testing.T) {
+		for _, i := range []struct {
+			name     string
+			body     string
+			expected string
+		}{
+			{
+				name:     "eth_chainId",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`,
+				expected: `"0x4d2"`,
+			},
+			{
+				name:     "net_version",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"net_version"}`,
+				expected: `"1234"`,
+			},
+			{
+				name:     "eth_blockNumber",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"}`,
+				expected: `"0x1"`,
+			},
+			{
+				name:     "eth_getBalance; lowercase address",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["` + strings.ToLower(ethereumAddress(b.sender)) + `","latest"]}`,
+				expected: `"0x50"`,
+			},
+			{
+				name:     "eth_getBalance; by height",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["` + ethereumAddress(b.sender) + `","0x0"]}`,
+				expected: `"0x64"`,
+			},
+			{
+				name:     "eth_getBalance; unknown address",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x` + strings.Repeat("1", 40) + `"]}`,
+				expected: `"0x0"`,
+			},
+			{
+				name:     "eth_getBlockByNumber; unknown block",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x9",false]}`,
+				expected: `null`,
+			},
+			{
+				name:     "eth_getTransactionByHash; unknown transaction",
+				body:     `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0x` + strings.Repeat("1", 64) + `"]}`,
+				expected: `null`,
+			},
+		} {
+			t.Run(i.name, func(t *testing.T) {
+				res := callTestJSONRPCSingle(t, h, i.body)
+
+				switch {
+				case res.Error != nil:
+					t.Fatal(res.Error)
+				case string(res.ID) != "1":
+					t.Fatalf("unexpected id, %s", string(res.ID))
+				case string(res.Result) != i.expected:
+					t.Fatalf("expected %s, but %s", i.expected, string(res.Result))
+				}
+			})
+		}
+	})
+
+	t.Run("block and transaction", func(t *testing.T) {
+		res := callTestJSONRPCSingle(t, h, `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["latest",false]}`)
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		var block struct {
+			Number       string   `json:"number"`
+			Transactions []string `json:"transactions"`
+		}
+
+		if err := json.Unmarshal(res.Result, &block); err != nil {
+			t.Fatal(err)
+		}
+
+		if block.Number != "0x1" || len(block.Transactions) != 1 || block.Transactions[0] != txhash {
+			t.Fatalf("unexpected block, %v", block)
+		}
+
+		res = callTestJSONRPCSingle(t, h, `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["`+txhash+`"]}`)
+		if res.Error != nil {
+			t.Fatal(res.Error)
+		}
+
+		var tx struct {
+			Hash        string `json:"hash"`
+			BlockNumber string `json:"blockNumber"`
+			From        string `json:"from"`
+			To          string `json:"to"`
+			Value       string `json:"value"`
+		}
+
+		if err := json.Unmarshal(res.Result, &tx); err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case tx.Hash != txhash, tx.BlockNumber != "0x1", tx.Value != "0x14":
+			t.Fatalf("unexpected transaction, %v", tx)
+		case tx.From != ethereumAddress(b.sender):
+			t.Fatalf("unexpected from, %q != %q", tx.From, ethereumAddress(b.sender))
+		case tx.To != ethereumAddress(receiver):
+			t.Fatalf("unexpected to, %q != %q", tx.To, ethereumAddress(receiver))
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, i := range []struct {
+			name string
+			body string
+			id   string
+			code int
+		}{
+			{name: "parse error", body: `{"jsonrpc":"2.0",`, id: "null", code: digest.JSONRPCErrorParse},
+			{name: "not object", body: `"eth_chainId"`, id: "null", code: digest.JSONRPCErrorInvalidRequest},
+			{
+				name: "wrong version",
+				body: `{"jsonrpc":"1.0","id":1,"method":"eth_chainId"}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidRequest,
+			},
+			{
+				name: "empty method",
+				body: `{"jsonrpc":"2.0","id":"a"}`,
+				id:   `"a"`,
+				code: digest.JSONRPCErrorInvalidRequest,
+			},
+			{
+				name: "method not found",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_sendRawTransaction","params":["0x00"]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorMethodNotFound,
+			},
+			{
+				name: "missing params",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance"}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+			{
+				name: "too many params",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["` + txhash + `","0x1"]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+			{
+				name: "short address",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x1234"]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+			{
+				name: "mitum address",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["` + b.sender.String() + `"]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+			{
+				name: "not hex address",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x` + strings.Repeat("z", 40) + `"]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+			{
+				name: "invalid block number",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["1",false]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+			{
+				name: "invalid transaction hash",
+				body: `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0x"]}`,
+				id:   "1",
+				code: digest.JSONRPCErrorInvalidParams,
+			},
+		} {
+			t.Run(i.name, func(t *testing.T) {
+				res := callTestJSONRPCSingle(t, h, i.body)
+
+				switch {
+				case res.Error == nil:
+					t.Fatalf("expected error, but %s", string(res.Result))
+				case res.Error.Code != i.code:
+					t.Fatalf("expected code %d, but %v", i.code, res.Error)
+				case string(res.ID) != i.id:
+					t.Fatalf("expected id %s, but %s", i.id, string(res.ID))
+				}
+			})
+		}
+	})
+
+	t.Run("notification", func(t *testing.T) {
+		for _, body := range []string{
+			`{"jsonrpc":"2.0","method":"eth_chainId"}`,
+			// NOTE the error of notification is not returned
+			`{"jsonrpc":"2.0","method":"eth_unknown"}`,
+			`[{"jsonrpc":"2.0","method":"eth_chainId"},{"jsonrpc":"2.0","method":"eth_blockNumber"}]`,
+		} {
+			if code, rb := callTestJSONRPC(t, h, body); code != http.StatusNoContent || len(rb) > 0 {
+				t.Fatalf("expected no content for %s, but %d: %s", body, code, string(rb))
+			}
+		}
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		code, rb := callTestJSONRPC(t, h, `[
+			{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","method":"eth_blockNumber"},
+			{"jsonrpc":"2.0","id":2,"method":"eth_unknown"},
+			1,
+			{"jsonrpc":"2.0","id":3,"method":"eth_blockNumber"}
+		]`)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status, %d", code)
+		}
+
+		var ress []testJSONRPCResponse
+		if err := json.Unmarshal(rb, &ress); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ress) != 4 {
+			t.Fatalf("expected 4 responses, but %d: %s", len(ress), string(rb))
+		}
+
+		switch {
+		case string(ress[0].ID) != "1" || string(ress[0].Result) != `"0x4d2"`:
+			t.Fatalf("unexpected response, %s", string(rb))
+		case string(ress[1].ID) != "2" || ress[1].Error == nil || ress[1].Error.Code != digest.JSONRPCErrorMethodNotFound:
+			t.Fatalf("unexpected response, %s", string(rb))
+		case string(ress[2].ID) != "null" || ress[2].Error == nil || ress[2].Error.Code != digest.JSONRPCErrorInvalidRequest:
+			t.Fatalf("unexpected response, %s", string(rb))
+		case string(ress[3].ID) != "3" || string(ress[3].Result) != `"0x1"`:
+			t.Fatalf("unexpected response, %s", string(rb))
+		}
+
+		for _, body := range []string{`[]`, `[` + strings.Repeat(`{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},`, 100) + `1]`} {
+			res := callTestJSONRPCSingle(t, h, body)
+			if res.Error == nil || res.Error.Code != digest.JSONRPCErrorInvalidRequest {
+				t.Fatalf("expected invalid request, but %v", res)
+			}
+		}
+
+		res := callTestJSONRPCSingle(t, h, `[{"jsonrpc":"2.0",`)
+		if res.Error == nil || res.Error.Code != digest.JSONRPCErrorParse {
+			t.Fatalf("expected parse error, but %v", res)
+		}
+	})
+
+	t.Run("rate limit per batch call", func(t *testing.T) {
+		c, err := digest.NewRateLimitConfig(&digest.RateLimitYAML{
+			IP: &digest.RateLimitRuleSetYAML{Read: &digest.RateLimitRuleYAML{Limit: 0.001, Burst: 3}},
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		h := newTestJSONRPCHandler(t, b, digest.NewAPIRateLimiter(c))
+
+		code, rb := callTestJSONRPC(t, h, `[
+			{"jsonrpc":"2.0","id":1,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":2,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":3,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","id":4,"method":"eth_chainId"},
+			{"jsonrpc":"2.0","method":"eth_chainId"}
+		]`)
+		if code != http.StatusOK {
+			t.Fatalf("unexpected status, %d", code)
+		}
+
+		var ress []testJSONRPCResponse
+		if err := json.Unmarshal(rb, &ress); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(ress) != 4 {
+			t.Fatalf("expected 4 responses, but %d: %s", len(ress), string(rb))
+		}
+
+		for j := range ress[:3] {
+			if ress[j].Error != nil {
+				t.Fatalf("unexpected error, %v", ress[j].Error)
+			}
+		}
+
+		if ress[3].Error == nil || ress[3].Error.Code != digest.JSONRPCErrorLimitExceeded || string(ress[3].ID) != "4" {
+			t.Fatalf("expected limit exceeded, but %s", string(rb))
+		}
+
+		if code, _ := callTestJSONRPC(t, h, `{"jsonrpc":"2.0","id":1,"method":"eth_chainId"}`); code != http.StatusTooManyRequests {
+			t.Fatalf("expected too many requests, but %d", code)
+		}
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		// NOTE the error of closed database is not exposed.
+		if err := b.db.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		res := callTestJSONRPCSingle(t, h,
+			`{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["`+ethereumAddress(b.sender)+`"]}`)
+
+		switch {
+		case res.Error == nil:
+			t.Fatalf("expected error, but %s", string(res.Result))
+		case res.Error.Code != digest.JSONRPCErrorInternal:
+			t.Fatalf("expected internal error, but %v", res.Error)
+		case res.Error.Message != "internal error":
+			t.Fatalf("unexpected error message, %q", res.Error.Message)
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package digest
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+const multisigMaxBodySize = 1 << 20
+
+// handleMultisig accepts the partially signed operation; the signs are merged
+// with the signs of the same fact, which were posted before.
+func (hd *Handlers) handleMultisig(w http.ResponseWriter, r *http.Request) {
+	op, err := hd.loadMultisigOperation(r)
+	if err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	st, err := hd.addMultisig(op, false)
+	if err != nil {
+		HTTP2HandleError(w, err)
+
+		return
+	}
+
+	HTTP2WriteHal(hd.enc, w, hd.buildMultisigHal(st), http.StatusOK)
+}
+
+// handleMultisigByFact returns the status of the operation by fact hash; with
+// POST, the signs of the body operation are appended.
+func (hd *Handlers) handleMultisigByFact(w http.ResponseWriter, r *http.Request) {
+	h, err := parseHashFromPath(mux.Vars(r)["hash"])
+	if err != nil {
+		HTTP2ProblemWithError(w, errors.Wrap(err, "invalid fact hash for multisig"), http.StatusBadRequest)
+
+		return
+	}
+
+	var st MultisigStatus
+
+	switch r.Method {
+	case http.MethodPost:
+		op, err := hd.loadMultisigOperation(r)
+		if err != nil {
+			HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+			return
+		}
+
+		if !op.Fact().Hash().Equal(h) {
+			HTTP2ProblemWithError(w, errors.Errorf("fact hash not matched"), http.StatusBadRequest)
+
+			return
+		}
+
+		i, err := hd.addMultisig(op, true)
+		if err != nil {
+			HTTP2HandleError(w, err)
+
+			return
+		}
+
+		st = i
+	default:
+		i, found := hd.multisig.Get(h)
+		if !found {
+			HTTP2ProblemWithError(w, errors.Errorf("multisig operation not found"), http.StatusNotFound)
+
+			return
+		}
+
+		j, err := hd.multisigStatus(i)
+		if err != nil {
+			HTTP2HandleError(w, err)
+
+			return
+		}
+
+		st = j
+	}
+
+	HTTP2WriteHal(hd.enc, w, hd.buildMultisigHal(st), http.StatusOK)
+}
+
+func (hd *Handlers) loadMultisigOperation(r *http.Request) (base.Operation, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, multisigMaxBodySize))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	hinter, err := hd.enc.Decode(body)
+	if err != nil {
+		return nil, common.ErrDecodeJson.Wrap(err)
+	}
+
+	op, ok := hinter.(base.Operation)
+	if !ok {
+		return nil, errors.Errorf("expected Operation, not %T", hinter)
+	}
+
+	return op, nil
+}
+
+// addMultisig verifies the signs of operation by the keys of sender account
+// and merges them. When the accumulated weight reaches the threshold, the
+// operation is broadcasted.
+func (hd *Handlers) addMultisig(op base.Operation, mustExist bool) (MultisigStatus, error) {
+	sender, err := multisigSender(op)
+	if err != nil {
+		return MultisigStatus{}, ErrBadRequest.Wrap(err)
+	}
+
+	fact := op.Fact()
+
+	if err := fact.IsValid(hd.networkID); err != nil {
+		return MultisigStatus{}, ErrBadRequest.Wrap(err)
+	}
+
+	if len(op.Signs()) < 1 {
+		return MultisigStatus{}, ErrBadRequest.Errorf("empty signs")
+	}
+
+	keys, err := hd.multisigKeys(sender)
+	if err != nil {
+		return MultisigStatus{}, err
+	}
+
+	if err := checkMultisigSigns(hd.networkID, fact, op.Signs(), keys); err != nil {
+		return MultisigStatus{}, ErrBadRequest.Wrap(err)
+	}
+
+	var st MultisigStatus
+
+	i, err := hd.multisig.Set(fact.Hash(), func(i MultisigOperation, found bool) (MultisigOperation, error) {
+		switch {
+		case !found && mustExist:
+			return i, mitumutil.ErrNotFound.Errorf("multisig operation")
+		case !found:
+			nop, err := setOperationSigns(op, op.Signs())
+			if err != nil {
+				return i, err
+			}
+
+			i = MultisigOperation{CreatedAt: time.Now(), Operation: nop}
+		case !i.BroadcastedAt.IsZero():
+			return i, ErrBadRequest.Errorf("multisig operation already broadcasted")
+		case i.Broadcasting:
+			return i, ErrBadRequest.Errorf("multisig operation is being broadcasted")
+		default:
+			nop, err := setOperationSigns(i.Operation, mergeMultisigSigns(i.Operation.Signs(), op.Signs()))
+			if err != nil {
+				return i, err
+			}
+
+			i.Operation = nop
+		}
+
+		// NOTE the operation is marked under the lock of pool, so the other
+		// requests can not send it again.
+		st = hd.newMultisigStatus(i, sender, keys)
+		i.Broadcasting = st.IsPassed()
+
+		return i, nil
+	})
+	if err != nil {
+		return MultisigStatus{}, err
+	}
+
+	if !i.Broadcasting {
+		return st, nil
+	}
+
+	_, serr := hd.sendOperation(i.Operation)
+
+	i, err = hd.multisig.Set(fact.Hash(), func(i MultisigOperation, found bool) (MultisigOperation, error) {
+		if !found {
+			return i, mitumutil.ErrNotFound.Errorf("multisig operation")
+		}
+
+		i.Broadcasting = false
+
+		if serr == nil {
+			i.BroadcastedAt = time.Now()
+		}
+
+		return i, nil
+	})
+
+	switch {
+	case serr != nil:
+		return st, errors.WithMessage(serr, "broadcast multisig operation")
+	case err != nil:
+		return st, err
+	}
+
+	hd.Log().Stringer("fact", fact.Hash()).Msg("multisig operation broadcasted")
+
+	return hd.newMultisigStatus(i, sender, keys), nil
+}
+
+func (hd *Handlers) multisigStatus(i MultisigOperation) (MultisigStatus, error) {
+	sender, err := multisigSender(i.Operation)
+	if err != nil {
+		return MultisigStatus{}, err
+	}
+
+	keys, err := hd.multisigKeys(sender)
+	if err != nil {
+		return MultisigStatus{}, err
+	}
+
+	return hd.newMultisigStatus(i, sender, keys), nil
+}
+
+// multisigKeys returns the keys of account from digested accounts.
+func (hd *Handlers) multisigKeys(sender base.Address) (types.AccountKeys, error) {
+	switch va, found, err := hd.database.Account(sender); {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, mitumutil.ErrNotFound.Errorf("sender account, %v", sender)
+	default:
+		keys := va.Account().Keys()
+		if keys == nil || len(keys.Keys()) < 1 {
+			return nil, ErrBadRequest.Errorf("sender account has no keys, %v", sender)
+		}
+
+		return keys, nil
+	}
+}
+
+func (hd *Handlers) newMultisigStatus(
+	i MultisigOperation, sender base.Address, keys types.AccountKeys,
+) MultisigStatus {
+	st := MultisigStatus{
+		Operation: i.Operation,
+		Sender:    sender,
+		CreatedAt: i.CreatedAt,
+		ExpiresAt: hd.multisig.ExpiresAt(i),
+		Signers:   []MultisigSigner{},
+		Threshold: keys.Threshold(),
+	}
+
+	if !i.BroadcastedAt.IsZero() {
+		t := i.BroadcastedAt
+		st.BroadcastedAt = &t
+	}
+
+	signs := i.Operation.Signs()
+
+	for j := range signs {
+		ky, found := keys.Key(signs[j].Signer())
+		if !found {
+			continue
+		}
+
+		st.Signers = append(st.Signers, MultisigSigner{Signer: ky.Key(), Weight: ky.Weight()})
+		st.Weight += ky.Weight()
+	}
+
+	return st
+}
+
+func (hd *Handlers) buildMultisigHal(st MultisigStatus) Hal {
+	h, err := hd.combineURL(HandlerPathMultisigByFact, "hash", st.Operation.Fact().Hash().String())
+	if err != nil {
+		return NewBaseHal(st, HalLink{})
+	}
+
+	return NewBaseHal(st, NewHalLink(h, nil))
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/ProtoconNet/mitum2/util/valuehash"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +13,6 @@ import (
 	mitumutil "github.com/ProtoconNet/mitum2/util"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 func (hd *Handlers) handleOperation(w http.ResponseWriter, r *http.Request) {
@@ -70,13 +68,20 @@ func (hd *Handlers) handleOperations(w http.ResponseWriter, r *http.Request) {
 	offset := ParseStringQuery(r.URL.Query().Get("offset"))
 	reverse := ParseBoolQuery(r.URL.Query().Get("reverse"))
 
-	cachekey := CacheKey(r.URL.Path, StringOffsetQuery(offset), StringBoolQuery("reverse", reverse))
+	of, err := ParseOperationFilter(r.URL.Query(), hd.enc)
+	if err != nil {
+		HTTP2ProblemWithError(w, err, http.StatusBadRequest)
+
+		return
+	}
+
+	cachekey := CacheKey(r.URL.Path, StringOffsetQuery(offset), StringBoolQuery("reverse", reverse), of.Query())
 	if err := LoadFromCache(hd.cache, cachekey, w); err == nil {
 		return
 	}
 
 	if v, err, shared := hd.rg.Do(cachekey, func() (interface{}, error) {
-		i, filled, err := hd.handleOperationsInGroup(offset, reverse, limit)
+		i, filled, err := hd.handleOperationsInGroup(offset, reverse, limit, of)
 
 		return []interface{}{i, filled}, err
 	}); err != nil {
@@ -103,15 +108,20 @@ func (hd *Handlers) handleOperations(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (hd *Handlers) handleOperationsInGroup(offset string, reverse bool, l int64) ([]byte, bool, error) {
-	filter, err := buildOperationsFilterByOffset(offset, reverse)
-	if err != nil {
-		return nil, false, err
-	}
-
+func (hd *Handlers) handleOperationsInGroup(
+	offset string,
+	reverse bool,
+	l int64,
+	of OperationFilter,
+) ([]byte, bool, error) {
 	var vas []Hal
 	var opsCount int64
-	switch l, count, e := hd.loadOperationsHALFromDatabase(filter, reverse, l); {
+	switch l, count, e := hd.loadOperationsHALFromDatabase(l, func(
+		limit int64,
+		callback func(mitumutil.Hash, OperationValue, int64) (bool, error),
+	) error {
+		return hd.database.Operations(of, offset, reverse, true, limit, callback)
+	}); {
 	case e != nil:
 		return nil, false, e
 	case len(l) < 1:
@@ -125,6 +135,8 @@ func (hd *Handlers) handleOperationsInGroup(offset string, reverse bool, l int64
 	if err != nil {
 		return nil, false, err
 	}
+	h = AddQueryValue(h, of.Query())
+
 	hal := hd.buildOperationsHal(h, vas, offset, reverse)
 	if next := nextOffsetOfOperations(h, vas, reverse); len(next) > 0 {
 		hal = hal.AddLink("next", NewHalLink(next, nil))
@@ -191,14 +203,14 @@ func (hd *Handlers) handleOperationsByHeightInGroup(
 	reverse bool,
 	l int64,
 ) ([]byte, bool, error) {
-	filter, err := buildOperationsByHeightFilterByOffset(height, offset, reverse)
-	if err != nil {
-		return nil, false, err
-	}
-
 	var vas []Hal
 	var opsCount int64
-	switch l, count, e := hd.loadOperationsHALFromDatabase(filter, reverse, l); {
+	switch l, count, e := hd.loadOperationsHALFromDatabase(l, func(
+		limit int64,
+		callback func(mitumutil.Hash, OperationValue, int64) (bool, error),
+	) error {
+		return hd.database.OperationsByHeight(height, offset, reverse, true, limit, callback)
+	}); {
 	case e != nil:
 		return nil, false, e
 	case len(l) < 1:
@@ -251,14 +263,14 @@ func (hd *Handlers) handleOperationsByHash(w http.ResponseWriter, r *http.Reques
 func (hd *Handlers) handleOperationsByHashInGroup(
 	hashes string,
 ) ([]byte, error) {
-	filter, err := buildOperationsByHashesFilter(hashes)
+	hs, err := parseOperationHashes(hashes)
 	if err != nil {
 		return nil, err
 	}
 
 	var vas []Hal
 	var opsCount int64
-	switch l, count, e := hd.loadOperationsHALFromDatabaseByHash(filter); {
+	switch l, count, e := hd.loadOperationsHALFromDatabaseByHash(hs); {
 	case e != nil:
 		return nil, e
 	case len(l) < 1:
@@ -356,66 +368,9 @@ func (*Handlers) buildOperationsByHashHal(vas []Hal) Hal {
 	return hal
 }
 
-func buildOperationsFilterByOffset(offset string, reverse bool) (bson.M, error) {
-	filter := bson.M{}
-	if len(offset) > 0 {
-		height, index, err := parseOffset(offset)
-		if err != nil {
-			return nil, err
-		}
-
-		if reverse {
-			filter["$or"] = []bson.M{
-				{"height": bson.M{"$lt": height}},
-				{"$and": []bson.M{
-					{"height": height},
-					{"index": bson.M{"$lt": index}},
-				}},
-			}
-		} else {
-			filter["$or"] = []bson.M{
-				{"height": bson.M{"$gt": height}},
-				{"$and": []bson.M{
-					{"height": height},
-					{"index": bson.M{"$gt": index}},
-				}},
-			}
-		}
-	}
-
-	return filter, nil
-}
-
-func buildOperationsByHeightFilterByOffset(height base.Height, offset string, reverse bool) (bson.M, error) {
-	var filter bson.M
-	if len(offset) < 1 {
-		return bson.M{"height": height}, nil
-	}
-
-	index, err := strconv.ParseUint(offset, 10, 64)
-	if err != nil {
-		return nil, errors.Wrap(err, "invalid index of offset")
-	}
-
-	if reverse {
-		filter = bson.M{
-			"height": height,
-			"index":  bson.M{"$lt": index},
-		}
-	} else {
-		filter = bson.M{
-			"height": height,
-			"index":  bson.M{"$gt": index},
-		}
-	}
-
-	return filter, nil
-}
-
 const maxHashCount = 40
 
-func buildOperationsByHashesFilter(hashes string) (bson.M, error) {
-	var filter bson.M
+func parseOperationHashes(hashes string) ([]mitumutil.Hash, error) {
 	if len(hashes) < 1 {
 		return nil, errors.Errorf("empty hashes")
 	}
@@ -436,13 +391,7 @@ func buildOperationsByHashesFilter(hashes string) (bson.M, error) {
 		hashArr = append(hashArr, h)
 	}
 
-	filter = bson.M{
-		"fact": bson.M{
-			"$in": hashArr,
-		},
-	}
-
-	return filter, nil
+	return hashArr, nil
 }
 
 func nextOffsetOfOperations(baseSelf string, vas []Hal, reverse bool) string {
@@ -491,7 +440,10 @@ func nextOffsetOfOperationsByHeight(baseSelf string, vas []Hal, reverse bool) st
 	return next
 }
 
-func (hd *Handlers) loadOperationsHALFromDatabase(filter bson.M, reverse bool, l int64) ([]Hal, int64, error) {
+func (hd *Handlers) loadOperationsHALFromDatabase(
+	l int64,
+	load func(int64, func(mitumutil.Hash, OperationValue, int64) (bool, error)) error,
+) ([]Hal, int64, error) {
 	var limit int64
 	if l < 0 {
 		limit = hd.itemsLimiter("operations")
@@ -501,8 +453,8 @@ func (hd *Handlers) loadOperationsHALFromDatabase(filter bson.M, reverse bool, l
 
 	var vas []Hal
 	var opsCount int64
-	if err := hd.database.Operations(
-		filter, true, reverse, limit,
+	if err := load(
+		limit,
 		func(_ mitumutil.Hash, va OperationValue, count int64) (bool, error) {
 			hal, err := hd.buildOperationHal(va)
 			if err != nil {
@@ -521,11 +473,11 @@ func (hd *Handlers) loadOperationsHALFromDatabase(filter bson.M, reverse bool, l
 	return vas, opsCount, nil
 }
 
-func (hd *Handlers) loadOperationsHALFromDatabaseByHash(filter bson.M) ([]Hal, int64, error) {
+func (hd *Handlers) loadOperationsHALFromDatabaseByHash(hashes []mitumutil.Hash) ([]Hal, int64, error) {
 	var vas []Hal
 	var opsCount int64
 	if err := hd.database.OperationsByHash(
-		filter,
+		hashes,
 		func(_ mitumutil.Hash, va OperationValue, count int64) (bool, error) {
 			hal, err := hd.buildOperationHal(va)
 			if err != nil {
@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"net/http"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+)
+
+func (hd *Handlers) handleCurrencySupplyAudit(w http.ResponseWriter, r *http.Request) {
+	cid, err, status := ParseRequest(w, r, "currency_id")
+	if err != nil {
+		HTTP2ProblemWithError(w, err, status)
+
+		return
+	}
+
+	if v, err, _ := hd.rg.Do(CacheKeyPath(r), func() (interface{}, error) {
+		return hd.handleCurrencySupplyAuditInGroup(cid)
+	}); err != nil {
+		HTTP2HandleError(w, err)
+	} else {
+		HTTP2WriteHalBytes(hd.enc, w, v.([]byte), http.StatusOK)
+	}
+}
+
+// handleCurrencySupplyAuditInGroup reconciles the latest digested balances of
+// the accounts and the deposits of suffrage bonds with the total supply of
+// currency.
+func (hd *Handlers) handleCurrencySupplyAuditInGroup(cid string) ([]byte, error) {
+	de, _, err := hd.database.Currency(cid)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := currency.NewSupplyAudit()
+	audit.SetDesign(de)
+
+	if err := hd.database.CurrencySupplyBalances(cid, func(key string, am common.Big) (bool, error) {
+		audit.SetBalance(key, types.NewAmount(am, de.Currency()))
+
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var result currency.SupplyAuditResult
+	if results := audit.Results(); len(results) > 0 {
+		result = results[0]
+	}
+
+	self, err := hd.combineURL(HandlerPathCurrencySupplyAudit, "currency_id", cid)
+	if err != nil {
+		return nil, err
+	}
+
+	var hal Hal = NewBaseHal(result, NewHalLink(self, nil))
+
+	h, err := hd.combineURL(HandlerPathCurrencyHolders, "currency_id", cid)
+	if err != nil {
+		return nil, err
+	}
+
+	hal = hal.AddLink("holders", NewHalLink(h, nil)).
+		AddExtras("height", hd.database.LastBlock()).
+		AddExtras("balanced", result.IsBalanced())
+
+	return hd.enc.Marshal(hal)
+}
@@ -49,6 +49,15 @@ var balanceIndexModels = []mongo.IndexModel{
 		Options: options.Index().
 			SetName("mitum_digest_balance_currency"),
 	},
+	{
+		Keys: bson.D{
+			bson.E{Key: "currency", Value: 1},
+			bson.E{Key: "address", Value: 1},
+			bson.E{Key: "height", Value: -1},
+		},
+		Options: options.Index().
+			SetName("mitum_digest_balance_currency_holders"),
+	},
 	//{
 	//	Keys: bson.D{bson.E{Key: "height", Value: -1}},
 	//	Options: options.Index().
@@ -72,6 +81,38 @@ var operationIndexModels = []mongo.IndexModel{
 		Options: options.Index().
 			SetName("mitum_digest_operation_height"),
 	},
+	{
+		Keys: bson.D{bson.E{Key: "hint", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Options: options.Index().
+			SetName("mitum_digest_operation_hint"),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "currencies", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Options: options.Index().
+			SetName("mitum_digest_operation_currencies"),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "in_state", Value: 1}, bson.E{Key: "height", Value: 1}, bson.E{Key: "index", Value: 1}},
+		Options: options.Index().
+			SetName("mitum_digest_operation_in_state"),
+	},
+	{
+		Keys: bson.D{bson.E{Key: "confirmed_at", Value: 1}},
+		Options: options.Index().
+			SetName("mitum_digest_operation_confirmed_at"),
+	},
+}
+
+var suffrageBondIndexModels = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			bson.E{Key: "currency", Value: 1},
+			bson.E{Key: "node", Value: 1},
+			bson.E{Key: "height", Value: -1},
+		},
+		Options: options.Index().
+			SetName("mitum_digest_suffrage_bond_currency"),
+	},
 }
 
 var DefaultIndexes = map[string] /* collection */ []mongo.IndexModel{
@@ -0,0 +1,55 @@
+package digest
+
+import (
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/pkg/errors"
+)
+
+type JSONRPCYAML struct {
+	ChainID  uint64 `yaml:"chain_id"`
+	Currency string `yaml:"currency"`
+}
+
+// JSONRPCConfig is the config of ethereum JSON-RPC gateway; the balance of
+// eth_getBalance is the balance of the currency. Without config, the gateway
+// is disabled.
+type JSONRPCConfig struct {
+	currency types.CurrencyID
+	chainID  uint64
+}
+
+func NewJSONRPCConfig(y *JSONRPCYAML) (JSONRPCConfig, error) {
+	var c JSONRPCConfig
+	if y == nil {
+		return c, nil
+	}
+
+	switch {
+	case y.ChainID < 1:
+		return c, errors.Errorf("Empty chain id of json rpc")
+	case len(y.Currency) < 1:
+		return c, errors.Errorf("Empty currency of json rpc")
+	}
+
+	cid := types.CurrencyID(y.Currency)
+	if err := cid.IsValid(nil); err != nil {
+		return c, errors.WithMessage(err, "Invalid currency of json rpc")
+	}
+
+	c.chainID = y.ChainID
+	c.currency = cid
+
+	return c, nil
+}
+
+func (c JSONRPCConfig) IsEmpty() bool {
+	return c.chainID < 1
+}
+
+func (c JSONRPCConfig) ChainID() uint64 {
+	return c.chainID
+}
+
+func (c JSONRPCConfig) Currency() types.CurrencyID {
+	return c.currency
+}
@@ -285,6 +285,17 @@ func (st *Database) New() (*Database, error) {
 	}
 }
 
+// NewByName returns new Database, which shares the connection, but uses the
+// another database of the given name.
+func (st *Database) NewByName(name string) (*Database, error) {
+	client, err := st.client.New(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDatabase(client, st.encs, st.enc)
+}
+
 func (st *Database) SetInfo(key string, b []byte) error {
 	if st.readonly {
 		return errors.Errorf("readonly mode")
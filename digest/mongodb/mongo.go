@@ -323,6 +323,10 @@ func (cl *Client) DropDatabase() error {
 	return cl.db.Drop(ctx)
 }
 
+func (cl *Client) DatabaseName() string {
+	return cl.db.Name()
+}
+
 func (cl *Client) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), cl.execTimeout)
 	defer cancel()
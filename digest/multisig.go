@@ -0,0 +1,195 @@
+package digest
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var (
+	DefaultMultisigLifespan = time.Hour * 24
+	DefaultMultisigLimit    = 1 << 13
+)
+
+// MultisigPool keeps the partially signed operations by fact hash until the sum
+// of the weights of signers reaches the threshold of the sender account keys.
+// The operations are kept in memory, so they are lost when the node is
+// restarted.
+type MultisigPool struct {
+	ops      map[string]MultisigOperation
+	lifespan time.Duration
+	limit    int
+	sync.Mutex
+}
+
+// MultisigOperation is the operation collected in MultisigPool. Broadcasting is
+// set while the operation is being sent, so the signs are not changed and the
+// operation is not sent twice.
+type MultisigOperation struct {
+	CreatedAt     time.Time
+	BroadcastedAt time.Time
+	Operation     base.Operation
+	Broadcasting  bool
+}
+
+func NewMultisigPool(lifespan time.Duration, limit int) *MultisigPool {
+	return &MultisigPool{
+		ops:      map[string]MultisigOperation{},
+		lifespan: lifespan,
+		limit:    limit,
+	}
+}
+
+func (p *MultisigPool) Get(fact util.Hash) (MultisigOperation, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.clean()
+
+	i, found := p.ops[fact.String()]
+
+	return i, found
+}
+
+// Set updates the operation of fact hash by f atomically.
+func (p *MultisigPool) Set(
+	fact util.Hash,
+	f func(MultisigOperation, bool) (MultisigOperation, error),
+) (MultisigOperation, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.clean()
+
+	i, found := p.ops[fact.String()]
+	if !found && len(p.ops) >= p.limit {
+		return MultisigOperation{}, errors.Errorf("too many multisig operations, %d", len(p.ops))
+	}
+
+	j, err := f(i, found)
+	if err != nil {
+		return MultisigOperation{}, err
+	}
+
+	p.ops[fact.String()] = j
+
+	return j, nil
+}
+
+func (p *MultisigPool) ExpiresAt(i MultisigOperation) time.Time {
+	return i.CreatedAt.Add(p.lifespan)
+}
+
+func (p *MultisigPool) clean() {
+	now := time.Now()
+
+	for k := range p.ops {
+		if now.After(p.ExpiresAt(p.ops[k])) {
+			delete(p.ops, k)
+		}
+	}
+}
+
+// MultisigStatus shows the accumulated weight of the signers against the
+// threshold of the sender account keys.
+type MultisigStatus struct {
+	Operation     base.Operation   `json:"operation"`
+	Sender        base.Address     `json:"sender"`
+	CreatedAt     time.Time        `json:"created_at"`
+	ExpiresAt     time.Time        `json:"expires_at"`
+	BroadcastedAt *time.Time       `json:"broadcasted_at,omitempty"`
+	Signers       []MultisigSigner `json:"signers"`
+	Weight        uint             `json:"weight"`
+	Threshold     uint             `json:"threshold"`
+}
+
+type MultisigSigner struct {
+	Signer base.Publickey `json:"signer"`
+	Weight uint           `json:"weight"`
+}
+
+// IsPassed checks the accumulated weight reaches the threshold.
+func (st MultisigStatus) IsPassed() bool {
+	return st.Threshold > 0 && st.Weight >= st.Threshold
+}
+
+func multisigSender(op base.Operation) (base.Address, error) {
+	if _, ok := op.(base.NodeSigner); ok {
+		return nil, errors.Errorf("node operation not supported, %T", op)
+	}
+
+	i, ok := op.Fact().(interface{ Sender() base.Address })
+	if !ok {
+		return nil, errors.Errorf("fact without sender not supported, %T", op.Fact())
+	}
+
+	return i.Sender(), nil
+}
+
+// mergeMultisigSigns merges the signs of b into a; the sign of same signer is
+// replaced by the sign of b.
+func mergeMultisigSigns(a, b []base.Sign) []base.Sign {
+	signs := make([]base.Sign, len(a), len(a)+len(b))
+	copy(signs, a)
+
+next:
+	for i := range b {
+		for j := range signs {
+			if signs[j].Signer().Equal(b[i].Signer()) {
+				signs[j] = b[i]
+
+				continue next
+			}
+		}
+
+		signs = append(signs, b[i])
+	}
+
+	return signs
+}
+
+// setOperationSigns returns the copy of operation with the signs.
+func setOperationSigns(op base.Operation, signs []base.Sign) (base.Operation, error) {
+	type signsSetter interface {
+		SetSigns([]base.Sign) error
+	}
+
+	ptr := reflect.New(reflect.TypeOf(op))
+	ptr.Elem().Set(reflect.ValueOf(op))
+
+	i, ok := ptr.Interface().(signsSetter)
+	if !ok {
+		return nil, errors.Errorf("signs can not be set, %T", op)
+	}
+
+	if err := i.SetSigns(signs); err != nil {
+		return nil, err
+	}
+
+	return ptr.Elem().Interface().(base.Operation), nil //nolint:forcetypeassert //...
+}
+
+// checkMultisigSigns verifies the signs; the signers should be in the keys.
+func checkMultisigSigns(
+	networkID base.NetworkID, fact base.Fact, signs []base.Sign, keys types.AccountKeys,
+) error {
+	for i := range signs {
+		s := signs[i]
+
+		if _, found := keys.Key(s.Signer()); !found {
+			return errors.Errorf("unknown signer, %v", s.Signer())
+		}
+
+		if err := common.VerifySign(s, networkID, fact); err != nil {
+			return errors.WithMessagef(err, "signer, %v", s.Signer())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package digest
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var testMultisigNetworkID = base.NetworkID("multisig-test")
+
+func newTestMultisigOperation(t *testing.T, privs ...base.Privatekey) base.Operation {
+	t.Helper()
+
+	sender, err := types.NewAddressFromKeys(newTestMultisigKeys(t, types.NewMEPrivatekey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receiver, err := types.NewAddressFromKeys(newTestMultisigKeys(t, types.NewMEPrivatekey()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fact := currency.NewTransferFact(util.UUID().Bytes(), sender, []currency.TransferItem{
+		currency.NewTransferItemSingleAmount(receiver, types.NewAmount(common.NewBig(100), types.CurrencyID("MCC"))),
+	})
+
+	op, err := currency.NewTransfer(fact)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range privs {
+		if err := op.Sign(privs[i], testMultisigNetworkID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return op
+}
+
+func newTestMultisigKeys(t *testing.T, privs ...base.Privatekey) types.AccountKeys {
+	t.Helper()
+
+	ks := make([]types.AccountKey, len(privs))
+
+	for i := range privs {
+		k, err := types.NewBaseAccountKey(privs[i].Publickey(), uint(100/len(privs)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ks[i] = k
+	}
+
+	keys, err := types.NewBaseAccountKeys(ks, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keys
+}
+
+func TestMergeMultisigSigns(t *testing.T) {
+	apriv, bpriv, cpriv := types.NewMEPrivatekey(), types.NewMEPrivatekey(), types.NewMEPrivatekey()
+
+	op := newTestMultisigOperation(t, apriv, bpriv)
+	a, b := op.Signs()[0], op.Signs()[1]
+
+	newSign := func(priv base.Privatekey) base.Sign {
+		s, err := base.NewBaseSignFromFact(priv, testMultisigNetworkID, op.Fact())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return s
+	}
+
+	na, c := newSign(apriv), newSign(cpriv)
+
+	checkSigners := func(t *testing.T, signs []base.Sign, expected ...base.Sign) {
+		t.Helper()
+
+		if len(signs) != len(expected) {
+			t.Fatalf("expected %d signs, but %d", len(expected), len(signs))
+		}
+
+		for i := range expected {
+			if !signs[i].Signer().Equal(expected[i].Signer()) ||
+				!bytes.Equal(signs[i].Signature(), expected[i].Signature()) {
+				t.Fatalf("sign %d not matched, %v", i, signs[i].Signer())
+			}
+		}
+	}
+
+	t.Run("new signer", func(t *testing.T) {
+		checkSigners(t, mergeMultisigSigns([]base.Sign{a, b}, []base.Sign{c}), a, b, c)
+	})
+
+	t.Run("same signer replaced", func(t *testing.T) {
+		checkSigners(t, mergeMultisigSigns([]base.Sign{a, b}, []base.Sign{na, c}), na, b, c)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		checkSigners(t, mergeMultisigSigns(nil, []base.Sign{a}), a)
+		checkSigners(t, mergeMultisigSigns([]base.Sign{a}, nil), a)
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		signs := []base.Sign{a, b}
+
+		_ = mergeMultisigSigns(signs, []base.Sign{na})
+
+		checkSigners(t, signs, a, b)
+	})
+}
+
+func TestSetOperationSigns(t *testing.T) {
+	apriv, bpriv := types.NewMEPrivatekey(), types.NewMEPrivatekey()
+
+	op := newTestMultisigOperation(t, apriv)
+	other := newTestMultisigOperation(t, bpriv)
+
+	signs := mergeMultisigSigns(op.Signs(), []base.Sign{other.Signs()[0]})
+
+	nop, err := setOperationSigns(op, signs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := nop.(currency.Transfer); !ok {
+		t.Fatalf("expected Transfer, but %T", nop)
+	}
+
+	if len(nop.Signs()) != 2 {
+		t.Fatalf("expected 2 signs, but %d", len(nop.Signs()))
+	}
+
+	if len(op.Signs()) != 1 {
+		t.Fatalf("original operation modified, %d signs", len(op.Signs()))
+	}
+
+	if nop.Hash().Equal(op.Hash()) {
+		t.Fatal("hash not updated")
+	}
+
+	// NOTE the sign of other fact can not be verified, but the signers of
+	// multisig are checked by checkMultisigSigns before.
+	if err := nop.IsValid(testMultisigNetworkID); err == nil {
+		t.Fatal("expected error for sign of other fact")
+	}
+
+	if _, err := setOperationSigns(op, []base.Sign{nil}); err == nil {
+		t.Fatal("expected error for empty sign")
+	}
+}
+
+func TestCheckMultisigSigns(t *testing.T) {
+	apriv, bpriv := types.NewMEPrivatekey(), types.NewMEPrivatekey()
+	keys := newTestMultisigKeys(t, apriv, bpriv)
+
+	op := newTestMultisigOperation(t, apriv, bpriv)
+
+	if err := checkMultisigSigns(testMultisigNetworkID, op.Fact(), op.Signs(), keys); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkMultisigSigns(
+		base.NetworkID("showme"), op.Fact(), op.Signs(), keys); err == nil {
+		t.Fatal("expected error for wrong network id")
+	}
+
+	unknown := newTestMultisigOperation(t, types.NewMEPrivatekey())
+
+	if err := checkMultisigSigns(
+		testMultisigNetworkID, unknown.Fact(), unknown.Signs(), keys); err == nil {
+		t.Fatal("expected error for unknown signer")
+	}
+}
+
+func TestMultisigPool(t *testing.T) {
+	newItem := func(createdAt time.Time) func(MultisigOperation, bool) (MultisigOperation, error) {
+		return func(MultisigOperation, bool) (MultisigOperation, error) {
+			return MultisigOperation{CreatedAt: createdAt, Operation: newTestMultisigOperation(t)}, nil
+		}
+	}
+
+	t.Run("expire", func(t *testing.T) {
+		p := NewMultisigPool(time.Minute, 10)
+
+		old, fresh := util.UUID().Bytes(), util.UUID().Bytes()
+
+		if _, err := p.Set(common.NewHashFromBytes(old), newItem(time.Now().Add(-time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+
+		i, err := p.Set(common.NewHashFromBytes(fresh), newItem(time.Now()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if d := p.ExpiresAt(i).Sub(i.CreatedAt); d != time.Minute {
+			t.Fatalf("unexpected lifespan, %v", d)
+		}
+
+		if _, found := p.Get(common.NewHashFromBytes(old)); found {
+			t.Fatal("expired operation found")
+		}
+
+		if _, found := p.Get(common.NewHashFromBytes(fresh)); !found {
+			t.Fatal("operation not found")
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		p := NewMultisigPool(time.Minute, 2)
+
+		hs := make([]util.Hash, 3)
+		for i := range hs {
+			hs[i] = common.NewHashFromBytes(util.UUID().Bytes())
+		}
+
+		for _, h := range hs[:2] {
+			if _, err := p.Set(h, newItem(time.Now())); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if _, err := p.Set(hs[2], newItem(time.Now())); err == nil {
+			t.Fatal("expected error for limit")
+		}
+
+		// NOTE the existing operation can be updated over limit.
+		if _, err := p.Set(hs[0], newItem(time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("limit after expire", func(t *testing.T) {
+		p := NewMultisigPool(time.Minute, 1)
+
+		old, fresh := common.NewHashFromBytes(util.UUID().Bytes()), common.NewHashFromBytes(util.UUID().Bytes())
+
+		if _, err := p.Set(old, newItem(time.Now().Add(-time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := p.Set(fresh, newItem(time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("set error", func(t *testing.T) {
+		p := NewMultisigPool(time.Minute, 10)
+		h := common.NewHashFromBytes(util.UUID().Bytes())
+
+		if _, err := p.Set(h, func(MultisigOperation, bool) (MultisigOperation, error) {
+			return MultisigOperation{CreatedAt: time.Now()}, errors.Errorf("hehehe")
+		}); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if _, found := p.Get(h); found {
+			t.Fatal("operation of failed set found")
+		}
+	})
+}
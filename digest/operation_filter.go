@@ -0,0 +1,348 @@
+package digest
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/operation/extension"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// OperationFilter filters the operations by the queries of request.
+// * hint: hint type of operation, like `mitum-currency-transfer-operation`;
+// comma separated.
+// * in_state: `1` for the operations processed successfully, `0` for failed.
+// * currency: currency id, which is used in operation; comma separated. The
+// operation matches if it uses any of the given currencies.
+// * counterparty: address, which is related with operation; comma separated.
+// The operation matches only if it is related with all of the given addresses.
+// * from_height, to_height: height range.
+// * from_date, to_date: range of confirmed time in RFC3339.
+//
+// The hint, in_state, currency and date filters need the fields of operation
+// document, which are added by this version of digest; the digest of older
+// version should be rebuilt by `storage rebuild-digest`. The zero value is the
+// empty filter.
+type OperationFilter struct {
+	fromDate     time.Time
+	toDate       time.Time
+	inState      *bool
+	hints        []string
+	currencies   []string
+	counterparty []string
+	fromHeight   *base.Height
+	toHeight     *base.Height
+}
+
+func ParseOperationFilter(q url.Values, enc encoder.Encoder) (OperationFilter, error) {
+	var f OperationFilter
+
+	f.hints = parseCommaQuery(q.Get("hint"))
+	f.currencies = parseCommaQuery(q.Get("currency"))
+
+	for i := range f.currencies {
+		if err := types.CurrencyID(f.currencies[i]).IsValid(nil); err != nil {
+			return f, errors.WithMessage(err, "invalid currency query")
+		}
+	}
+
+	if l := parseCommaQuery(q.Get("counterparty")); len(l) > 0 {
+		founds := map[string]struct{}{}
+
+		for i := range l {
+			a, err := base.DecodeAddress(l[i], enc)
+
+			switch {
+			case err != nil:
+				return f, errors.WithMessage(err, "invalid counterparty query")
+			case a == nil:
+				return f, errors.Errorf("Invalid counterparty query, empty address")
+			}
+
+			if err := a.IsValid(nil); err != nil {
+				return f, errors.WithMessage(err, "invalid counterparty query")
+			}
+
+			if _, found := founds[a.String()]; found {
+				continue
+			}
+
+			founds[a.String()] = struct{}{}
+			f.counterparty = append(f.counterparty, a.String())
+		}
+
+		sort.Strings(f.counterparty)
+	}
+
+	switch s := ParseStringQuery(q.Get("in_state")); s {
+	case "":
+	case "1", "true":
+		b := true
+		f.inState = &b
+	case "0", "false":
+		b := false
+		f.inState = &b
+	default:
+		return f, errors.Errorf("Invalid in_state query, %q", s)
+	}
+
+	for _, i := range []struct {
+		h    **base.Height
+		name string
+	}{
+		{h: &f.fromHeight, name: "from_height"},
+		{h: &f.toHeight, name: "to_height"},
+	} {
+		if s := ParseStringQuery(q.Get(i.name)); len(s) > 0 {
+			h, err := base.ParseHeightString(s)
+			if err != nil {
+				return f, errors.WithMessagef(err, "invalid %s query", i.name)
+			}
+
+			*i.h = &h
+		}
+	}
+
+	for _, i := range []struct {
+		t    *time.Time
+		name string
+	}{
+		{t: &f.fromDate, name: "from_date"},
+		{t: &f.toDate, name: "to_date"},
+	} {
+		if s := ParseStringQuery(q.Get(i.name)); len(s) > 0 {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return f, errors.WithMessagef(err, "invalid %s query", i.name)
+			}
+
+			*i.t = t
+		}
+	}
+
+	switch {
+	case f.fromHeight != nil && f.toHeight != nil && *f.fromHeight > *f.toHeight:
+		return f, errors.Errorf("Invalid height range, from %v > to %v", *f.fromHeight, *f.toHeight)
+	case !f.fromDate.IsZero() && !f.toDate.IsZero() && f.fromDate.After(f.toDate):
+		return f, errors.Errorf("Invalid date range, from %v > to %v", f.fromDate, f.toDate)
+	}
+
+	return f, nil
+}
+
+// needsDigestedFields returns true if the filter uses the fields of operation
+// document, which are missing in the digest of older version.
+func (f OperationFilter) needsDigestedFields() bool {
+	return len(f.hints) > 0 || f.inState != nil || len(f.currencies) > 0 ||
+		!f.fromDate.IsZero() || !f.toDate.IsZero()
+}
+
+func (f OperationFilter) IsEmpty() bool {
+	return len(f.hints) < 1 && f.inState == nil && len(f.currencies) < 1 && len(f.counterparty) < 1 &&
+		f.fromHeight == nil && f.toHeight == nil && f.fromDate.IsZero() && f.toDate.IsZero()
+}
+
+// BSON returns the mongodb filter of operations.
+func (f OperationFilter) BSON() bson.M {
+	filter := bson.M{}
+
+	if len(f.hints) > 0 {
+		filter["hint"] = bson.M{"$in": f.hints}
+	}
+
+	if f.inState != nil {
+		filter["in_state"] = *f.inState
+	}
+
+	if len(f.currencies) > 0 {
+		filter["currencies"] = bson.M{"$in": f.currencies}
+	}
+
+	if len(f.counterparty) > 0 {
+		filter["addresses"] = bson.M{"$all": f.counterparty}
+	}
+
+	hf := bson.M{}
+	if f.fromHeight != nil {
+		hf["$gte"] = *f.fromHeight
+	}
+
+	if f.toHeight != nil {
+		hf["$lte"] = *f.toHeight
+	}
+
+	if len(hf) > 0 {
+		filter["height"] = hf
+	}
+
+	df := bson.M{}
+	if !f.fromDate.IsZero() {
+		df["$gte"] = f.fromDate
+	}
+
+	if !f.toDate.IsZero() {
+		df["$lte"] = f.toDate
+	}
+
+	if len(df) > 0 {
+		filter["confirmed_at"] = df
+	}
+
+	return filter
+}
+
+// Merge combines the given filter with the operation filter.
+func (f OperationFilter) Merge(filter bson.M) bson.M {
+	if f.IsEmpty() {
+		return filter
+	}
+
+	if len(filter) < 1 {
+		return f.BSON()
+	}
+
+	return bson.M{"$and": []bson.M{filter, f.BSON()}}
+}
+
+// Query returns the url query string of filter for HAL links.
+func (f OperationFilter) Query() string {
+	q := url.Values{}
+
+	if len(f.hints) > 0 {
+		q.Set("hint", strings.Join(f.hints, ","))
+	}
+
+	if f.inState != nil {
+		if *f.inState {
+			q.Set("in_state", "1")
+		} else {
+			q.Set("in_state", "0")
+		}
+	}
+
+	if len(f.currencies) > 0 {
+		q.Set("currency", strings.Join(f.currencies, ","))
+	}
+
+	if len(f.counterparty) > 0 {
+		q.Set("counterparty", strings.Join(f.counterparty, ","))
+	}
+
+	if f.fromHeight != nil {
+		q.Set("from_height", f.fromHeight.String())
+	}
+
+	if f.toHeight != nil {
+		q.Set("to_height", f.toHeight.String())
+	}
+
+	if !f.fromDate.IsZero() {
+		q.Set("from_date", f.fromDate.Format(time.RFC3339Nano))
+	}
+
+	if !f.toDate.IsZero() {
+		q.Set("to_date", f.toDate.Format(time.RFC3339Nano))
+	}
+
+	return q.Encode()
+}
+
+func parseCommaQuery(s string) []string {
+	s = strings.TrimSpace(s)
+	if len(s) < 1 {
+		return nil
+	}
+
+	var l []string
+	founds := map[string]struct{}{}
+
+	for _, i := range strings.Split(s, ",") {
+		i = strings.TrimSpace(i)
+		if len(i) < 1 {
+			continue
+		}
+
+		if _, found := founds[i]; found {
+			continue
+		}
+
+		founds[i] = struct{}{}
+		l = append(l, i)
+	}
+
+	sort.Strings(l)
+
+	return l
+}
+
+// operationCurrencies returns the currency ids, which are used in operation.
+func operationCurrencies(op base.Operation) []string {
+	var cids []types.CurrencyID
+
+	addAmounts := func(ams []types.Amount) {
+		for i := range ams {
+			cids = append(cids, ams[i].Currency())
+		}
+	}
+
+	switch t := op.Fact().(type) {
+	case currency.CreateAccountFact:
+		for _, i := range t.Items() {
+			addAmounts(i.Amounts())
+		}
+	case currency.TransferFact:
+		for _, i := range t.Items() {
+			addAmounts(i.Amounts())
+		}
+	case currency.MintFact:
+		for _, i := range t.Items() {
+			cids = append(cids, i.Amount().Currency())
+		}
+	case currency.UpdateKeyFact:
+		cids = append(cids, t.Currency())
+	case currency.RegisterCurrencyFact:
+		cids = append(cids, t.Currency().Currency())
+	case currency.UpdateCurrencyFact:
+		cids = append(cids, t.Currency())
+	case currency.RegisterGenesisCurrencyFact:
+		for _, i := range t.Currencies() {
+			cids = append(cids, i.Currency())
+		}
+	case extension.CreateContractAccountFact:
+		for _, i := range t.Items() {
+			addAmounts(i.Amounts())
+		}
+	case extension.WithdrawFact:
+		for _, i := range t.Items() {
+			addAmounts(i.Amounts())
+		}
+	case extension.UpdateHandlerFact:
+		cids = append(cids, t.Currency())
+	}
+
+	if len(cids) < 1 {
+		return nil
+	}
+
+	founds := map[string]struct{}{}
+	l := make([]string, 0, len(cids))
+
+	for i := range cids {
+		s := cids[i].String()
+		if _, found := founds[s]; found {
+			continue
+		}
+
+		founds[s] = struct{}{}
+		l = append(l, s)
+	}
+
+	return l
+}
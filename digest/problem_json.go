@@ -0,0 +1,21 @@
+package digest
+
+import (
+	"github.com/ProtoconNet/mitum2/util"
+)
+
+func (pr Problem) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{}
+	for k, v := range pr.extra {
+		m[k] = v
+	}
+
+	m["type"] = makeProblemNamespace(pr.t)
+	m["title"] = pr.title
+
+	if len(pr.detail) > 0 {
+		m["detail"] = pr.detail
+	}
+
+	return util.MarshalJSON(m)
+}
@@ -0,0 +1,497 @@
+package digest
+
+import (
+	"crypto/subtle"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+var (
+	DefaultAPIKeyHeader          = "X-Api-Key"
+	DefaultRateLimitBucketExpire = time.Minute * 3
+)
+
+const (
+	ProblemTypeTooManyRequests = "too-many-requests"
+	ProblemTypeUnauthorized    = "unauthorized"
+	ProblemTypeForbidden       = "forbidden"
+)
+
+type RateLimitKind string
+
+const (
+	RateLimitKindRead RateLimitKind = "read"
+	RateLimitKindSend RateLimitKind = "send"
+)
+
+// RateLimitKindFromPath returns RateLimitKindSend for the operation sending
+// paths, `/builder/send*`; the others are regarded as read.
+func RateLimitKindFromPath(prefix string) RateLimitKind {
+	if strings.HasPrefix(prefix, HandlerPathSend) {
+		return RateLimitKindSend
+	}
+
+	return RateLimitKindRead
+}
+
+// RateLimitKindFromRequest is RateLimitKindFromPath with the request method;
+// POST to `/multisig*` is send, but the status polling by GET is read.
+func RateLimitKindFromRequest(prefix string, r *http.Request) RateLimitKind {
+	if r.Method == http.MethodPost && strings.HasPrefix(prefix, HandlerPathMultisig) {
+		return RateLimitKindSend
+	}
+
+	return RateLimitKindFromPath(prefix)
+}
+
+// adminPaths are the paths for the node operators; they always require the
+// valid api key of `auth`, even if `auth.required` is false.
+var adminPaths = map[string]struct{}{
+	HandlerPathMetrics:             {},
+	HandlerPathDigestGaps:          {},
+	HandlerPathCurrencySupplyAudit: {},
+}
+
+func isAdminPath(prefix string) bool {
+	_, found := adminPaths[prefix]
+
+	return found
+}
+
+type RateLimitRuleYAML struct {
+	Limit float64 `yaml:"limit"`
+	Burst int     `yaml:"burst"`
+}
+
+type RateLimitRuleSetYAML struct {
+	Read *RateLimitRuleYAML `yaml:"read,omitempty"`
+	Send *RateLimitRuleYAML `yaml:"send,omitempty"`
+}
+
+type RateLimitYAML struct {
+	IP         *RateLimitRuleSetYAML `yaml:"ip,omitempty"`
+	APIKey     *RateLimitRuleSetYAML `yaml:"api_key,omitempty"`
+	Expire     *string               `yaml:"expire,omitempty"`
+	TrustProxy bool                  `yaml:"trust_proxy,omitempty"`
+}
+
+type APIKeyAuthYAML struct {
+	Header   string   `yaml:"header,omitempty"`
+	Required bool     `yaml:"required,omitempty"`
+	Keys     []string `yaml:"keys,omitempty"`
+}
+
+type RateLimitRule struct {
+	limit rate.Limit
+	burst int
+}
+
+func NewRateLimitRule(limit float64, burst int) (RateLimitRule, error) {
+	switch {
+	case limit < 0:
+		return RateLimitRule{}, errors.Errorf("negative rate limit, %v", limit)
+	case burst < 0:
+		return RateLimitRule{}, errors.Errorf("negative rate limit burst, %d", burst)
+	case limit > 0 && burst < 1:
+		burst = int(math.Ceil(limit))
+	}
+
+	return RateLimitRule{limit: rate.Limit(limit), burst: burst}, nil
+}
+
+// IsEmpty returns true when the rule does not limit anything.
+func (r RateLimitRule) IsEmpty() bool {
+	return r.limit <= 0
+}
+
+type RateLimitRuleSet struct {
+	Read RateLimitRule
+	Send RateLimitRule
+}
+
+func (rs RateLimitRuleSet) Rule(kind RateLimitKind) RateLimitRule {
+	if kind == RateLimitKindSend {
+		return rs.Send
+	}
+
+	return rs.Read
+}
+
+func (rs RateLimitRuleSet) IsEmpty() bool {
+	return rs.Read.IsEmpty() && rs.Send.IsEmpty()
+}
+
+func newRateLimitRuleSet(y *RateLimitRuleSetYAML) (RateLimitRuleSet, error) {
+	var rs RateLimitRuleSet
+	if y == nil {
+		return rs, nil
+	}
+
+	if y.Read != nil {
+		r, err := NewRateLimitRule(y.Read.Limit, y.Read.Burst)
+		if err != nil {
+			return rs, errors.WithMessage(err, "read")
+		}
+		rs.Read = r
+	}
+
+	if y.Send != nil {
+		r, err := NewRateLimitRule(y.Send.Limit, y.Send.Burst)
+		if err != nil {
+			return rs, errors.WithMessage(err, "send")
+		}
+		rs.Send = r
+	}
+
+	return rs, nil
+}
+
+type RateLimitConfig struct {
+	IP         RateLimitRuleSet
+	APIKey     RateLimitRuleSet
+	Expire     time.Duration
+	TrustProxy bool
+	APIKeys    map[string]struct{}
+	KeyHeader  string
+	KeyRequire bool
+}
+
+func NewRateLimitConfig(ry *RateLimitYAML, ay *APIKeyAuthYAML) (RateLimitConfig, error) {
+	c := RateLimitConfig{
+		Expire:    DefaultRateLimitBucketExpire,
+		KeyHeader: DefaultAPIKeyHeader,
+	}
+
+	if ry != nil {
+		i, err := newRateLimitRuleSet(ry.IP)
+		if err != nil {
+			return c, errors.WithMessage(err, "ip rate limit")
+		}
+		c.IP = i
+
+		j, err := newRateLimitRuleSet(ry.APIKey)
+		if err != nil {
+			return c, errors.WithMessage(err, "api key rate limit")
+		}
+		c.APIKey = j
+
+		if ry.Expire != nil {
+			d, err := time.ParseDuration(*ry.Expire)
+			if err != nil {
+				return c, errors.WithMessage(err, "rate limit expire")
+			} else if d <= 0 {
+				return c, errors.Errorf("Invalid rate limit expire, %v", d)
+			}
+			c.Expire = d
+		}
+
+		c.TrustProxy = ry.TrustProxy
+	}
+
+	if ay != nil {
+		if s := strings.TrimSpace(ay.Header); len(s) > 0 {
+			c.KeyHeader = http.CanonicalHeaderKey(s)
+		}
+
+		c.APIKeys = map[string]struct{}{}
+		for i := range ay.Keys {
+			k := strings.TrimSpace(ay.Keys[i])
+			if len(k) < 1 {
+				return c, errors.Errorf("Empty api key")
+			} else if _, found := c.APIKeys[k]; found {
+				return c, errors.Errorf("Duplicated api key")
+			}
+			c.APIKeys[k] = struct{}{}
+		}
+
+		if ay.Required && len(c.APIKeys) < 1 {
+			return c, errors.Errorf("Api key required, but no keys given")
+		}
+
+		c.KeyRequire = ay.Required
+	}
+
+	return c, nil
+}
+
+// IsEmpty returns true when neither rate limit nor api key authentication is
+// configured.
+func (c RateLimitConfig) IsEmpty() bool {
+	return c.IP.IsEmpty() && c.APIKey.IsEmpty() && len(c.APIKeys) < 1
+}
+
+type rateLimitBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimitBuckets struct {
+	sync.Mutex
+	m         map[string]*rateLimitBucket
+	expire    time.Duration
+	lastSweep time.Time
+}
+
+func newRateLimitBuckets(expire time.Duration) *rateLimitBuckets {
+	return &rateLimitBuckets{
+		m:         map[string]*rateLimitBucket{},
+		expire:    expire,
+		lastSweep: time.Now(),
+	}
+}
+
+// reserve takes one token from the bucket of the key. If the token is not
+// available now, it returns the duration to wait.
+func (bs *rateLimitBuckets) reserve(key string, rule RateLimitRule, now time.Time) (bool, time.Duration) {
+	bs.Lock()
+	defer bs.Unlock()
+
+	if now.Sub(bs.lastSweep) > bs.expire {
+		for k := range bs.m {
+			if now.Sub(bs.m[k].lastSeen) > bs.expire {
+				delete(bs.m, k)
+			}
+		}
+
+		bs.lastSweep = now
+	}
+
+	b, found := bs.m[key]
+	if !found {
+		b = &rateLimitBucket{limiter: rate.NewLimiter(rule.limit, rule.burst)}
+		bs.m[key] = b
+	}
+
+	b.lastSeen = now
+
+	r := b.limiter.ReserveN(now, 1)
+	if !r.OK() {
+		return false, bs.expire
+	}
+
+	if d := r.DelayFrom(now); d > 0 {
+		r.CancelAt(now)
+
+		return false, d
+	}
+
+	return true, 0
+}
+
+func (bs *rateLimitBuckets) len() int {
+	bs.Lock()
+	defer bs.Unlock()
+
+	return len(bs.m)
+}
+
+// APIRateLimiter limits requests by token buckets per client ip and per api
+// key. The requests with valid api key are limited by the api key buckets
+// instead of the ip buckets.
+type APIRateLimiter struct {
+	config  RateLimitConfig
+	ips     map[RateLimitKind]*rateLimitBuckets
+	apikeys map[RateLimitKind]*rateLimitBuckets
+}
+
+func NewAPIRateLimiter(config RateLimitConfig) *APIRateLimiter {
+	rl := &APIRateLimiter{
+		config:  config,
+		ips:     map[RateLimitKind]*rateLimitBuckets{},
+		apikeys: map[RateLimitKind]*rateLimitBuckets{},
+	}
+
+	for _, kind := range []RateLimitKind{RateLimitKindRead, RateLimitKindSend} {
+		rl.ips[kind] = newRateLimitBuckets(config.Expire)
+		rl.apikeys[kind] = newRateLimitBuckets(config.Expire)
+	}
+
+	return rl
+}
+
+func (rl *APIRateLimiter) KeyHeader() string {
+	return rl.config.KeyHeader
+}
+
+// Buckets returns the number of active buckets.
+func (rl *APIRateLimiter) Buckets() int {
+	var n int
+	for kind := range rl.ips {
+		n += rl.ips[kind].len() + rl.apikeys[kind].len()
+	}
+
+	return n
+}
+
+func (rl *APIRateLimiter) Middleware(kind RateLimitKind) func(http.Handler) http.Handler {
+	return rl.MiddlewareByRequest(func(*http.Request) RateLimitKind { return kind })
+}
+
+// MiddlewareByRequest is Middleware with the kind decided by request.
+func (rl *APIRateLimiter) MiddlewareByRequest(
+	kindf func(*http.Request) RateLimitKind,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			allowed, wait, err := rl.Allow(r, kindf(r))
+			if err != nil {
+				HTTP2WriteProblemDetail(w,
+					NewProblem(ProblemTypeUnauthorized, http.StatusText(http.StatusUnauthorized)).
+						SetDetail(err.Error()),
+					http.StatusUnauthorized,
+				)
+
+				return
+			}
+
+			if !allowed {
+				retry := int64(math.Ceil(wait.Seconds()))
+				if retry < 1 {
+					retry = 1
+				}
+
+				w.Header().Set("Retry-After", strconv.FormatInt(retry, 10))
+				HTTP2WriteProblemDetail(w,
+					NewProblem(ProblemTypeTooManyRequests, http.StatusText(http.StatusTooManyRequests)).
+						SetDetail("rate limit exceeded").
+						SetExtra("retry_after", retry),
+					http.StatusTooManyRequests,
+				)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Allow takes one token from the bucket of request by the api key or by the
+// client ip. If not allowed, it returns the duration to wait. Besides
+// Middleware, it charges the request, which contains the multiple calls, like
+// the batch of JSON-RPC.
+func (rl *APIRateLimiter) Allow(r *http.Request, kind RateLimitKind) (bool, time.Duration, error) {
+	key, err := rl.apiKey(r)
+	if err != nil {
+		return false, 0, err
+	}
+
+	now := time.Now()
+
+	if len(key) > 0 {
+		rule := rl.config.APIKey.Rule(kind)
+		if rule.IsEmpty() {
+			return true, 0, nil
+		}
+
+		allowed, wait := rl.apikeys[kind].reserve(key, rule, now)
+
+		return allowed, wait, nil
+	}
+
+	rule := rl.config.IP.Rule(kind)
+	if rule.IsEmpty() {
+		return true, 0, nil
+	}
+
+	allowed, wait := rl.ips[kind].reserve(rl.clientIP(r), rule, now)
+
+	return allowed, wait, nil
+}
+
+// RequireAPIKey allows only the requests with the valid api key. Without api
+// keys in config, every request is forbidden; rl can be nil.
+func (rl *APIRateLimiter) RequireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if rl == nil || len(rl.config.APIKeys) < 1 {
+			HTTP2WriteProblemDetail(w,
+				NewProblem(ProblemTypeForbidden, http.StatusText(http.StatusForbidden)).
+					SetDetail("api key not configured"),
+				http.StatusForbidden,
+			)
+
+			return
+		}
+
+		key, err := rl.apiKey(r)
+		if err == nil && len(key) < 1 {
+			err = errors.Errorf("api key required in header, %q", rl.config.KeyHeader)
+		}
+
+		if err != nil {
+			HTTP2WriteProblemDetail(w,
+				NewProblem(ProblemTypeUnauthorized, http.StatusText(http.StatusUnauthorized)).
+					SetDetail(err.Error()),
+				http.StatusUnauthorized,
+			)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiKey returns the valid api key of request. Empty string is returned when
+// api key is not given and not required.
+func (rl *APIRateLimiter) apiKey(r *http.Request) (string, error) {
+	if len(rl.config.APIKeys) < 1 {
+		return "", nil
+	}
+
+	k := strings.TrimSpace(r.Header.Get(rl.config.KeyHeader))
+	if len(k) < 1 {
+		if rl.config.KeyRequire {
+			return "", errors.Errorf("api key required in header, %q", rl.config.KeyHeader)
+		}
+
+		return "", nil
+	}
+
+	for i := range rl.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(i), []byte(k)) == 1 {
+			return i, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown api key")
+}
+
+func (rl *APIRateLimiter) clientIP(r *http.Request) string {
+	if rl.config.TrustProxy {
+		if s := strings.TrimSpace(r.Header.Get("X-Forwarded-For")); len(s) > 0 {
+			return strings.TrimSpace(strings.Split(s, ",")[0])
+		}
+
+		if s := strings.TrimSpace(r.Header.Get("X-Real-Ip")); len(s) > 0 {
+			return s
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
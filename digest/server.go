@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/network"
 	"github.com/ProtoconNet/mitum-currency/v3/digest/util"
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	"github.com/ProtoconNet/mitum2/base"
 	isaacnetwork "github.com/ProtoconNet/mitum2/isaac/network"
 	"github.com/ProtoconNet/mitum2/network/quicmemberlist"
@@ -57,7 +58,6 @@ func NewHTTP2Server(
 	idleTimeout := time.Second * 10
 
 	r := mux.NewRouter()
-	//r.Use(RateLimiter(5, 20))
 
 	sv := &HTTP2Server{
 		Logging: logging.NewLogging(func(c zerolog.Context) zerolog.Context {
@@ -82,6 +82,14 @@ func NewHTTP2Server(
 
 	sv.ContextDaemon = mitumutil.NewContextDaemon(sv.start)
 
+	metrics.Default.SetGaugeFunc(
+		"mitum_digest_api_queue_length",
+		"number of operations waiting in send queue of digest api",
+		func() float64 {
+			return float64(len(sv.queue))
+		},
+	)
+
 	return sv, nil
 }
 
@@ -384,17 +392,3 @@ func (ln tcpKeepAliveListener) Accept() (net.Conn, error) {
 
 	return tc, nil
 }
-
-//func RateLimiter(rps int, burst int) mux.MiddlewareFunc {
-//	limiter := rate.NewLimiter(rate.Limit(rps), burst)
-//
-//	return func(next http.Handler) http.Handler {
-//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-//			if !limiter.Allow() {
-//				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
-//				return
-//			}
-//			next.ServeHTTP(w, r)
-//		})
-//	}
-//}
@@ -0,0 +1,167 @@
+package digest
+
+import (
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StateDigest digests the custom states of the extension modules, which are
+// built on mitum-currency, into its own collection. The DigestWrites are
+// written by BlockSession in the same transaction with the other collections,
+// and cleaned by height like the other collections, so the documents should
+// have `height` field. DigestWrite is applied by both mongodb and leveldb
+// Store; the invalid DigestWrite fails the block session before it is written.
+// * Collection: name of collection.
+// * IsStateKey: selects the states by state key.
+// * Indexes: indexes of collection.
+// * Writes: builds the DigestWrites of state.
+// * SetHandlers: optional; adds the http handlers to digest API.
+type StateDigest struct {
+	Collection  string
+	IsStateKey  func(string) bool
+	Indexes     []mongo.IndexModel
+	Writes      DigestWritePrepareFunc
+	SetHandlers func(*Handlers)
+}
+
+func (sd StateDigest) IsValid([]byte) error {
+	switch {
+	case len(sd.Collection) < 1:
+		return errors.Errorf("Empty collection")
+	case sd.IsStateKey == nil:
+		return errors.Errorf("Empty IsStateKey")
+	case sd.Writes == nil:
+		return errors.Errorf("Empty Writes")
+	}
+
+	return nil
+}
+
+var stateDigests = struct {
+	sync.RWMutex
+	l []StateDigest
+}{}
+
+// RegisterStateDigest registers StateDigest. It should be called before the
+// digest database and API are initialized, usually in init() of extension
+// module.
+func RegisterStateDigest(sd StateDigest) error {
+	if err := sd.IsValid(nil); err != nil {
+		return errors.WithMessage(err, "invalid StateDigest")
+	}
+
+	stateDigests.Lock()
+	defer stateDigests.Unlock()
+
+	if isBuiltinCollection(sd.Collection) {
+		return errors.Errorf("Collection already used by digest, %q", sd.Collection)
+	}
+
+	for i := range stateDigests.l {
+		if stateDigests.l[i].Collection == sd.Collection {
+			return errors.Errorf("Collection already registered, %q", sd.Collection)
+		}
+	}
+
+	stateDigests.l = append(stateDigests.l, sd)
+
+	return nil
+}
+
+// StateDigests returns the registered StateDigests by the registered order.
+func StateDigests() []StateDigest {
+	stateDigests.RLock()
+	defer stateDigests.RUnlock()
+
+	l := make([]StateDigest, len(stateDigests.l))
+	copy(l, stateDigests.l)
+
+	return l
+}
+
+func builtinCollections() []string {
+	return []string{
+		defaultColNameAccount,
+		defaultColNameBalance,
+		defaultColNameCurrency,
+		defaultColNameOperation,
+		defaultColNameBlock,
+		defaultColNameContractAccount,
+	}
+}
+
+func isBuiltinCollection(col string) bool {
+	for _, i := range builtinCollections() {
+		if col == i {
+			return true
+		}
+	}
+
+	return false
+}
+
+// digestCollections returns the builtin and registered collections.
+func digestCollections() []string {
+	cols := builtinCollections()
+
+	sds := StateDigests()
+	for i := range sds {
+		cols = append(cols, sds[i].Collection)
+	}
+
+	return cols
+}
+
+func (bs *BlockSession) prepareStateDigests() error {
+	sds := StateDigests()
+	if len(sds) < 1 || len(bs.sts) < 1 {
+		return nil
+	}
+
+	writes := map[string][]DigestWrite{}
+
+	for i := range bs.sts {
+		st := bs.sts[i]
+
+		for j := range sds {
+			if !sds[j].IsStateKey(st.Key()) {
+				continue
+			}
+
+			k, err := sds[j].Writes(bs, st)
+			if err != nil {
+				return errors.WithMessagef(err, "prepare state digest, %q", sds[j].Collection)
+			}
+
+			for l := range k {
+				if err := k[l].IsValid(nil); err != nil {
+					return errors.WithMessagef(err, "invalid write of state digest, %q", sds[j].Collection)
+				}
+			}
+
+			writes[sds[j].Collection] = append(writes[sds[j].Collection], k...)
+		}
+	}
+
+	bs.stateDigestWrites = writes
+
+	return nil
+}
+
+// Encoder returns the encoder for the documents.
+func (bs *BlockSession) Encoder() encoder.Encoder {
+	return bs.enc
+}
+
+// Height returns the height of block.
+func (bs *BlockSession) Height() base.Height {
+	if bs.block == nil {
+		return base.NilHeight
+	}
+
+	return bs.block.Manifest().Height()
+}
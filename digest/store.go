@@ -0,0 +1,210 @@
+package digest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	mitumutil "github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/fixedtree"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Store keeps the digested blocks and serves them to the digest API. Database
+// is the mongodb Store; LeveldbDatabase is the embedded Store for the small
+// deployments, which run without mongodb server.
+type Store interface {
+	Readonly() bool
+	Close() error
+	LastBlock() base.Height
+	SetLastBlock(base.Height) error
+	Clean() error
+	CleanByHeight(context.Context, base.Height) error
+	NewBlockSession(
+		base.BlockMap, []base.Operation, fixedtree.Tree, []base.State, base.ProposalSignFact, string,
+	) (BlockSessioner, error)
+	Manifests(
+		load, reverse bool,
+		offset base.Height,
+		limit int64,
+		callback func(base.Height, base.Manifest, uint64, string, string, uint64) (bool, error),
+	) error
+	ManifestByHeight(base.Height) (base.Manifest, uint64, string, string, uint64, error)
+	ManifestByHash(mitumutil.Hash) (base.Manifest, uint64, string, string, uint64, error)
+	Operation(h mitumutil.Hash, load bool) (OperationValue, bool, error)
+	Operations(
+		of OperationFilter,
+		offset string,
+		reverse, load bool,
+		limit int64,
+		callback func(mitumutil.Hash, OperationValue, int64) (bool, error),
+	) error
+	OperationsByHeight(
+		height base.Height,
+		offset string,
+		reverse, load bool,
+		limit int64,
+		callback func(mitumutil.Hash, OperationValue, int64) (bool, error),
+	) error
+	OperationsByHash(
+		hashes []mitumutil.Hash,
+		callback func(mitumutil.Hash, OperationValue, int64) (bool, error),
+	) error
+	OperationsByAddress(
+		address base.Address,
+		load, reverse bool,
+		offset string,
+		limit int64,
+		of OperationFilter,
+		callback func(mitumutil.Hash, OperationValue) (bool, error),
+	) error
+	Account(base.Address) (AccountValue, bool, error)
+	AccountsByPublickey(
+		pub base.Publickey,
+		loadBalance bool,
+		offsetHeight base.Height,
+		offsetAddress string,
+		limit int64,
+		callback func(AccountValue) (bool, error),
+	) error
+	TopHeightByPublickey(base.Publickey) (base.Height, error)
+	BalanceHistory(
+		address base.Address,
+		cid string,
+		fromHeight, toHeight, offset base.Height,
+		reverse bool,
+		limit int64,
+		callback func(base.State) (bool, error),
+	) error
+	BalanceByHeight(address base.Address, cid string, height base.Height) (base.State, bool, error)
+	Currencies() ([]string, error)
+	Currency(cid string) (types.CurrencyDesign, base.State, error)
+	CurrencyHolders(cid string, offset, limit int64) ([]CurrencyHolder, int64, error)
+	CurrencyBalanceSum(cid string, addresses []string) (common.Big, error)
+	CurrencySupplyBalances(cid string, callback func(key string, amount common.Big) (bool, error)) error
+}
+
+// DigestWriteKind is the kind of DigestWrite.
+type DigestWriteKind uint8
+
+const (
+	DigestWriteInsert DigestWriteKind = iota + 1
+	DigestWriteReplace
+	DigestWriteUpdate
+	DigestWriteDelete
+)
+
+// DigestWrite is the write operation of StateDigest document, which is applied
+// by every Store. The filter matches the documents by the equality of fields;
+// the embedded field is "a.b". The update sets and unsets the top-level fields.
+// The query operators like "$gt" are not allowed.
+type DigestWrite struct {
+	doc    interface{}
+	filter bson.D
+	set    bson.D
+	unset  []string
+	kind   DigestWriteKind
+	upsert bool
+	many   bool
+}
+
+// NewDigestInsert inserts the document.
+func NewDigestInsert(doc interface{}) DigestWrite {
+	return DigestWrite{kind: DigestWriteInsert, doc: doc}
+}
+
+// NewDigestReplace replaces the first matched document. With upsert, the
+// document is inserted if not matched.
+func NewDigestReplace(filter bson.D, doc interface{}, upsert bool) DigestWrite {
+	return DigestWrite{kind: DigestWriteReplace, filter: filter, doc: doc, upsert: upsert}
+}
+
+// NewDigestUpdate updates the fields of the first matched document, or all the
+// matched documents with many. With upsert, the document of the fields of
+// filter and set is inserted if not matched.
+func NewDigestUpdate(filter, set bson.D, unset []string, upsert, many bool) DigestWrite {
+	return DigestWrite{kind: DigestWriteUpdate, filter: filter, set: set, unset: unset, upsert: upsert, many: many}
+}
+
+// NewDigestDelete deletes the first matched document, or all the matched
+// documents with many.
+func NewDigestDelete(filter bson.D, many bool) DigestWrite {
+	return DigestWrite{kind: DigestWriteDelete, filter: filter, many: many}
+}
+
+func (w DigestWrite) IsValid([]byte) error {
+	switch w.kind {
+	case DigestWriteInsert, DigestWriteReplace:
+		if w.doc == nil {
+			return errors.Errorf("Empty document")
+		}
+	case DigestWriteUpdate:
+		if len(w.set) < 1 && len(w.unset) < 1 {
+			return errors.Errorf("Empty update")
+		}
+	case DigestWriteDelete:
+	default:
+		return errors.Errorf("Unknown write kind, %d", w.kind)
+	}
+
+	for i := range w.filter {
+		if err := isValidDigestWriteKey(w.filter[i].Key, true); err != nil {
+			return errors.WithMessage(err, "filter")
+		}
+
+		if err := isValidDigestWriteValue(w.filter[i].Value); err != nil {
+			return errors.WithMessagef(err, "filter, %q", w.filter[i].Key)
+		}
+	}
+
+	for i := range w.set {
+		if err := isValidDigestWriteKey(w.set[i].Key, false); err != nil {
+			return errors.WithMessage(err, "set")
+		}
+	}
+
+	for i := range w.unset {
+		if err := isValidDigestWriteKey(w.unset[i], false); err != nil {
+			return errors.WithMessage(err, "unset")
+		}
+	}
+
+	return nil
+}
+
+func isValidDigestWriteKey(key string, embedded bool) error {
+	switch {
+	case len(key) < 1:
+		return errors.Errorf("Empty field")
+	case strings.HasPrefix(key, "$"):
+		return errors.Errorf("Unsupported operator, %q", key)
+	case !embedded && strings.Contains(key, "."):
+		return errors.Errorf("Unsupported embedded field, %q", key)
+	}
+
+	return nil
+}
+
+// isValidDigestWriteValue checks the value of filter does not have the query
+// operators.
+func isValidDigestWriteValue(v interface{}) error {
+	switch t := v.(type) {
+	case bson.D:
+		for i := range t {
+			if strings.HasPrefix(t[i].Key, "$") {
+				return errors.Errorf("Unsupported operator, %q", t[i].Key)
+			}
+		}
+	case bson.M:
+		for k := range t {
+			if strings.HasPrefix(k, "$") {
+				return errors.Errorf("Unsupported operator, %q", k)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package digest
+
+import (
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum2/base"
+)
+
+// SuffrageBondStateDigest digests the deposits of suffrage candidate nodes for
+// the supply audit. The document of bond is inserted by height, so the latest
+// document of node is the current deposit.
+var SuffrageBondStateDigest = StateDigest{
+	Collection: defaultColNameSuffrageBond,
+	IsStateKey: statecurrency.IsSuffrageBondStateKey,
+	Indexes:    suffrageBondIndexModels,
+	Writes: func(bs *BlockSession, st base.State) ([]DigestWrite, error) {
+		doc, err := NewSuffrageBondDoc(st, bs.Encoder())
+		if err != nil {
+			return nil, err
+		}
+
+		return []DigestWrite{NewDigestInsert(doc)}, nil
+	},
+}
+
+func init() {
+	if err := RegisterStateDigest(SuffrageBondStateDigest); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,93 @@
+package digest
+
+import (
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/pkg/errors"
+)
+
+type SupplyYAML struct {
+	Excluded           []string `yaml:"excluded,omitempty"`
+	ExcludeFeeReceiver bool     `yaml:"exclude_fee_receiver,omitempty"`
+}
+
+// SupplyConfig decides which accounts are not counted in the circulating
+// supply of currency.
+type SupplyConfig struct {
+	excluded           []string
+	excludeFeeReceiver bool
+}
+
+func NewSupplyConfig(y *SupplyYAML) (SupplyConfig, error) {
+	var c SupplyConfig
+	if y == nil {
+		return c, nil
+	}
+
+	founds := map[string]struct{}{}
+	for i := range y.Excluded {
+		a := strings.TrimSpace(y.Excluded[i])
+		switch {
+		case len(a) < 1:
+			return c, errors.Errorf("Empty excluded address")
+		case !strings.HasSuffix(a, types.AddressHint.Type().String()):
+			return c, errors.Errorf("Invalid excluded address, %q", a)
+		}
+
+		if err := types.NewAddress(strings.TrimSuffix(a, types.AddressHint.Type().String())).IsValid(nil); err != nil {
+			return c, errors.WithMessagef(err, "Invalid excluded address, %q", a)
+		}
+
+		if _, found := founds[a]; found {
+			return c, errors.Errorf("Duplicated excluded address, %q", a)
+		}
+
+		founds[a] = struct{}{}
+		c.excluded = append(c.excluded, a)
+	}
+
+	c.excludeFeeReceiver = y.ExcludeFeeReceiver
+
+	return c, nil
+}
+
+// Excluded returns the excluded addresses for the currency.
+func (c SupplyConfig) Excluded(de types.CurrencyDesign) []string {
+	l := make([]string, len(c.excluded), len(c.excluded)+1)
+	copy(l, c.excluded)
+
+	if !c.excludeFeeReceiver {
+		return l
+	}
+
+	if fe := de.Policy().Feeer(); fe != nil {
+		if r := fe.Receiver(); r != nil {
+			s := r.String()
+
+			var found bool
+			for i := range l {
+				if l[i] == s {
+					found = true
+
+					break
+				}
+			}
+
+			if !found {
+				l = append(l, s)
+			}
+		}
+	}
+
+	return l
+}
+
+type CurrencyHolder struct {
+	Address string      `json:"address"`
+	Amount  common.Big  `json:"amount"`
+	Height  base.Height `json:"height"`
+	Rank    int64       `json:"rank"`
+}
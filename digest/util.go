@@ -186,6 +186,27 @@ func HTTP2WriteProblem(w http.ResponseWriter, pr Problem, status int) {
 	_, _ = w.Write(output)
 }
 
+// HTTP2WriteProblemDetail writes the whole problem document, not only the
+// title like HTTP2WriteProblem.
+func HTTP2WriteProblemDetail(w http.ResponseWriter, pr Problem, status int) {
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", ProblemMimetype)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	var output []byte
+	if b, err := JSON.Marshal(pr); err != nil {
+		output = UnknownProblemJSON
+	} else {
+		output = b
+	}
+
+	w.WriteHeader(status)
+	_, _ = w.Write(output)
+}
+
 func HTTP2WriteHal(enc encoder.Encoder, w http.ResponseWriter, hal Hal, status int) { // nolint:unparam
 	stream, flush := HTTP2Stream(enc, w, 1, status)
 	defer flush()
@@ -32,6 +32,7 @@ var CLI struct { //nolint:govet //...
 		Currency cmds.CurrencyCommand `cmd:"" help:"currency operation"`
 		Suffrage cmds.SuffrageCommand `cmd:"" help:"suffrage operation"`
 	} `cmd:"" help:"create operation"`
+	Offline cmds.OfflineCommand `cmd:"" help:"build and sign operation without node"`
 	Network struct {
 		Client cmds.NetworkClientCommand `cmd:"" help:"network client"`
 	} `cmd:"" help:"network"`
@@ -40,6 +41,10 @@ var CLI struct { //nolint:govet //...
 		Address cmds.KeyAddressCommand `cmd:"" help:"generate address from key"`
 		Load    cmds.KeyLoadCommand    `cmd:"" help:"load key"`
 		Sign    cmds.KeySignCommand    `cmd:"" help:"sign"`
+		Export  cmds.KeyExportCommand  `cmd:"" help:"export key to keystore"`
+		Import  cmds.KeyImportCommand  `cmd:"" help:"import key from keystore"`
+		Plan    cmds.KeyPlanCommand    `cmd:"" help:"plan key update by current account keys"`
+		Signer  cmds.KeySignerCommand  `cmd:"" help:"serve key as external signer for testing"`
 	} `cmd:"" help:"key"`
 	Handover launchcmd.HandoverCommands `cmd:""`
 	Version  struct{}                   `cmd:"" help:"version"`
@@ -0,0 +1,61 @@
+package metrics
+
+var (
+	DigestBlockSessionCommit = Default.NewHistogram(
+		"mitum_digest_block_session_commit_seconds",
+		"duration of committing digested block to database",
+		DefaultDurationBuckets,
+	)
+	DigestMongoWrites = Default.NewCounter(
+		"mitum_digest_mongo_writes_total",
+		"number of documents written by digester",
+		"collection",
+	)
+	DigestRollbacks = Default.NewCounter(
+		"mitum_digest_rollbacks_total",
+		"number of digest rollbacks by diverged blocks",
+	)
+	DigestGapsFilled = Default.NewCounter(
+		"mitum_digest_gaps_filled_total",
+		"number of missed heights digested again by digester",
+	)
+	DigestCacheRequests = Default.NewCounter(
+		"mitum_digest_api_cache_requests_total",
+		"number of digest api cache lookups",
+		"result",
+	)
+	OperationPreProcess = Default.NewHistogram(
+		"mitum_operation_preprocess_seconds",
+		"duration of operation preprocess",
+		DefaultDurationBuckets,
+		"hint",
+	)
+	OperationProcess = Default.NewHistogram(
+		"mitum_operation_process_seconds",
+		"duration of operation process",
+		DefaultDurationBuckets,
+		"hint",
+	)
+)
+
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+func init() {
+	Default.SetGaugeFunc(
+		"mitum_digest_api_cache_hit_ratio",
+		"ratio of cache hits to cache lookups of digest api",
+		func() float64 {
+			hit := DigestCacheRequests.Value(CacheHit)
+			total := hit + DigestCacheRequests.Value(CacheMiss)
+
+			if total < 1 {
+				return 0
+			}
+
+			return float64(hit) / float64(total)
+		},
+	)
+}
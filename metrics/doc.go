@@ -0,0 +1,5 @@
+/*
+Package metrics provides the metrics of node and digester in the prometheus
+text exposition format.
+*/
+package metrics
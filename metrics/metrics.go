@@ -0,0 +1,412 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const TextMimetype = "text/plain; version=0.0.4; charset=utf-8"
+
+var DefaultDurationBuckets = []float64{
+	0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10,
+}
+
+// Default is the registry shared by the node and digester. It is exported
+// thru the `/metrics` path of digest API with the api key, and the
+// `--http-state` of node.
+var Default = NewRegistry()
+
+type collector interface {
+	Name() string
+	Help() string
+	Type() string
+	write(io.Writer, string) error
+}
+
+// Registry keeps collectors and writes them in the prometheus text exposition
+// format.
+type Registry struct {
+	sync.RWMutex
+	m map[string]collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{m: map[string]collector{}}
+}
+
+func (r *Registry) register(c collector) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, found := r.m[c.Name()]; found {
+		return errors.Errorf("metric already registered, %q", c.Name())
+	}
+
+	r.m[c.Name()] = c
+
+	return nil
+}
+
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{vec: newVec[*counterValue](name, help, labels, func() *counterValue {
+		return &counterValue{}
+	})}
+
+	if err := r.register(c); err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	bs := make([]float64, len(buckets))
+	copy(bs, buckets)
+	sort.Float64s(bs)
+
+	h := &Histogram{buckets: bs}
+	h.vec = newVec[*histogramValue](name, help, labels, func() *histogramValue {
+		return &histogramValue{counts: make([]uint64, len(bs))}
+	})
+
+	if err := r.register(h); err != nil {
+		panic(err)
+	}
+
+	return h
+}
+
+// SetGaugeFunc sets the gauge, which value is evaluated whenever collected.
+// Unlike the others, the existing gauge of same name is replaced.
+func (r *Registry) SetGaugeFunc(name, help string, f func() float64) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.m[name] = &GaugeFunc{name: name, help: help, f: f}
+}
+
+func (r *Registry) Unregister(name string) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.m, name)
+}
+
+func (r *Registry) Write(w io.Writer) error {
+	r.RLock()
+	cs := make([]collector, 0, len(r.m))
+	for i := range r.m {
+		cs = append(cs, r.m[i])
+	}
+	r.RUnlock()
+
+	sort.Slice(cs, func(i, j int) bool {
+		return cs[i].Name() < cs[j].Name()
+	})
+
+	bw := bufio.NewWriter(w)
+
+	for i := range cs {
+		c := cs[i]
+
+		if _, err := fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n",
+			c.Name(), escapeHelp(c.Help()), c.Name(), c.Type()); err != nil {
+			return err
+		}
+
+		if err := c.write(bw, c.Name()); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", TextMimetype)
+
+		if err := r.Write(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+type vec[T any] struct {
+	sync.RWMutex
+	name   string
+	help   string
+	labels []string
+	m      map[string]T
+	values map[string][]string
+	newf   func() T
+}
+
+func newVec[T any](name, help string, labels []string, newf func() T) vec[T] {
+	return vec[T]{
+		name:   name,
+		help:   help,
+		labels: labels,
+		m:      map[string]T{},
+		values: map[string][]string{},
+		newf:   newf,
+	}
+}
+
+func (v *vec[T]) Name() string {
+	return v.name
+}
+
+func (v *vec[T]) Help() string {
+	return v.help
+}
+
+func (v *vec[T]) with(values []string) T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metric %q expects %d label values, not %d", v.name, len(v.labels), len(values)))
+	}
+
+	k := strings.Join(values, "\xff")
+
+	v.RLock()
+	i, found := v.m[k]
+	v.RUnlock()
+
+	if found {
+		return i
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if i, found := v.m[k]; found {
+		return i
+	}
+
+	i = v.newf()
+	v.m[k] = i
+	v.values[k] = append([]string(nil), values...)
+
+	return i
+}
+
+func (v *vec[T]) each(f func(labels string, value T) error) error {
+	v.RLock()
+	keys := make([]string, 0, len(v.m))
+	for k := range v.m {
+		keys = append(keys, k)
+	}
+	v.RUnlock()
+
+	sort.Strings(keys)
+
+	for i := range keys {
+		v.RLock()
+		value := v.m[keys[i]]
+		values := v.values[keys[i]]
+		v.RUnlock()
+
+		if err := f(formatLabels(v.labels, values), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type counterValue struct {
+	n uint64
+}
+
+// Counter is monotonically increasing value.
+type Counter struct {
+	vec[*counterValue]
+}
+
+func (*Counter) Type() string {
+	return "counter"
+}
+
+func (c *Counter) Add(n uint64, labels ...string) {
+	atomic.AddUint64(&c.with(labels).n, n)
+}
+
+func (c *Counter) Inc(labels ...string) {
+	c.Add(1, labels...)
+}
+
+func (c *Counter) Value(labels ...string) uint64 {
+	return atomic.LoadUint64(&c.with(labels).n)
+}
+
+func (c *Counter) write(w io.Writer, name string) error {
+	return c.each(func(labels string, v *counterValue) error {
+		_, err := fmt.Fprintf(w, "%s%s %d\n", name, labels, atomic.LoadUint64(&v.n))
+
+		return err
+	})
+}
+
+type histogramValue struct {
+	sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Histogram counts observations in the configured buckets.
+type Histogram struct {
+	vec[*histogramValue]
+	buckets []float64
+}
+
+func (*Histogram) Type() string {
+	return "histogram"
+}
+
+func (h *Histogram) Observe(f float64, labels ...string) {
+	v := h.with(labels)
+
+	v.Lock()
+	defer v.Unlock()
+
+	for i := range h.buckets {
+		if f <= h.buckets[i] {
+			v.counts[i]++
+		}
+	}
+
+	v.count++
+	v.sum += f
+}
+
+func (h *Histogram) ObserveDuration(d time.Duration, labels ...string) {
+	h.Observe(d.Seconds(), labels...)
+}
+
+// Since observes the elapsed time from started.
+func (h *Histogram) Since(started time.Time, labels ...string) {
+	h.ObserveDuration(time.Since(started), labels...)
+}
+
+func (h *Histogram) write(w io.Writer, name string) error {
+	return h.each(func(labels string, v *histogramValue) error {
+		v.Lock()
+		counts := make([]uint64, len(v.counts))
+		copy(counts, v.counts)
+		count, sum := v.count, v.sum
+		v.Unlock()
+
+		for i := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n",
+				name, appendLabel(labels, "le", formatFloat(h.buckets[i])), counts[i]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", name, appendLabel(labels, "le", "+Inf"), count); err != nil {
+			return err
+		}
+
+		_, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+			name, labels, formatFloat(sum), name, labels, count)
+
+		return err
+	})
+}
+
+// GaugeFunc is the gauge which value is given by function.
+type GaugeFunc struct {
+	name string
+	help string
+	f    func() float64
+}
+
+func (g *GaugeFunc) Name() string {
+	return g.name
+}
+
+func (g *GaugeFunc) Help() string {
+	return g.help
+}
+
+func (*GaugeFunc) Type() string {
+	return "gauge"
+}
+
+func (g *GaugeFunc) write(w io.Writer, name string) error {
+	_, err := fmt.Fprintf(w, "%s %s\n", name, formatFloat(g.f()))
+
+	return err
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) < 1 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	_ = sb.WriteByte('{')
+
+	for i := range names {
+		if i > 0 {
+			_ = sb.WriteByte(',')
+		}
+
+		_, _ = sb.WriteString(names[i])
+		_, _ = sb.WriteString(`="`)
+		_, _ = sb.WriteString(escapeLabelValue(values[i]))
+		_ = sb.WriteByte('"')
+	}
+
+	_ = sb.WriteByte('}')
+
+	return sb.String()
+}
+
+func appendLabel(labels, name, value string) string {
+	l := name + `="` + escapeLabelValue(value) + `"`
+	if len(labels) < 1 {
+		return "{" + l + "}"
+	}
+
+	return labels[:len(labels)-1] + "," + l + "}"
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
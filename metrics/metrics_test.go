@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func writeRegistry(t *testing.T, r *Registry) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+
+	c := r.NewCounter("test_requests_total", "Total requests.", "path", "code")
+	c.Inc("/a", "200")
+	c.Add(2, "/a", "200")
+	c.Inc("/b", "500")
+
+	if n := c.Value("/a", "200"); n != 3 {
+		t.Fatalf("expected 3, but %d", n)
+	}
+
+	expected := `# HELP test_requests_total Total requests.
+# TYPE test_requests_total counter
+test_requests_total{path="/a",code="200"} 3
+test_requests_total{path="/b",code="500"} 1
+`
+
+	if s := writeRegistry(t, r); s != expected {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+}
+
+func TestCounterWithoutLabels(t *testing.T) {
+	r := NewRegistry()
+
+	r.NewCounter("test_total", "Total.").Add(7)
+
+	expected := `# HELP test_total Total.
+# TYPE test_total counter
+test_total 7
+`
+
+	if s := writeRegistry(t, r); s != expected {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+
+	// NOTE buckets are sorted
+	h := r.NewHistogram("test_duration_seconds", "Duration.", []float64{1, 0.1, 0.5}, "op")
+	for _, f := range []float64{0.05, 0.1, 0.3, 2} {
+		h.Observe(f, "x")
+	}
+
+	expected := `# HELP test_duration_seconds Duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{op="x",le="0.1"} 2
+test_duration_seconds_bucket{op="x",le="0.5"} 3
+test_duration_seconds_bucket{op="x",le="1"} 3
+test_duration_seconds_bucket{op="x",le="+Inf"} 4
+test_duration_seconds_sum{op="x"} 2.45
+test_duration_seconds_count{op="x"} 4
+`
+
+	if s := writeRegistry(t, r); s != expected {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+}
+
+func TestHistogramWithoutLabels(t *testing.T) {
+	r := NewRegistry()
+
+	r.NewHistogram("test_size", "Size.", []float64{10}).Observe(3)
+
+	expected := `# HELP test_size Size.
+# TYPE test_size histogram
+test_size_bucket{le="10"} 1
+test_size_bucket{le="+Inf"} 1
+test_size_sum 3
+test_size_count 1
+`
+
+	if s := writeRegistry(t, r); s != expected {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetGaugeFunc("test_b", "B.", func() float64 { return 1.5 })
+	r.SetGaugeFunc("test_a", "A.", func() float64 { return math.Inf(1) })
+	// NOTE replaced
+	r.SetGaugeFunc("test_b", "B.", func() float64 { return 2 })
+
+	expected := `# HELP test_a A.
+# TYPE test_a gauge
+test_a +Inf
+# HELP test_b B.
+# TYPE test_b gauge
+test_b 2
+`
+
+	if s := writeRegistry(t, r); s != expected {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+
+	r.Unregister("test_a")
+
+	if s := writeRegistry(t, r); strings.Contains(s, "test_a") {
+		t.Fatalf("unregistered gauge found:\n%s", s)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	r := NewRegistry()
+
+	c := r.NewCounter("test_escape_total", "Help with \\ and\nnew line.", "v")
+	c.Inc("a\"b\\c\nd")
+
+	expected := `# HELP test_escape_total Help with \\ and\nnew line.
+# TYPE test_escape_total counter
+test_escape_total{v="a\"b\\c\nd"} 1
+`
+
+	if s := writeRegistry(t, r); s != expected {
+		t.Fatalf("unexpected output:\n%s", s)
+	}
+}
+
+func TestRegisterDuplicated(t *testing.T) {
+	r := NewRegistry()
+
+	r.NewCounter("test_total", "Total.")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+
+	r.NewHistogram("test_total", "Total.", DefaultDurationBuckets)
+}
+
+func TestWrongLabelValues(t *testing.T) {
+	r := NewRegistry()
+
+	c := r.NewCounter("test_total", "Total.", "a", "b")
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+
+	c.Inc("x")
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+
+	r.NewCounter("test_total", "Total.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	switch {
+	case rec.Code != http.StatusOK:
+		t.Fatalf("unexpected status, %d", rec.Code)
+	case rec.Header().Get("Content-Type") != TextMimetype:
+		t.Fatalf("unexpected content type, %q", rec.Header().Get("Content-Type"))
+	case !strings.Contains(rec.Body.String(), "test_total 1\n"):
+		t.Fatalf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+func TestDefaultCollectors(t *testing.T) {
+	s := writeRegistry(t, Default)
+
+	for _, i := range []string{
+		"# TYPE mitum_digest_block_session_commit_seconds histogram\n",
+		"# TYPE mitum_digest_mongo_writes_total counter\n",
+		"# TYPE mitum_digest_rollbacks_total counter\n",
+		"# TYPE mitum_digest_gaps_filled_total counter\n",
+		"# TYPE mitum_digest_api_cache_requests_total counter\n",
+		"# TYPE mitum_operation_preprocess_seconds histogram\n",
+		"# TYPE mitum_operation_process_seconds histogram\n",
+		"# TYPE mitum_digest_api_cache_hit_ratio gauge\n",
+	} {
+		if !strings.Contains(s, i) {
+			t.Fatalf("%q not found:\n%s", i, s)
+		}
+	}
+}
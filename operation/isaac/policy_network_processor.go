@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ProtoconNet/mitum-currency/v3/common"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
 	"github.com/ProtoconNet/mitum-currency/v3/types"
 
 	"github.com/ProtoconNet/mitum2/base"
@@ -113,6 +114,19 @@ func (p *NetworkPolicyProcessor) PreProcess(ctx context.Context, op base.Operati
 		return ctx, base.NewBaseOperationProcessReasonError("same with existing network policy"), nil
 	}
 
+	// NOTE the treasury of BondSuffrageCandidateLimiterRule should exist to
+	// receive the slash.
+	if rule, ok := newpolicy.SuffrageCandidateLimiterRule().(BondSuffrageCandidateLimiterRule); ok &&
+		rule.SlashRate() > 0 {
+		switch _, found, err := getStateFunc(statecurrency.AccountStateKey(rule.Treasury())); {
+		case err != nil:
+			return ctx, nil, e.Wrap(err)
+		case !found:
+			return ctx, base.NewBaseOperationProcessReasonError(
+				"treasury account not found, %v", rule.Treasury()), nil
+		}
+	}
+
 	p.newop = op.Hash()
 
 	return ctx, nil, nil
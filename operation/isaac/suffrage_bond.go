@@ -0,0 +1,120 @@
+package isaacoperation
+
+import (
+	"context"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/state"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+var SuffrageBondPreProcessedContextKey = util.ContextKey("suffrage-bond-preprocessed")
+
+// SuffrageBond returns the bonded deposit of node. The released deposit is
+// not found.
+func SuffrageBond(
+	node base.Address, getStateFunc base.GetStateFunc,
+) (statecurrency.SuffrageBondStateValue, bool, error) {
+	switch st, found, err := getStateFunc(statecurrency.SuffrageBondStateKey(node)); {
+	case err != nil:
+		return statecurrency.SuffrageBondStateValue{}, false, err
+	case !found:
+		return statecurrency.SuffrageBondStateValue{}, false, nil
+	default:
+		bond, err := statecurrency.StateSuffrageBondValue(st)
+		if err != nil {
+			return statecurrency.SuffrageBondStateValue{}, false, err
+		}
+
+		return bond, bond.Amount.Big().OverZero(), nil
+	}
+}
+
+// preprocessSuffrageBond marks the bond of node in the context; the bond can be
+// bonded or released by only one operation in a block.
+func preprocessSuffrageBond(ctx context.Context, node base.Address) (context.Context, bool) {
+	var preprocessed []base.Address
+
+	_ = util.LoadFromContext(ctx, SuffrageBondPreProcessedContextKey, &preprocessed)
+
+	for i := range preprocessed {
+		if preprocessed[i].Equal(node) {
+			return ctx, false
+		}
+	}
+
+	return context.WithValue(ctx, SuffrageBondPreProcessedContextKey, append(preprocessed, node)), true
+}
+
+// bondSuffrageDepositMergeValues moves the deposit from the account balance to
+// the bond of node.
+func bondSuffrageDepositMergeValues(
+	node, account base.Address, deposit types.Amount,
+) []base.StateMergeValue {
+	key := statecurrency.BalanceStateKey(account, deposit.Currency())
+
+	return []base.StateMergeValue{
+		common.NewBaseStateMergeValue(
+			key,
+			statecurrency.NewDeductBalanceStateValue(deposit),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return statecurrency.NewBalanceStateValueMerger(height, key, deposit.Currency(), st)
+			},
+		),
+		state.NewStateMergeValue(
+			statecurrency.SuffrageBondStateKey(node),
+			statecurrency.NewSuffrageBondStateValue(account, deposit),
+		),
+	}
+}
+
+// releaseSuffrageBondMergeValues returns the bonded deposit to the account; the
+// slash goes to the treasury.
+func releaseSuffrageBondMergeValues(
+	node base.Address,
+	bond statecurrency.SuffrageBondStateValue,
+	slash common.Big,
+	treasury base.Address,
+) ([]base.StateMergeValue, error) {
+	cid := bond.Amount.Currency()
+
+	switch {
+	case slash.Compare(common.ZeroBig) < 0, slash.Compare(bond.Amount.Big()) > 0:
+		return nil, errors.Errorf("invalid slash, %v of %v", slash, bond.Amount.Big())
+	case slash.OverZero() && treasury == nil:
+		return nil, errors.Errorf("empty treasury for slash")
+	}
+
+	var values []base.StateMergeValue
+
+	add := func(account base.Address, big common.Big) {
+		key := statecurrency.BalanceStateKey(account, cid)
+
+		values = append(values, common.NewBaseStateMergeValue(
+			key,
+			statecurrency.NewAddBalanceStateValue(types.NewAmount(big, cid)),
+			func(height base.Height, st base.State) base.StateValueMerger {
+				return statecurrency.NewBalanceStateValueMerger(height, key, cid, st)
+			},
+		))
+	}
+
+	if refund := bond.Amount.Big().Sub(slash); refund.OverZero() {
+		add(bond.Account, refund)
+	}
+
+	if slash.OverZero() {
+		add(treasury, slash)
+	}
+
+	values = append(values, state.NewStateMergeValue(
+		statecurrency.SuffrageBondStateKey(node),
+		statecurrency.NewSuffrageBondStateValue(bond.Account, types.NewZeroAmount(cid)),
+	))
+
+	return values, nil
+}
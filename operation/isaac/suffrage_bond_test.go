@@ -0,0 +1,428 @@
+package isaacoperation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/isaac"
+)
+
+var testBondNetworkID = base.NetworkID("suffrage-bond-test")
+
+type testSuffrageBond struct {
+	t        *testing.T
+	states   map[string]base.State
+	priv     base.Privatekey
+	node     base.Address
+	account  base.Address
+	treasury base.Address
+	cid      types.CurrencyID
+}
+
+func newTestSuffrageBond(t *testing.T) *testSuffrageBond {
+	t.Helper()
+
+	priv := types.NewMEPrivatekey()
+
+	account, err := SuffrageCandidateAccount(priv.Publickey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := types.NewBaseAccountKey(types.NewMEPrivatekey().Publickey(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := types.NewBaseAccountKeys([]types.AccountKey{key}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	treasury, err := types.NewAccountFromKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &testSuffrageBond{
+		t:        t,
+		states:   map[string]base.State{},
+		priv:     priv,
+		node:     base.NewStringAddress("node0"),
+		account:  account,
+		treasury: treasury.Address(),
+		cid:      types.CurrencyID("MCC"),
+	}
+
+	b.set(statecurrency.AccountStateKey(treasury.Address()), statecurrency.NewAccountStateValue(treasury))
+
+	return b
+}
+
+func (b *testSuffrageBond) rule(slashRate uint64) BondSuffrageCandidateLimiterRule {
+	return NewBondSuffrageCandidateLimiterRule(3, b.cid, common.NewBig(100), slashRate, b.treasury)
+}
+
+func (b *testSuffrageBond) set(key string, v base.StateValue) {
+	b.states[key] = common.NewBaseState(base.GenesisHeight, key, v, nil, nil)
+}
+
+func (b *testSuffrageBond) getState(key string) (base.State, bool, error) {
+	st, found := b.states[key]
+
+	return st, found, nil
+}
+
+func (b *testSuffrageBond) setBalance(amount int64) {
+	b.set(statecurrency.BalanceStateKey(b.account, b.cid),
+		statecurrency.NewBalanceStateValue(types.NewAmount(common.NewBig(amount), b.cid)))
+}
+
+func (b *testSuffrageBond) setBond(amount int64) {
+	b.set(statecurrency.SuffrageBondStateKey(b.node),
+		statecurrency.NewSuffrageBondStateValue(b.account, types.NewAmount(common.NewBig(amount), b.cid)))
+}
+
+func (b *testSuffrageBond) setSuffrage() {
+	b.set(isaac.SuffrageStateKey, isaac.NewSuffrageNodesStateValue(base.GenesisHeight, []base.SuffrageNodeStateValue{
+		isaac.NewSuffrageNodeStateValue(isaac.NewNode(b.priv.Publickey(), b.node), base.GenesisHeight),
+	}))
+}
+
+func (b *testSuffrageBond) setCandidate(deadline base.Height) {
+	b.set(isaac.SuffrageCandidateStateKey, isaac.NewSuffrageCandidatesStateValue([]base.SuffrageCandidateStateValue{
+		isaac.NewSuffrageCandidateStateValue(isaac.NewNode(b.priv.Publickey(), b.node), base.GenesisHeight, deadline),
+	}))
+}
+
+func (b *testSuffrageBond) disjoin(priv base.Privatekey) SuffrageDisjoin {
+	op := NewSuffrageDisjoin(NewSuffrageDisjoinFact([]byte("token"), b.node, base.GenesisHeight))
+
+	if err := op.NodeSign(priv, testBondNetworkID, b.node); err != nil {
+		b.t.Fatal(err)
+	}
+
+	return op
+}
+
+// balances returns the added or deducted amounts of balance by account.
+func (b *testSuffrageBond) balances(values []base.StateMergeValue) map[string]int64 {
+	m := map[string]int64{}
+
+	for i := range values {
+		switch t := values[i].Value().(type) {
+		case statecurrency.AddBalanceStateValue:
+			m[values[i].Key()] += t.Amount.Big().Int64()
+		case statecurrency.DeductBalanceStateValue:
+			m[values[i].Key()] -= t.Amount.Big().Int64()
+		}
+	}
+
+	return m
+}
+
+func (b *testSuffrageBond) bond(values []base.StateMergeValue) (statecurrency.SuffrageBondStateValue, bool) {
+	for i := range values {
+		if values[i].Key() != statecurrency.SuffrageBondStateKey(b.node) {
+			continue
+		}
+
+		bond, ok := values[i].Value().(statecurrency.SuffrageBondStateValue)
+
+		return bond, ok
+	}
+
+	return statecurrency.SuffrageBondStateValue{}, false
+}
+
+func (b *testSuffrageBond) checkReleased(values []base.StateMergeValue, expected map[string]int64) {
+	b.t.Helper()
+
+	balances := b.balances(values)
+	if len(balances) != len(expected) {
+		b.t.Fatalf("unexpected balances, %v", balances)
+	}
+
+	for k := range expected {
+		if balances[k] != expected[k] {
+			b.t.Fatalf("unexpected balance of %q, %d != %d", k, balances[k], expected[k])
+		}
+	}
+
+	switch bond, found := b.bond(values); {
+	case !found:
+		b.t.Fatal("bond not released")
+	case !bond.Amount.Big().IsZero():
+		b.t.Fatalf("bond not released, %v", bond.Amount.Big())
+	}
+}
+
+func TestBondSuffrageCandidateLimiterRule(t *testing.T) {
+	b := newTestSuffrageBond(t)
+
+	if err := b.rule(2500).IsValid(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.rule(SlashRateBase + 1).IsValid(nil); err == nil {
+		t.Fatal("expected error for slash rate over base")
+	}
+
+	if err := NewBondSuffrageCandidateLimiterRule(3, b.cid, common.NewBig(100), 1, nil).IsValid(nil); err == nil {
+		t.Fatal("expected error for slash without treasury")
+	}
+
+	for _, i := range []struct {
+		amount   int64
+		rate     uint64
+		expected int64
+	}{
+		{amount: 100, rate: 0, expected: 0},
+		{amount: 100, rate: 2500, expected: 25},
+		{amount: 99, rate: 2500, expected: 24}, // NOTE rounded down
+		{amount: 100, rate: SlashRateBase, expected: 100},
+	} {
+		if s := b.rule(i.rate).Slash(common.NewBig(i.amount)); s.Int64() != i.expected {
+			t.Fatalf("unexpected slash of %d by %d, %v != %d", i.amount, i.rate, s, i.expected)
+		}
+	}
+}
+
+func TestSuffrageCandidateProcessorBond(t *testing.T) {
+	newOp := func(b *testSuffrageBond) SuffrageCandidate {
+		return NewSuffrageCandidate(NewSuffrageCandidateFact([]byte("token"), b.node, b.priv.Publickey()))
+	}
+
+	t.Run("bond", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setBalance(150)
+
+		p, err := NewSuffrageCandidateProcessor(base.Height(10), b.getState, nil, nil, 3, b.rule(2500))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		op := newOp(b)
+
+		switch _, reasonerr, err := p.PreProcess(context.Background(), op, b.getState); {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr != nil:
+			t.Fatal(reasonerr)
+		}
+
+		values, reasonerr, err := p.Process(context.Background(), op, b.getState)
+
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr != nil:
+			t.Fatal(reasonerr)
+		}
+
+		balances := b.balances(values)
+		if len(balances) != 1 || balances[statecurrency.BalanceStateKey(b.account, b.cid)] != -100 {
+			t.Fatalf("unexpected balances, %v", balances)
+		}
+
+		switch bond, found := b.bond(values); {
+		case !found:
+			t.Fatal("bond not found")
+		case !bond.Account.Equal(b.account), bond.Amount.Big().Int64() != 100, bond.Amount.Currency() != b.cid:
+			t.Fatalf("unexpected bond, %v %v", bond.Account, bond.Amount)
+		}
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setBalance(50)
+
+		p, err := NewSuffrageCandidateProcessor(base.Height(10), b.getState, nil, nil, 3, b.rule(2500))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch _, reasonerr, err := p.PreProcess(context.Background(), newOp(b), b.getState); {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr == nil:
+			t.Fatal("expected reason error")
+		}
+	})
+
+	t.Run("already bonded", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setBond(100)
+
+		p, err := NewSuffrageCandidateProcessor(base.Height(10), b.getState, nil, nil, 3, b.rule(2500))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		op := newOp(b)
+
+		if _, reasonerr, err := p.PreProcess(context.Background(), op, b.getState); err != nil || reasonerr != nil {
+			t.Fatal(err, reasonerr)
+		}
+
+		values, reasonerr, err := p.Process(context.Background(), op, b.getState)
+
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr != nil:
+			t.Fatal(reasonerr)
+		case len(b.balances(values)) > 0:
+			t.Fatalf("unexpected balances, %v", b.balances(values))
+		}
+	})
+}
+
+func TestSuffrageDisjoinProcessorBond(t *testing.T) {
+	process := func(b *testSuffrageBond, op SuffrageDisjoin) ([]base.StateMergeValue, base.OperationProcessReasonError) {
+		t.Helper()
+
+		p, err := NewSuffrageDisjoinProcessor(base.Height(10), b.getState, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch _, reasonerr, err := p.PreProcess(context.Background(), op, b.getState); {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr != nil:
+			return nil, reasonerr
+		}
+
+		values, reasonerr, err := p.Process(context.Background(), op, b.getState)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return values, reasonerr
+	}
+
+	t.Run("release by disjoin", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setSuffrage()
+		b.setBond(100)
+
+		values, reasonerr := process(b, b.disjoin(b.priv))
+		if reasonerr != nil {
+			t.Fatal(reasonerr)
+		}
+
+		b.checkReleased(values, map[string]int64{statecurrency.BalanceStateKey(b.account, b.cid): 100})
+	})
+
+	t.Run("withdraw after expiry", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.set(isaac.SuffrageStateKey, isaac.NewSuffrageNodesStateValue(base.GenesisHeight, nil))
+		b.setCandidate(base.Height(5))
+		b.setBond(100)
+
+		values, reasonerr := process(b, b.disjoin(b.priv))
+		if reasonerr != nil {
+			t.Fatal(reasonerr)
+		}
+
+		b.checkReleased(values, map[string]int64{statecurrency.BalanceStateKey(b.account, b.cid): 100})
+	})
+
+	t.Run("withdraw before expiry", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.set(isaac.SuffrageStateKey, isaac.NewSuffrageNodesStateValue(base.GenesisHeight, nil))
+		b.setCandidate(base.Height(20))
+		b.setBond(100)
+
+		if _, reasonerr := process(b, b.disjoin(b.priv)); reasonerr == nil {
+			t.Fatal("expected reason error")
+		}
+	})
+
+	t.Run("withdraw by unknown key", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.set(isaac.SuffrageStateKey, isaac.NewSuffrageNodesStateValue(base.GenesisHeight, nil))
+		b.setBond(100)
+
+		if _, reasonerr := process(b, b.disjoin(types.NewMEPrivatekey())); reasonerr == nil {
+			t.Fatal("expected reason error")
+		}
+	})
+}
+
+func TestSuffrageExpelProcessorSlash(t *testing.T) {
+	process := func(b *testSuffrageBond, rule base.SuffrageCandidateLimiterRule) []base.StateMergeValue {
+		t.Helper()
+
+		height := base.Height(10)
+
+		p, err := NewSuffrageExpelProcessor(height, b.getState, nil, nil, rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		op := isaac.NewSuffrageExpelOperation(isaac.NewSuffrageExpelFact(b.node, height, height+1, "test"))
+
+		switch _, reasonerr, err := p.PreProcess(context.Background(), op, b.getState); {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr != nil:
+			t.Fatal(reasonerr)
+		}
+
+		values, reasonerr, err := p.Process(context.Background(), op, b.getState)
+
+		switch {
+		case err != nil:
+			t.Fatal(err)
+		case reasonerr != nil:
+			t.Fatal(reasonerr)
+		}
+
+		return values
+	}
+
+	t.Run("slash to treasury", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setSuffrage()
+		b.setBond(100)
+
+		b.checkReleased(process(b, b.rule(2500)), map[string]int64{
+			statecurrency.BalanceStateKey(b.account, b.cid):  75,
+			statecurrency.BalanceStateKey(b.treasury, b.cid): 25,
+		})
+	})
+
+	t.Run("missing treasury", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setSuffrage()
+		b.setBond(100)
+
+		delete(b.states, statecurrency.AccountStateKey(b.treasury))
+
+		// NOTE without treasury, the deposit is released without slash.
+		b.checkReleased(process(b, b.rule(2500)), map[string]int64{
+			statecurrency.BalanceStateKey(b.account, b.cid): 100,
+		})
+	})
+
+	t.Run("without bond", func(t *testing.T) {
+		b := newTestSuffrageBond(t)
+		b.setSuffrage()
+
+		values := process(b, b.rule(2500))
+
+		if _, found := b.bond(values); found {
+			t.Fatal("unexpected bond")
+		}
+
+		if len(b.balances(values)) > 0 {
+			t.Fatalf("unexpected balances, %v", b.balances(values))
+		}
+	})
+}
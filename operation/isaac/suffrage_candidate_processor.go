@@ -3,6 +3,7 @@ package isaacoperation
 import (
 	"context"
 	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
 	"github.com/ProtoconNet/mitum2/base"
 	"sort"
 	"strings"
@@ -18,6 +19,7 @@ type SuffrageCandidateProcessor struct {
 	suffrages      map[string]base.Node
 	existings      map[string]base.SuffrageCandidateStateValue
 	preprocessed   map[string]struct{} // revive:disable-line:nested-structs
+	rule           base.SuffrageCandidateLimiterRule
 	startheight    base.Height
 	deadlineheight base.Height
 }
@@ -28,6 +30,7 @@ func NewSuffrageCandidateProcessor(
 	newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
 	newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
 	lifespan base.Height,
+	rule base.SuffrageCandidateLimiterRule,
 ) (*SuffrageCandidateProcessor, error) {
 	e := util.StringError("create new SuffrageCandidateProcessor")
 
@@ -42,6 +45,7 @@ func NewSuffrageCandidateProcessor(
 		existings:              map[string]base.SuffrageCandidateStateValue{},
 		suffrages:              map[string]base.Node{},
 		preprocessed:           map[string]struct{}{},
+		rule:                   rule,
 		startheight:            height + 1,
 		deadlineheight:         height + 1 + lifespan,
 	}
@@ -86,6 +90,7 @@ func (p *SuffrageCandidateProcessor) Close() error {
 	clear(p.suffrages)
 	clear(p.existings)
 	clear(p.preprocessed)
+	p.rule = nil
 	p.startheight = base.NilHeight
 	p.deadlineheight = base.NilHeight
 
@@ -117,6 +122,13 @@ func (p *SuffrageCandidateProcessor) PreProcess(
 		return ctx, base.NewBaseOperationProcessReasonError("already candidate up to, %d", record.Deadline()), nil
 	}
 
+	switch reasonerr, err := p.checkRule(fact, getStateFunc); {
+	case err != nil:
+		return ctx, nil, e.Wrap(err)
+	case reasonerr != nil:
+		return ctx, reasonerr, nil
+	}
+
 	switch reasonerr, err := p.PreProcessConstraintFunc(ctx, op, getStateFunc); {
 	case err != nil:
 		return ctx, nil, e.Wrap(err)
@@ -124,6 +136,16 @@ func (p *SuffrageCandidateProcessor) PreProcess(
 		return ctx, reasonerr, nil
 	}
 
+	if _, ok := p.rule.(BondSuffrageCandidateLimiterRule); ok {
+		i, ok := preprocessSuffrageBond(ctx, fact.Address())
+		if !ok {
+			return ctx, base.NewBaseOperationProcessReasonError(
+				"bond of candidate already preprocessed, %v", fact.Address()), nil
+		}
+
+		ctx = i //revive:disable-line:modifies-parameter
+	}
+
 	return ctx, nil, nil
 }
 
@@ -147,7 +169,7 @@ func (p *SuffrageCandidateProcessor) Process(ctx context.Context, op base.Operat
 		p.deadlineheight,
 	)
 
-	return []base.StateMergeValue{
+	values := []base.StateMergeValue{
 		common.NewBaseStateMergeValue(
 			isaac.SuffrageCandidateStateKey,
 			isaac.NewSuffrageCandidatesStateValue([]base.SuffrageCandidateStateValue{node}),
@@ -155,7 +177,55 @@ func (p *SuffrageCandidateProcessor) Process(ctx context.Context, op base.Operat
 				return NewSuffrageCandidatesStateValueMerger(height, st)
 			},
 		),
-	}, nil, nil
+	}
+
+	if rule, ok := p.rule.(BondSuffrageCandidateLimiterRule); ok {
+		switch _, found, err := SuffrageBond(fact.Address(), getStateFunc); {
+		case err != nil:
+			return nil, nil, e.Wrap(err)
+		case !found:
+			// NOTE the balance can be changed by the other operations after
+			// PreProcess.
+			account, reasonerr, err := checkSuffrageCandidateBalance(
+				fact.Publickey(), rule.Currency(), rule.Deposit(), getStateFunc)
+
+			switch {
+			case err != nil:
+				return nil, nil, e.Wrap(err)
+			case reasonerr != nil:
+				return nil, reasonerr, nil
+			}
+
+			values = append(values, bondSuffrageDepositMergeValues(
+				fact.Address(), account, types.NewAmount(rule.Deposit(), rule.Currency()))...)
+		}
+	}
+
+	return values, nil, nil
+}
+
+func (p *SuffrageCandidateProcessor) checkRule(
+	fact SuffrageCandidateFact, getStateFunc base.GetStateFunc,
+) (base.OperationProcessReasonError, error) {
+	switch rule := p.rule.(type) {
+	case BalanceSuffrageCandidateLimiterRule:
+		return rule.CheckCandidate(fact.Publickey(), getStateFunc)
+	case BondSuffrageCandidateLimiterRule:
+		// NOTE the node, which already bonded, does not bond again.
+		switch _, found, err := SuffrageBond(fact.Address(), getStateFunc); {
+		case err != nil:
+			return nil, err
+		case found:
+			return nil, nil
+		}
+
+		_, reasonerr, err := checkSuffrageCandidateBalance(
+			fact.Publickey(), rule.Currency(), rule.Deposit(), getStateFunc)
+
+		return reasonerr, err
+	default:
+		return nil, nil
+	}
 }
 
 type SuffrageCandidatesStateValueMerger struct {
@@ -10,9 +10,14 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+// SuffrageDisjoinProcessor removes the node from suffrage and releases the
+// bonded deposit. The node, which is not in suffrage, can disjoin to withdraw
+// the bonded deposit after the candidacy expires; it should be signed by the
+// key of the deposit account.
 type SuffrageDisjoinProcessor struct {
 	*base.BaseOperationProcessor
 	suffrage     map[string]base.SuffrageNodeStateValue
+	candidates   map[string]base.SuffrageCandidateStateValue
 	preprocessed map[string]struct{} //revive:disable-line:nested-structs
 }
 
@@ -32,6 +37,7 @@ func NewSuffrageDisjoinProcessor(
 
 	p := &SuffrageDisjoinProcessor{
 		BaseOperationProcessor: b,
+		candidates:             map[string]base.SuffrageCandidateStateValue{},
 		preprocessed:           map[string]struct{}{},
 	}
 
@@ -53,6 +59,18 @@ func NewSuffrageDisjoinProcessor(
 		}
 	}
 
+	switch _, candidates, err := isaac.LastCandidatesFromState(height, getStateFunc); {
+	case err != nil:
+		return nil, e.Wrap(err)
+	case candidates == nil:
+	default:
+		for i := range candidates {
+			n := candidates[i]
+
+			p.candidates[n.Address().String()] = n
+		}
+	}
+
 	return p, nil
 }
 
@@ -62,6 +80,7 @@ func (p *SuffrageDisjoinProcessor) Close() error {
 	}
 
 	p.suffrage = nil
+	p.candidates = nil
 	p.preprocessed = nil
 
 	return nil
@@ -100,7 +119,19 @@ func (p *SuffrageDisjoinProcessor) PreProcess(ctx context.Context, op base.Opera
 
 	switch stv, found := p.suffrage[n.String()]; {
 	case !found:
-		return ctx, base.NewBaseOperationProcessReasonError("Not in suffrage, %q", n), nil
+		switch reasonerr, err := p.checkWithdrawBond(n, signer, getStateFunc); {
+		case err != nil:
+			return ctx, nil, e.Wrap(err)
+		case reasonerr != nil:
+			return ctx, reasonerr, nil
+		}
+
+		i, ok := preprocessSuffrageBond(ctx, n)
+		if !ok {
+			return ctx, base.NewBaseOperationProcessReasonError("Bond already preprocessed, %q", n), nil
+		}
+
+		ctx = i //revive:disable-line:modifies-parameter
 	case fact.Start() != stv.Start():
 		return ctx, base.NewBaseOperationProcessReasonError("Start does not match"), nil
 	case !signer.Equal(stv.Publickey()):
@@ -133,15 +164,63 @@ func (p *SuffrageDisjoinProcessor) Process(ctx context.Context, op base.Operatio
 
 	fact := op.Fact().(SuffrageDisjoinFact) //nolint:forcetypeassert //...
 
-	return []base.StateMergeValue{
-		common.NewBaseStateMergeValue(
+	var values []base.StateMergeValue
+
+	if _, found := p.suffrage[fact.Node().String()]; found {
+		values = append(values, common.NewBaseStateMergeValue(
 			isaac.SuffrageStateKey,
 			newSuffrageDisjoinNodeStateValue(fact.Node()),
 			func(height base.Height, st base.State) base.StateValueMerger {
 				return NewSuffrageJoinStateValueMerger(height, st)
 			},
-		),
-	}, nil, nil
+		))
+	}
+
+	switch bond, found, err := SuffrageBond(fact.Node(), getStateFunc); {
+	case err != nil:
+		return nil, nil, e.Wrap(err)
+	case found:
+		released, err := releaseSuffrageBondMergeValues(fact.Node(), bond, common.ZeroBig, nil)
+		if err != nil {
+			return nil, nil, e.Wrap(err)
+		}
+
+		values = append(values, released...)
+	}
+
+	if len(values) < 1 {
+		return nil, base.NewBaseOperationProcessReasonError("Empty bond, %q", fact.Node()), nil
+	}
+
+	return values, nil, nil
+}
+
+// checkWithdrawBond checks the node, which is not in suffrage, can withdraw
+// the bonded deposit.
+func (p *SuffrageDisjoinProcessor) checkWithdrawBond(
+	node base.Address, signer base.Publickey, getStateFunc base.GetStateFunc,
+) (base.OperationProcessReasonError, error) {
+	bond, found, err := SuffrageBond(node, getStateFunc)
+
+	switch {
+	case err != nil:
+		return nil, err
+	case !found:
+		return base.NewBaseOperationProcessReasonError("Not in suffrage, %q", node), nil
+	}
+
+	if c, found := p.candidates[node.String()]; found {
+		return base.NewBaseOperationProcessReasonError("Candidate not expired up to, %d", c.Deadline()), nil
+	}
+
+	switch account, err := SuffrageCandidateAccount(signer); {
+	case err != nil:
+		return nil, err
+	case !account.Equal(bond.Account):
+		return base.NewBaseOperationProcessReasonError("Not signed by deposit account key"), nil
+	}
+
+	return nil, nil
 }
 
 type suffrageDisjoinNodeStateValue struct {
@@ -3,6 +3,7 @@ package isaacoperation
 import (
 	"context"
 	"github.com/ProtoconNet/mitum-currency/v3/common"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/isaac"
 	"github.com/ProtoconNet/mitum2/util"
@@ -14,6 +15,7 @@ type SuffrageExpelProcessor struct {
 	*base.BaseOperationProcessor
 	sufstv       base.SuffrageNodesStateValue
 	suffrage     base.Suffrage
+	rule         base.SuffrageCandidateLimiterRule
 	preprocessed map[string]struct{} //revive:disable-line:nested-structs
 }
 
@@ -22,6 +24,7 @@ func NewSuffrageExpelProcessor(
 	getStateFunc base.GetStateFunc,
 	newPreProcessConstraintFunc base.NewOperationProcessorProcessFunc,
 	newProcessConstraintFunc base.NewOperationProcessorProcessFunc,
+	rule base.SuffrageCandidateLimiterRule,
 ) (*SuffrageExpelProcessor, error) {
 	e := util.StringError("create new SuffrageExpelProcessor")
 
@@ -33,6 +36,7 @@ func NewSuffrageExpelProcessor(
 
 	p := &SuffrageExpelProcessor{
 		BaseOperationProcessor: b,
+		rule:                   rule,
 		preprocessed:           map[string]struct{}{},
 	}
 
@@ -62,6 +66,7 @@ func (p *SuffrageExpelProcessor) Close() error {
 
 	p.sufstv = nil
 	p.suffrage = nil
+	p.rule = nil
 	p.preprocessed = nil
 
 	return nil
@@ -124,7 +129,7 @@ func (p *SuffrageExpelProcessor) Process(ctx context.Context, op base.Operation,
 
 	fact := op.Fact().(base.SuffrageExpelFact) //nolint:forcetypeassert //...
 
-	return []base.StateMergeValue{
+	values := []base.StateMergeValue{
 		common.NewBaseStateMergeValue(
 			isaac.SuffrageStateKey,
 			newSuffrageDisjoinNodeStateValue(fact.Node()),
@@ -132,5 +137,47 @@ func (p *SuffrageExpelProcessor) Process(ctx context.Context, op base.Operation,
 				return NewSuffrageJoinStateValueMerger(height, st)
 			},
 		),
-	}, nil, nil
+	}
+
+	released, err := p.slashBond(fact.Node(), getStateFunc)
+	if err != nil {
+		return nil, nil, e.Wrap(err)
+	}
+
+	values = append(values, released...)
+
+	return values, nil, nil
+}
+
+// slashBond releases the bonded deposit of expelled node; by the
+// BondSuffrageCandidateLimiterRule, the slash rate of deposit goes to the
+// treasury. If the treasury account is not found, the deposit is released
+// without slash; the expel should not be failed by the bond.
+func (p *SuffrageExpelProcessor) slashBond(node base.Address, getStateFunc base.GetStateFunc) (
+	[]base.StateMergeValue, error,
+) {
+	bond, found, err := SuffrageBond(node, getStateFunc)
+
+	switch {
+	case err != nil:
+		return nil, err
+	case !found:
+		return nil, nil
+	}
+
+	slash := common.ZeroBig
+
+	var treasury base.Address
+
+	if rule, ok := p.rule.(BondSuffrageCandidateLimiterRule); ok && rule.SlashRate() > 0 {
+		switch _, found, err := getStateFunc(statecurrency.AccountStateKey(rule.Treasury())); {
+		case err != nil:
+			return nil, err
+		case found:
+			treasury = rule.Treasury()
+			slash = rule.Slash(bond.Amount.Big())
+		}
+	}
+
+	return releaseSuffrageBondMergeValues(node, bond, slash, treasury)
 }
@@ -3,6 +3,9 @@ package isaacoperation
 import (
 	"math"
 
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	statecurrency "github.com/ProtoconNet/mitum-currency/v3/state/currency"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
@@ -12,6 +15,8 @@ import (
 var (
 	FixedSuffrageCandidateLimiterRuleHint    = hint.MustNewHint("currency-fixed-suffrage-candidate-limiter-rule-v0.0.1")
 	MajoritySuffrageCandidateLimiterRuleHint = hint.MustNewHint("currency-majority-suffrage-candidate-limiter-rule-v0.0.1")
+	BalanceSuffrageCandidateLimiterRuleHint  = hint.MustNewHint("currency-balance-suffrage-candidate-limiter-rule-v0.0.1")
+	BondSuffrageCandidateLimiterRuleHint     = hint.MustNewHint("currency-bond-suffrage-candidate-limiter-rule-v0.0.1")
 )
 
 type FixedSuffrageCandidateLimiterRule struct {
@@ -154,3 +159,246 @@ func NewCandidatesOfMajoritySuffrageCandidateLimiterRule(
 
 	return ns - s, nil
 }
+
+// BalanceSuffrageCandidateLimiterRule limits the number of new candidates like
+// FixedSuffrageCandidateLimiterRule, and the candidate node should have the
+// minimum balance in the currency. The balance is checked in the account of
+// candidate node publickey, which has the single key with weight 100 and
+// threshold 100.
+type BalanceSuffrageCandidateLimiterRule struct {
+	hint.BaseHinter
+	currency   types.CurrencyID
+	minBalance common.Big
+	limit      uint64
+}
+
+func NewBalanceSuffrageCandidateLimiterRule(
+	limit uint64, currency types.CurrencyID, minBalance common.Big,
+) BalanceSuffrageCandidateLimiterRule {
+	return BalanceSuffrageCandidateLimiterRule{
+		BaseHinter: hint.NewBaseHinter(BalanceSuffrageCandidateLimiterRuleHint),
+		limit:      limit,
+		currency:   currency,
+		minBalance: minBalance,
+	}
+}
+
+func NewBalanceSuffrageCandidateLimiter(rule BalanceSuffrageCandidateLimiterRule) base.SuffrageCandidateLimiter {
+	return func() (uint64, error) {
+		return rule.limit, nil
+	}
+}
+
+func (l BalanceSuffrageCandidateLimiterRule) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid BalanceSuffrageCandidateLimiterRule")
+
+	if err := l.BaseHinter.IsValid(BalanceSuffrageCandidateLimiterRuleHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := l.currency.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	if !l.minBalance.OverZero() {
+		return e.Errorf("min balance should be over zero")
+	}
+
+	return nil
+}
+
+func (l BalanceSuffrageCandidateLimiterRule) Limit() uint64 {
+	return l.limit
+}
+
+func (l BalanceSuffrageCandidateLimiterRule) Currency() types.CurrencyID {
+	return l.currency
+}
+
+func (l BalanceSuffrageCandidateLimiterRule) MinBalance() common.Big {
+	return l.minBalance
+}
+
+func (l BalanceSuffrageCandidateLimiterRule) HashBytes() []byte {
+	return util.ConcatBytesSlice(
+		l.Hint().Bytes(),
+		util.Uint64ToBytes(l.limit),
+		l.currency.Bytes(),
+		l.minBalance.Bytes(),
+	)
+}
+
+// CheckCandidate checks the balance of the account of candidate node
+// publickey.
+func (l BalanceSuffrageCandidateLimiterRule) CheckCandidate(
+	pub base.Publickey, getStateFunc base.GetStateFunc,
+) (base.OperationProcessReasonError, error) {
+	_, reasonerr, err := checkSuffrageCandidateBalance(pub, l.currency, l.minBalance, getStateFunc)
+
+	return reasonerr, err
+}
+
+// SlashRateBase is the base of slash rate; the slash rate is in basis points,
+// 10000 is the whole deposit.
+const SlashRateBase uint64 = 10000
+
+// BondSuffrageCandidateLimiterRule limits the number of new candidates like
+// FixedSuffrageCandidateLimiterRule, and the candidate node bonds the deposit
+// from the account of candidate node publickey. The deposit is locked until
+// the node disjoins or is expelled; when expelled, the slash rate of deposit
+// goes to the treasury account. The candidate, which expires without joining,
+// withdraws the deposit by disjoin.
+type BondSuffrageCandidateLimiterRule struct {
+	hint.BaseHinter
+	treasury  base.Address
+	currency  types.CurrencyID
+	deposit   common.Big
+	slashRate uint64
+	limit     uint64
+}
+
+func NewBondSuffrageCandidateLimiterRule(
+	limit uint64,
+	currency types.CurrencyID,
+	deposit common.Big,
+	slashRate uint64,
+	treasury base.Address,
+) BondSuffrageCandidateLimiterRule {
+	return BondSuffrageCandidateLimiterRule{
+		BaseHinter: hint.NewBaseHinter(BondSuffrageCandidateLimiterRuleHint),
+		limit:      limit,
+		currency:   currency,
+		deposit:    deposit,
+		slashRate:  slashRate,
+		treasury:   treasury,
+	}
+}
+
+func NewBondSuffrageCandidateLimiter(rule BondSuffrageCandidateLimiterRule) base.SuffrageCandidateLimiter {
+	return func() (uint64, error) {
+		return rule.limit, nil
+	}
+}
+
+func (l BondSuffrageCandidateLimiterRule) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("invalid BondSuffrageCandidateLimiterRule")
+
+	if err := l.BaseHinter.IsValid(BondSuffrageCandidateLimiterRuleHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := l.currency.IsValid(nil); err != nil {
+		return e.Wrap(err)
+	}
+
+	if !l.deposit.OverZero() {
+		return e.Errorf("deposit should be over zero")
+	}
+
+	if l.slashRate > SlashRateBase {
+		return e.Errorf("invalid slash rate; should be inside 0 <= %d <= %d", l.slashRate, SlashRateBase)
+	}
+
+	switch {
+	case l.treasury != nil:
+		if err := l.treasury.IsValid(nil); err != nil {
+			return e.Wrap(err)
+		}
+	case l.slashRate > 0:
+		return e.Errorf("empty treasury for slash rate, %d", l.slashRate)
+	}
+
+	return nil
+}
+
+func (l BondSuffrageCandidateLimiterRule) Limit() uint64 {
+	return l.limit
+}
+
+func (l BondSuffrageCandidateLimiterRule) Currency() types.CurrencyID {
+	return l.currency
+}
+
+func (l BondSuffrageCandidateLimiterRule) Deposit() common.Big {
+	return l.deposit
+}
+
+// SlashRate returns the slash rate in basis points.
+func (l BondSuffrageCandidateLimiterRule) SlashRate() uint64 {
+	return l.slashRate
+}
+
+// Slash returns the slashed amount of the bonded amount by the slash rate; the
+// remainder is rounded down.
+func (l BondSuffrageCandidateLimiterRule) Slash(amount common.Big) common.Big {
+	if l.slashRate < 1 {
+		return common.ZeroBig
+	}
+
+	return amount.Mul(common.NewBig(int64(l.slashRate))).Div(common.NewBig(int64(SlashRateBase)))
+}
+
+func (l BondSuffrageCandidateLimiterRule) Treasury() base.Address {
+	return l.treasury
+}
+
+func (l BondSuffrageCandidateLimiterRule) HashBytes() []byte {
+	var treasury []byte
+	if l.treasury != nil {
+		treasury = l.treasury.Bytes()
+	}
+
+	return util.ConcatBytesSlice(
+		l.Hint().Bytes(),
+		util.Uint64ToBytes(l.limit),
+		l.currency.Bytes(),
+		l.deposit.Bytes(),
+		util.Uint64ToBytes(l.slashRate),
+		treasury,
+	)
+}
+
+// SuffrageCandidateAccount returns the address of the account of candidate
+// node publickey, which has the single key with weight 100 and threshold 100.
+func SuffrageCandidateAccount(pub base.Publickey) (base.Address, error) {
+	key, err := types.NewBaseAccountKey(pub, 100) //nolint:gomnd //...
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := types.NewBaseAccountKeys([]types.AccountKey{key}, 100) //nolint:gomnd //...
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewAddressFromKeys(keys)
+}
+
+func checkSuffrageCandidateBalance(
+	pub base.Publickey, cid types.CurrencyID, min common.Big, getStateFunc base.GetStateFunc,
+) (base.Address, base.OperationProcessReasonError, error) {
+	ad, err := SuffrageCandidateAccount(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch st, found, err := getStateFunc(statecurrency.BalanceStateKey(ad, cid)); {
+	case err != nil:
+		return nil, nil, err
+	case !found:
+		return nil, base.NewBaseOperationProcessReasonError(
+			"balance of candidate account not found, %v in %v", ad, cid), nil
+	default:
+		am, err := statecurrency.StateBalanceValue(st)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if am.Big().Compare(min) < 0 {
+			return nil, base.NewBaseOperationProcessReasonError(
+				"insufficient balance of candidate account, %v; %v < %v", ad, am.Big(), min), nil
+		}
+	}
+
+	return ad, nil, nil
+}
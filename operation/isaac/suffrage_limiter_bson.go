@@ -1,7 +1,10 @@
 package isaacoperation
 
 import (
+	"github.com/ProtoconNet/mitum-currency/v3/common"
 	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"go.mongodb.org/mongo-driver/bson"
@@ -82,3 +85,112 @@ func (l *MajoritySuffrageCandidateLimiterRule) DecodeBSON(b []byte, enc *bsonenc
 
 	return nil
 }
+
+func (l BalanceSuffrageCandidateLimiterRule) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":       l.Hint().String(),
+			"currency":    l.currency.String(),
+			"min_balance": l.minBalance.String(),
+			"limit":       l.limit,
+		},
+	)
+}
+
+type BalanceSuffrageCandidateLimiterRuleBSONUnMarshaler struct {
+	Hint       string `bson:"_hint"`
+	Currency   string `bson:"currency"`
+	MinBalance string `bson:"min_balance"`
+	Limit      uint64 `bson:"limit"`
+}
+
+func (l *BalanceSuffrageCandidateLimiterRule) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("Decode bson of BalanceSuffrageCandidateLimiterRule")
+
+	var u BalanceSuffrageCandidateLimiterRuleBSONUnMarshaler
+
+	err := enc.Unmarshal(b, &u)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	l.BaseHinter = hint.NewBaseHinter(ht)
+
+	big, err := common.NewBigFromString(u.MinBalance)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	l.currency = types.CurrencyID(u.Currency)
+	l.minBalance = big
+	l.limit = u.Limit
+
+	return nil
+}
+
+func (l BondSuffrageCandidateLimiterRule) MarshalBSON() ([]byte, error) {
+	m := bson.M{
+		"_hint":      l.Hint().String(),
+		"currency":   l.currency.String(),
+		"deposit":    l.deposit.String(),
+		"slash_rate": l.slashRate,
+		"limit":      l.limit,
+	}
+
+	if l.treasury != nil {
+		m["treasury"] = l.treasury.String()
+	}
+
+	return bsonenc.Marshal(m)
+}
+
+type BondSuffrageCandidateLimiterRuleBSONUnMarshaler struct {
+	Hint      string `bson:"_hint"`
+	Treasury  string `bson:"treasury"`
+	Currency  string `bson:"currency"`
+	Deposit   string `bson:"deposit"`
+	SlashRate uint64 `bson:"slash_rate"`
+	Limit     uint64 `bson:"limit"`
+}
+
+func (l *BondSuffrageCandidateLimiterRule) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("Decode bson of BondSuffrageCandidateLimiterRule")
+
+	var u BondSuffrageCandidateLimiterRuleBSONUnMarshaler
+
+	err := enc.Unmarshal(b, &u)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	l.BaseHinter = hint.NewBaseHinter(ht)
+
+	if len(u.Treasury) > 0 {
+		treasury, err := base.DecodeAddress(u.Treasury, enc)
+		if err != nil {
+			return e.Wrap(err)
+		}
+
+		l.treasury = treasury
+	}
+
+	deposit, err := common.NewBigFromString(u.Deposit)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	l.currency = types.CurrencyID(u.Currency)
+	l.deposit = deposit
+	l.slashRate = u.SlashRate
+	l.limit = u.Limit
+
+	return nil
+}
@@ -1,7 +1,11 @@
 package isaacoperation
 
 import (
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"github.com/pkg/errors"
 )
@@ -68,3 +72,93 @@ func (l *MajoritySuffrageCandidateLimiterRule) UnmarshalJSON(b []byte) error {
 
 	return nil
 }
+
+type balanceSuffrageCandidateLimiterRuleJSONMarshaler struct {
+	hint.BaseHinter
+	Currency   types.CurrencyID `json:"currency"`
+	MinBalance common.Big       `json:"min_balance"`
+	Limit      uint64           `json:"limit"`
+}
+
+type balanceSuffrageCandidateLimiterRuleJSONUnmarshaler struct {
+	Currency   string     `json:"currency"`
+	MinBalance common.Big `json:"min_balance"`
+	Limit      uint64     `json:"limit"`
+}
+
+func (l BalanceSuffrageCandidateLimiterRule) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(balanceSuffrageCandidateLimiterRuleJSONMarshaler{
+		BaseHinter: l.BaseHinter,
+		Currency:   l.currency,
+		MinBalance: l.minBalance,
+		Limit:      l.limit,
+	})
+}
+
+func (l *BalanceSuffrageCandidateLimiterRule) UnmarshalJSON(b []byte) error {
+	var u balanceSuffrageCandidateLimiterRuleJSONUnmarshaler
+
+	if err := util.UnmarshalJSON(b, &u); err != nil {
+		return errors.WithMessage(err, "unmarshal BalanceSuffrageCandidateLimiterRule")
+	}
+
+	l.currency = types.CurrencyID(u.Currency)
+	l.minBalance = u.MinBalance
+	l.limit = u.Limit
+
+	return nil
+}
+
+type bondSuffrageCandidateLimiterRuleJSONMarshaler struct {
+	hint.BaseHinter
+	Treasury  base.Address     `json:"treasury,omitempty"`
+	Currency  types.CurrencyID `json:"currency"`
+	Deposit   common.Big       `json:"deposit"`
+	SlashRate uint64           `json:"slash_rate"`
+	Limit     uint64           `json:"limit"`
+}
+
+type bondSuffrageCandidateLimiterRuleJSONUnmarshaler struct {
+	Treasury  string     `json:"treasury"`
+	Currency  string     `json:"currency"`
+	Deposit   common.Big `json:"deposit"`
+	SlashRate uint64     `json:"slash_rate"`
+	Limit     uint64     `json:"limit"`
+}
+
+func (l BondSuffrageCandidateLimiterRule) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(bondSuffrageCandidateLimiterRuleJSONMarshaler{
+		BaseHinter: l.BaseHinter,
+		Treasury:   l.treasury,
+		Currency:   l.currency,
+		Deposit:    l.deposit,
+		SlashRate:  l.slashRate,
+		Limit:      l.limit,
+	})
+}
+
+func (l *BondSuffrageCandidateLimiterRule) DecodeJSON(b []byte, enc encoder.Encoder) error {
+	e := util.StringError("decode json of BondSuffrageCandidateLimiterRule")
+
+	var u bondSuffrageCandidateLimiterRuleJSONUnmarshaler
+
+	if err := enc.Unmarshal(b, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	if len(u.Treasury) > 0 {
+		treasury, err := base.DecodeAddress(u.Treasury, enc)
+		if err != nil {
+			return e.Wrap(err)
+		}
+
+		l.treasury = treasury
+	}
+
+	l.currency = types.CurrencyID(u.Currency)
+	l.deposit = u.Deposit
+	l.slashRate = u.SlashRate
+	l.limit = u.Limit
+
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
+	"github.com/ProtoconNet/mitum-currency/v3/metrics"
 	"github.com/ProtoconNet/mitum-currency/v3/operation/currency"
 	"github.com/ProtoconNet/mitum-currency/v3/operation/extension"
 	"github.com/ProtoconNet/mitum-currency/v3/types"
@@ -203,6 +205,9 @@ func (opr *OperationProcessor) PreProcess(ctx context.Context, op base.Operation
 		sp = i
 	}
 
+	started := time.Now()
+	defer metrics.OperationPreProcess.Since(started, op.Hint().String())
+
 	switch _, reasonErr, err := sp.PreProcess(ctx, op, getStateFunc); {
 	case err != nil:
 		return ctx, nil, e.Wrap(err)
@@ -230,6 +235,9 @@ func (opr *OperationProcessor) Process(ctx context.Context, op base.Operation, g
 		sp = i
 	}
 
+	started := time.Now()
+	defer metrics.OperationProcess.Since(started, op.Hint().String())
+
 	stateMergeValues, reasonErr, err := sp.Process(ctx, op, getStateFunc)
 	return stateMergeValues, reasonErr, err
 }
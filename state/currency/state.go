@@ -11,15 +11,17 @@ import (
 )
 
 var (
-	AccountStateValueHint = hint.MustNewHint("account-state-value-v0.0.1")
-	BalanceStateValueHint = hint.MustNewHint("balance-state-value-v0.0.1")
-	DesignStateValueHint  = hint.MustNewHint("currency-design-state-value-v0.0.1")
+	AccountStateValueHint      = hint.MustNewHint("account-state-value-v0.0.1")
+	BalanceStateValueHint      = hint.MustNewHint("balance-state-value-v0.0.1")
+	DesignStateValueHint       = hint.MustNewHint("currency-design-state-value-v0.0.1")
+	SuffrageBondStateValueHint = hint.MustNewHint("currency-suffrage-bond-state-value-v0.0.1")
 )
 
 var (
-	AccountStateKeySuffix = ":account"
-	BalanceStateKeySuffix = ":balance"
-	DesignStateKeyPrefix  = "currencydesign:"
+	AccountStateKeySuffix      = ":account"
+	BalanceStateKeySuffix      = ":balance"
+	DesignStateKeyPrefix       = "currencydesign:"
+	SuffrageBondStateKeySuffix = ":suffragebond"
 )
 
 type AccountStateValue struct {
@@ -249,3 +251,63 @@ func IsDesignStateKey(key string) bool {
 func DesignStateKey(cid types.CurrencyID) string {
 	return fmt.Sprintf("%s%s", DesignStateKeyPrefix, cid)
 }
+
+// SuffrageBondStateValue is the deposit of suffrage candidate node, which is
+// bonded from the account. The zero amount means the deposit is released.
+type SuffrageBondStateValue struct {
+	hint.BaseHinter
+	Account base.Address
+	Amount  types.Amount
+}
+
+func NewSuffrageBondStateValue(account base.Address, amount types.Amount) SuffrageBondStateValue {
+	return SuffrageBondStateValue{
+		BaseHinter: hint.NewBaseHinter(SuffrageBondStateValueHint),
+		Account:    account,
+		Amount:     amount,
+	}
+}
+
+func (b SuffrageBondStateValue) Hint() hint.Hint {
+	return b.BaseHinter.Hint()
+}
+
+func (b SuffrageBondStateValue) IsValid([]byte) error {
+	e := util.ErrInvalid.Errorf("Invalid SuffrageBondStateValue")
+
+	if err := b.BaseHinter.IsValid(SuffrageBondStateValueHint.Type().Bytes()); err != nil {
+		return e.Wrap(err)
+	}
+
+	if err := util.CheckIsValiders(nil, false, b.Account, b.Amount); err != nil {
+		return e.Wrap(err)
+	}
+
+	return nil
+}
+
+func (b SuffrageBondStateValue) HashBytes() []byte {
+	return util.ConcatBytesSlice(b.Account.Bytes(), b.Amount.Bytes())
+}
+
+func StateSuffrageBondValue(st base.State) (SuffrageBondStateValue, error) {
+	v := st.Value()
+	if v == nil {
+		return SuffrageBondStateValue{}, util.ErrNotFound.Errorf("suffrage bond not found in State")
+	}
+
+	b, ok := v.(SuffrageBondStateValue)
+	if !ok {
+		return SuffrageBondStateValue{}, errors.Errorf("invalid suffrage bond value found, %T", v)
+	}
+
+	return b, nil
+}
+
+func SuffrageBondStateKey(node base.Address) string {
+	return fmt.Sprintf("%s%s", node.String(), SuffrageBondStateKeySuffix)
+}
+
+func IsSuffrageBondStateKey(key string) bool {
+	return strings.HasSuffix(key, SuffrageBondStateKeySuffix)
+}
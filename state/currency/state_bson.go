@@ -3,6 +3,7 @@ package currency
 import (
 	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
 	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
 	"go.mongodb.org/mongo-driver/bson"
@@ -122,3 +123,49 @@ func (c *DesignStateValue) DecodeBSON(b []byte, enc *bsonenc.Encoder) error {
 
 	return nil
 }
+
+func (b SuffrageBondStateValue) MarshalBSON() ([]byte, error) {
+	return bsonenc.Marshal(
+		bson.M{
+			"_hint":   b.Hint().String(),
+			"account": b.Account,
+			"amount":  b.Amount,
+		},
+	)
+}
+
+type SuffrageBondStateValueBSONUnmarshaler struct {
+	Hint    string   `bson:"_hint"`
+	Account string   `bson:"account"`
+	Amount  bson.Raw `bson:"amount"`
+}
+
+func (b *SuffrageBondStateValue) DecodeBSON(v []byte, enc *bsonenc.Encoder) error {
+	e := util.StringError("Decode SuffrageBondStateValue")
+
+	var u SuffrageBondStateValueBSONUnmarshaler
+	if err := enc.Unmarshal(v, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	ht, err := hint.ParseHint(u.Hint)
+	if err != nil {
+		return e.Wrap(err)
+	}
+	b.BaseHinter = hint.NewBaseHinter(ht)
+
+	account, err := base.DecodeAddress(u.Account, enc)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	var am types.Amount
+	if err := am.DecodeBSON(u.Amount, enc); err != nil {
+		return e.Wrap(err)
+	}
+
+	b.Account = account
+	b.Amount = am
+
+	return nil
+}
@@ -3,6 +3,7 @@ package currency
 import (
 	"encoding/json"
 	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/encoder"
 	"github.com/ProtoconNet/mitum2/util/hint"
@@ -112,3 +113,47 @@ func (c *DesignStateValue) DecodeJSON(b []byte, enc encoder.Encoder) error {
 
 	return nil
 }
+
+type SuffrageBondStateValueJSONMarshaler struct {
+	hint.BaseHinter
+	Account base.Address `json:"account"`
+	Amount  types.Amount `json:"amount"`
+}
+
+func (b SuffrageBondStateValue) MarshalJSON() ([]byte, error) {
+	return util.MarshalJSON(SuffrageBondStateValueJSONMarshaler{
+		BaseHinter: b.BaseHinter,
+		Account:    b.Account,
+		Amount:     b.Amount,
+	})
+}
+
+type SuffrageBondStateValueJSONUnmarshaler struct {
+	Account string          `json:"account"`
+	AM      json.RawMessage `json:"amount"`
+}
+
+func (b *SuffrageBondStateValue) DecodeJSON(v []byte, enc encoder.Encoder) error {
+	e := util.StringError("Decode SuffrageBondStateValue")
+
+	var u SuffrageBondStateValueJSONUnmarshaler
+	if err := enc.Unmarshal(v, &u); err != nil {
+		return e.Wrap(err)
+	}
+
+	account, err := base.DecodeAddress(u.Account, enc)
+	if err != nil {
+		return e.Wrap(err)
+	}
+
+	var am types.Amount
+
+	if err := am.DecodeJSON(u.AM, enc); err != nil {
+		return e.Wrap(err)
+	}
+
+	b.Account = account
+	b.Amount = am
+
+	return nil
+}
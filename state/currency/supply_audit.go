@@ -0,0 +1,162 @@
+package currency
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/pkg/errors"
+)
+
+// SupplyAudit reconciles the sum of balances of each currency with the total
+// supply of currency design. The states should be added by height order; the
+// later state of same key replaces the former.
+type SupplyAudit struct {
+	designs  map[types.CurrencyID]types.CurrencyDesign
+	balances map[types.CurrencyID]map[string]common.Big
+}
+
+func NewSupplyAudit() *SupplyAudit {
+	return &SupplyAudit{
+		designs:  map[types.CurrencyID]types.CurrencyDesign{},
+		balances: map[types.CurrencyID]map[string]common.Big{},
+	}
+}
+
+// AddState adds the currency design, balance and suffrage bond states; the
+// other states are ignored.
+func (sa *SupplyAudit) AddState(st base.State) error {
+	switch key := st.Key(); {
+	case IsDesignStateKey(key):
+		de, err := GetDesignFromState(st)
+		if err != nil {
+			return errors.WithMessagef(err, "currency design state, %q", key)
+		}
+
+		sa.SetDesign(de)
+	case IsBalanceStateKey(key):
+		am, err := StateBalanceValue(st)
+		if err != nil {
+			return errors.WithMessagef(err, "balance state, %q", key)
+		}
+
+		address := strings.TrimSuffix(key, "-"+am.Currency().String()+BalanceStateKeySuffix)
+
+		sa.SetBalance(address, am)
+	case IsSuffrageBondStateKey(key):
+		// NOTE the bonded deposit is counted as the balance of bond.
+		bond, err := StateSuffrageBondValue(st)
+		if err != nil {
+			return errors.WithMessagef(err, "suffrage bond state, %q", key)
+		}
+
+		sa.SetBalance(key, bond.Amount)
+	}
+
+	return nil
+}
+
+func (sa *SupplyAudit) SetDesign(de types.CurrencyDesign) {
+	sa.designs[de.Currency()] = de
+}
+
+func (sa *SupplyAudit) SetBalance(address string, am types.Amount) {
+	cid := am.Currency()
+
+	if _, found := sa.balances[cid]; !found {
+		sa.balances[cid] = map[string]common.Big{}
+	}
+
+	sa.balances[cid][address] = am.Big()
+}
+
+// Results returns the audit results of currencies by currency id order.
+func (sa *SupplyAudit) Results() []SupplyAuditResult {
+	cids := map[types.CurrencyID]struct{}{}
+	for cid := range sa.designs {
+		cids[cid] = struct{}{}
+	}
+
+	for cid := range sa.balances {
+		cids[cid] = struct{}{}
+	}
+
+	results := make([]SupplyAuditResult, 0, len(cids))
+	for cid := range cids {
+		results = append(results, sa.result(cid))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Currency < results[j].Currency
+	})
+
+	return results
+}
+
+func (sa *SupplyAudit) result(cid types.CurrencyID) SupplyAuditResult {
+	r := SupplyAuditResult{
+		Currency:    cid,
+		TotalSupply: common.ZeroBig,
+		BalanceSum:  common.ZeroBig,
+	}
+
+	if de, found := sa.designs[cid]; found {
+		r.Designed = true
+		r.TotalSupply = de.TotalSupply()
+	}
+
+	accounts := make([]SupplyAuditAccount, 0, len(sa.balances[cid]))
+
+	for address, b := range sa.balances[cid] {
+		r.BalanceSum = r.BalanceSum.Add(b)
+
+		if !b.OverZero() {
+			if b.Compare(common.ZeroBig) < 0 {
+				r.Negatives = append(r.Negatives, address)
+			}
+
+			continue
+		}
+
+		r.Holders++
+		accounts = append(accounts, SupplyAuditAccount{Address: address, Amount: b})
+	}
+
+	r.Difference = r.BalanceSum.Sub(r.TotalSupply)
+
+	sort.Strings(r.Negatives)
+
+	if !r.IsBalanced() {
+		sort.Slice(accounts, func(i, j int) bool {
+			return accounts[i].Address < accounts[j].Address
+		})
+
+		r.Accounts = accounts
+	}
+
+	return r
+}
+
+// SupplyAuditResult is the audit result of currency. Accounts are the holders
+// of currency; they are reported only when the currency is not balanced.
+type SupplyAuditResult struct {
+	Currency    types.CurrencyID     `json:"currency"`
+	Designed    bool                 `json:"designed"`
+	TotalSupply common.Big           `json:"total_supply"`
+	BalanceSum  common.Big           `json:"balance_sum"`
+	Difference  common.Big           `json:"difference"`
+	Holders     int64                `json:"holders"`
+	Negatives   []string             `json:"negatives,omitempty"`
+	Accounts    []SupplyAuditAccount `json:"accounts,omitempty"`
+}
+
+func (r SupplyAuditResult) IsBalanced() bool {
+	return r.Designed && len(r.Negatives) < 1 && r.Difference.Equal(common.ZeroBig)
+}
+
+type SupplyAuditAccount struct {
+	Address string     `json:"address"`
+	Amount  common.Big `json:"amount"`
+}
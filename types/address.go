@@ -4,19 +4,19 @@ import (
 	"encoding/hex"
 	"strings"
 
+	"github.com/ProtoconNet/mitum-currency/v3/types/address"
 	"github.com/ProtoconNet/mitum2/base"
 	"github.com/ProtoconNet/mitum2/util"
 	"github.com/ProtoconNet/mitum2/util/hint"
-	"golang.org/x/crypto/sha3"
 )
 
 var (
 	AddressHint       = hint.MustNewHint("fca-v0.0.1")
-	ZeroAddressSuffix = "-X"
+	ZeroAddressSuffix = address.ZeroSuffix
 )
 
 const (
-	AddressLength = 20
+	AddressLength = address.Size
 )
 
 type Address struct {
@@ -30,10 +30,10 @@ func NewAddress(s string) Address {
 }
 
 func NewAddressFromKeys(keys AccountKeys) (Address, error) {
-	var buf [42]byte
-	copy(buf[:2], "0x")
-	hex.Encode(buf[2:], keys.Hash().Bytes())
-	s := string(ChecksumHex(buf))
+	s, err := address.Checksum(keys.Hash().Bytes())
+	if err != nil {
+		return Address{}, err
+	}
 
 	return NewAddress(s), nil
 }
@@ -61,6 +61,11 @@ func (ad Address) IsValid([]byte) error {
 		if err != nil {
 			return util.ErrInvalid.Errorf("invalid mitum currency address3: %v", err)
 		}
+
+		if len(bytes) != AddressLength {
+			return util.ErrInvalid.Errorf("invalid mitum currency address: wrong length, %d", len(bytes))
+		}
+
 		hex.Encode(buf[2:], bytes)
 		if string(ChecksumHex(buf)) != sad {
 			return util.ErrInvalid.Errorf("invalid mitum currency address: checksum not matched, expeced %v but %v", string(ChecksumHex(buf)), sad)
@@ -72,22 +77,7 @@ func (ad Address) IsValid([]byte) error {
 
 // ChecksumHex return the hex in the manner of EIP55
 func ChecksumHex(buf [42]byte) []byte {
-	// compute checksum
-	sha := sha3.NewLegacyKeccak256()
-	sha.Write(buf[2:])
-	hash := sha.Sum(nil)
-	for i := 2; i < len(buf); i++ {
-		hashByte := hash[(i-2)/2]
-		if i%2 == 0 {
-			hashByte = hashByte >> 4
-		} else {
-			hashByte &= 0xf
-		}
-		if buf[i] > '9' && hashByte > 7 {
-			buf[i] -= 32
-		}
-	}
-	return buf[:]
+	return address.ChecksumHex(buf)
 }
 
 type Addresses interface {
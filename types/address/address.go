@@ -0,0 +1,182 @@
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// Type is the type suffix of mitum currency address.
+	Type = "fca"
+	// Size is the bytes size of address; same with the ethereum address.
+	Size = 20
+	// HexSize is the size of "0x" prefixed hex of address.
+	HexSize = Size*2 + 2
+	// ZeroSuffix is the suffix of zero address, "<currency id>-X".
+	ZeroSuffix = "-X"
+)
+
+// ErrInvalid is wrapped by every error of invalid address and keys; the
+// package does not depend on mitum2, so it is not same with util.ErrInvalid.
+var ErrInvalid = errors.New("invalid")
+
+// ChecksumHex returns the EIP-55 mixed case hex of the "0x" prefixed lower case
+// hex.
+func ChecksumHex(buf [HexSize]byte) []byte {
+	sha := sha3.NewLegacyKeccak256()
+	_, _ = sha.Write(buf[2:])
+	hash := sha.Sum(nil)
+
+	for i := 2; i < len(buf); i++ {
+		hashByte := hash[(i-2)/2]
+		if i%2 == 0 {
+			hashByte >>= 4
+		} else {
+			hashByte &= 0xf
+		}
+
+		if buf[i] > '9' && hashByte > 7 {
+			buf[i] -= 32
+		}
+	}
+
+	return buf[:]
+}
+
+// Checksum returns the EIP-55 hex of address bytes without type suffix.
+func Checksum(b []byte) (string, error) {
+	if len(b) != Size {
+		return "", invalidf("wrong address bytes size, %d != %d", len(b), Size)
+	}
+
+	var buf [HexSize]byte
+
+	copy(buf[:2], "0x")
+	hex.Encode(buf[2:], b)
+
+	return string(ChecksumHex(buf)), nil
+}
+
+// FromBytes returns the address string of address bytes.
+func FromBytes(b []byte) (string, error) {
+	s, err := Checksum(b)
+	if err != nil {
+		return "", err
+	}
+
+	return s + Type, nil
+}
+
+// FromHex returns the address string of the "0x" prefixed hex; the case of hex
+// is ignored, so the ethereum address can be converted to the mitum currency
+// address.
+func FromHex(s string) (string, error) {
+	b, err := decodeHex(s)
+	if err != nil {
+		return "", err
+	}
+
+	return FromBytes(b)
+}
+
+// Parse validates the address string and returns the address bytes. The zero
+// address does not have the address bytes, so it should be checked by IsZero
+// before.
+func Parse(s string) ([]byte, error) {
+	h, t, err := parseTypedString(s, len(Type))
+
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("invalid address, %q: %w", s, err)
+	case t != Type:
+		return nil, invalidf("invalid address, %q; type suffix, %q not found", s, Type)
+	case IsZero(s):
+		return nil, invalidf("invalid address, %q; zero address", s)
+	}
+
+	b, err := decodeHex(h)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address, %q: %w", s, err)
+	}
+
+	if c, _ := Checksum(b); c != h {
+		return nil, invalidf("invalid address, %q; checksum not matched, expected %q", s, c+Type)
+	}
+
+	return b, nil
+}
+
+// IsValid checks the address string; the zero address is also valid.
+func IsValid(s string) error {
+	if IsZero(s) {
+		_, err := ParseZero(s)
+
+		return err
+	}
+
+	_, err := Parse(s)
+
+	return err
+}
+
+// Zero returns the zero address of currency; the zero address has no keys, so
+// the balance of zero address can not be spent.
+func Zero(currency string) string {
+	return currency + ZeroSuffix + Type
+}
+
+// IsZero checks the address string is zero address.
+func IsZero(s string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(s, Type), ZeroSuffix)
+}
+
+// ParseZero returns the currency id of zero address.
+func ParseZero(s string) (string, error) {
+	switch {
+	case !strings.HasSuffix(s, Type):
+		return "", invalidf("invalid zero address, %q; type suffix, %q not found", s, Type)
+	case !IsZero(s):
+		return "", invalidf("invalid zero address, %q; zero suffix, %q not found", s, ZeroSuffix)
+	}
+
+	currency := strings.TrimSuffix(strings.TrimSuffix(s, Type), ZeroSuffix)
+	if len(currency) < 1 {
+		return "", invalidf("invalid zero address, %q; empty currency id", s)
+	}
+
+	return currency, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	switch {
+	case !strings.HasPrefix(s, "0x"):
+		return nil, invalidf("0x prefix not found")
+	case len(s) != HexSize:
+		return nil, invalidf("wrong hex size, %d != %d", len(s), HexSize)
+	}
+
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, invalidf("%v", err)
+	}
+
+	return b, nil
+}
+
+// parseTypedString splits the string into the body and the type suffix of
+// typesize; same with hint.ParseFixedTypedString of mitum2.
+func parseTypedString(s string, typesize int) (string, string, error) {
+	if len(s) <= typesize {
+		return "", "", invalidf("too short fixed typed string, %q", s)
+	}
+
+	return s[:len(s)-typesize], s[len(s)-typesize:], nil
+}
+
+func invalidf(format string, a ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,6 @@
+/*
+Package address provides the derivation and validation of mitum currency
+address. It depends only on the publickey strings, so the clients can derive
+and check the addresses without the node packages.
+*/
+package address
@@ -0,0 +1,122 @@
+package address
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// MaxKeys is the maximum number of keys of account.
+	MaxKeys = 10
+	// MaxWeight is the maximum weight of key and the maximum threshold; the
+	// single key account has the key of MaxWeight and the threshold of
+	// MaxWeight.
+	MaxWeight = 100
+
+	publickeyTypeSize = 3 // NOTE same with base.PKKeyTypeSize
+)
+
+// Key is the publickey and weight of account keys. Publickey is the string of
+// publickey with type suffix, like "...mpu".
+type Key struct {
+	Publickey string `json:"key"`
+	Weight    uint   `json:"weight"`
+}
+
+// FromKey derives the address of the single key account.
+func FromKey(publickey string) (string, error) {
+	return FromKeys([]Key{{Publickey: publickey, Weight: MaxWeight}}, MaxWeight)
+}
+
+// FromKeys derives the address of the account keys; the keys can be multisig.
+// The order of keys does not matter.
+func FromKeys(keys []Key, threshold uint) (string, error) {
+	h, err := KeysHash(keys, threshold)
+	if err != nil {
+		return "", err
+	}
+
+	return FromBytes(h)
+}
+
+// FromContractKeys derives the address of contract account. The contract
+// account is created with the keys of create-contract-account item and the
+// keys are replaced by the contract account keys after creation, so the
+// address is derived from the keys of item.
+func FromContractKeys(keys []Key, threshold uint) (string, error) {
+	return FromKeys(keys, threshold)
+}
+
+// KeysHash returns the address bytes of account keys, the last 20 bytes of
+// keccak256 of the keys sorted by publickey and the threshold.
+func KeysHash(keys []Key, threshold uint) ([]byte, error) {
+	if err := checkKeys(keys, threshold); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]Key, len(keys))
+	copy(sorted, keys)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare([]byte(sorted[i].Publickey), []byte(sorted[j].Publickey)) < 0
+	})
+
+	// NOTE same with util.UintToBytes of mitum2, the big endian uint64
+	var buf bytes.Buffer
+
+	for i := range sorted {
+		_, _ = buf.WriteString(sorted[i].Publickey)
+		_ = binary.Write(&buf, binary.BigEndian, uint64(sorted[i].Weight))
+	}
+
+	_ = binary.Write(&buf, binary.BigEndian, uint64(threshold))
+
+	sha := sha3.NewLegacyKeccak256()
+	_, _ = sha.Write(buf.Bytes())
+
+	return sha.Sum(nil)[12:], nil
+}
+
+func checkKeys(keys []Key, threshold uint) error {
+	switch n := len(keys); {
+	case n < 1:
+		return invalidf("invalid keys; empty keys")
+	case n > MaxKeys:
+		return invalidf("invalid keys; keys over %d, %d", MaxKeys, n)
+	case threshold < 1 || threshold > MaxWeight:
+		return invalidf("invalid keys; threshold, %d should be 1 <= threshold <= %d", threshold, MaxWeight)
+	}
+
+	var total uint
+
+	m := map[string]struct{}{}
+
+	for i := range keys {
+		k := keys[i]
+
+		if _, _, err := parseTypedString(k.Publickey, publickeyTypeSize); err != nil {
+			return invalidf("invalid keys; publickey %d, %q; %v", i, k.Publickey, err)
+		}
+
+		if k.Weight < 1 || k.Weight > MaxWeight {
+			return invalidf("invalid keys; weight of %q, %d should be 1 <= weight <= %d",
+				k.Publickey, k.Weight, MaxWeight)
+		}
+
+		if _, found := m[k.Publickey]; found {
+			return invalidf("invalid keys; duplicated key, %q", k.Publickey)
+		}
+
+		m[k.Publickey] = struct{}{}
+		total += k.Weight
+	}
+
+	if total < threshold {
+		return invalidf("invalid keys; sum of weight under threshold, %d < %d", total, threshold)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package address
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed vectors.json
+var vectorsJSON []byte
+
+// GoldenVector is the known address of account keys; the clients which
+// implement the address derivation by themselves can check their
+// implementation with GoldenVectors.
+type GoldenVector struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Keys      []Key  `json:"keys"`
+	Threshold uint   `json:"threshold"`
+}
+
+// GoldenVectors returns the golden vectors; the same vectors can be found in
+// vectors.json.
+func GoldenVectors() ([]GoldenVector, error) {
+	var vs []GoldenVector
+	if err := json.Unmarshal(vectorsJSON, &vs); err != nil {
+		return nil, invalidf("golden vectors; %v", err)
+	}
+
+	return vs, nil
+}
+
+// CheckGoldenVectors derives the address of every golden vector and compares
+// it with the known address.
+func CheckGoldenVectors() error {
+	vs, err := GoldenVectors()
+	if err != nil {
+		return err
+	}
+
+	for i := range vs {
+		v := vs[i]
+
+		switch a, err := FromKeys(v.Keys, v.Threshold); {
+		case err != nil:
+			return fmt.Errorf("golden vector, %q: %w", v.Name, err)
+		case a != v.Address:
+			return invalidf("golden vector, %q; address not matched, %q != %q", v.Name, a, v.Address)
+		}
+	}
+
+	return nil
+}
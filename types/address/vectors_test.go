@@ -0,0 +1,121 @@
+package address_test
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum-currency/v3/types/address"
+	"github.com/ProtoconNet/mitum2/base"
+)
+
+func TestCheckGoldenVectors(t *testing.T) {
+	if err := address.CheckGoldenVectors(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestGoldenVectorsWithAccountKeys checks the golden vectors with the address
+// of types.AccountKeys.
+func TestGoldenVectorsWithAccountKeys(t *testing.T) {
+	vs, err := address.GoldenVectors()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(vs) < 1 {
+		t.Fatal("empty golden vectors")
+	}
+
+	for i := range vs {
+		v := vs[i]
+
+		t.Run(v.Name, func(t *testing.T) {
+			keys := make([]types.AccountKey, len(v.Keys))
+
+			for j := range v.Keys {
+				pub, err := parseGoldenVectorPublickey(v.Keys[j].Publickey)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				k, err := types.NewBaseAccountKey(pub, v.Keys[j].Weight)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				keys[j] = k
+			}
+
+			ks, err := types.NewBaseAccountKeys(keys, v.Threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a, err := types.NewAddressFromKeys(ks)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if a.String() != v.Address {
+				t.Fatalf("address not matched, %q != %q", a.String(), v.Address)
+			}
+		})
+	}
+}
+
+func parseGoldenVectorPublickey(s string) (base.Publickey, error) {
+	if strings.HasSuffix(s, types.Ed25519PublickeyHint.Type().String()) {
+		return types.ParseEd25519Publickey(s)
+	}
+
+	return types.ParseMEPublickey(s)
+}
+
+func TestInvalid(t *testing.T) {
+	a, err := address.FromKey(types.NewMEPrivatekey().Publickey().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"",
+		"fca",
+		a[:len(a)-3],                          // NOTE without type
+		a[:len(a)-3] + "mca",                  // NOTE wrong type
+		strings.ToLower(a[:len(a)-3]) + "fca", // NOTE wrong checksum
+		a[2:],                                 // NOTE without 0x
+		"MCC-Xfca",                            // NOTE zero address
+	} {
+		if _, err := address.Parse(s); !errors.Is(err, address.ErrInvalid) {
+			t.Fatalf("expected ErrInvalid for %q, but %v", s, err)
+		}
+	}
+
+	for _, keys := range [][]address.Key{
+		nil,
+		{{Publickey: "mpu", Weight: 100}},
+		{{Publickey: "abcmpu", Weight: 0}},
+	} {
+		if _, err := address.FromKeys(keys, 100); !errors.Is(err, address.ErrInvalid) {
+			t.Fatalf("expected ErrInvalid for %v, but %v", keys, err)
+		}
+	}
+}
+
+// TestNoMitum2Dependency checks the package can be used by the clients
+// without the node packages.
+func TestNoMitum2Dependency(t *testing.T) {
+	b, err := exec.Command("go", "list", "-deps", ".").Output()
+	if err != nil {
+		t.Skipf("go list: %v", err)
+	}
+
+	for _, p := range strings.Fields(string(b)) {
+		if strings.HasPrefix(p, "github.com/ProtoconNet/mitum2") {
+			t.Fatalf("depends on mitum2, %q", p)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum-currency/v3/types/address"
 	"github.com/ProtoconNet/mitum2/base"
 
 	"github.com/ProtoconNet/mitum2/util"
@@ -20,7 +21,7 @@ var (
 	NilAccountKeysHint = hint.MustNewHint("mitum-currency-nil-keys-v0.0.1")
 )
 
-var MaxAccountKeyInKeys = 10
+var MaxAccountKeyInKeys = address.MaxKeys
 
 type AccountKey interface {
 	hint.Hinter
@@ -0,0 +1,264 @@
+package types
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	KeystoreVersion = 3
+
+	// StandardKeystoreScryptN and StandardKeystoreScryptP are the scrypt
+	// parameters of the ethereum wallets; LightKeystoreScryptN and
+	// LightKeystoreScryptP use much less memory and cpu.
+	StandardKeystoreScryptN = 1 << 18
+	StandardKeystoreScryptP = 1
+	LightKeystoreScryptN    = 1 << 12
+	LightKeystoreScryptP    = 6
+
+	keystoreScryptR     = 8
+	keystoreScryptDKLen = 32
+	keystoreCipher      = "aes-128-ctr"
+
+	// NOTE the kdf parameters of keystore file are bounded; the scrypt memory,
+	// 128*n*r bytes, is up to 1GiB, and the cost, n*r*p, is up to 8 times of
+	// the standard.
+	keystoreMaxScryptMemory = 1 << 30
+	keystoreMaxScryptCost   = 1 << 24
+	keystoreMaxPBKDF2C      = 1 << 24
+	keystoreMaxDKLen        = 64
+)
+
+// Keystore is the passphrase encrypted MEPrivatekey. It follows the ethereum
+// keystore v3, so the keystore can be imported to the ethereum wallets and vice
+// versa.
+type Keystore struct {
+	ID      string         `json:"id"`
+	Address string         `json:"address"`
+	Crypto  KeystoreCrypto `json:"crypto"`
+	Version int            `json:"version"`
+}
+
+type KeystoreCrypto struct {
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams struct {
+		IV string `json:"iv"`
+	} `json:"cipherparams"`
+	KDF string `json:"kdf"`
+	MAC string `json:"mac"`
+}
+
+// EncryptKeystore encrypts the privatekey with passphrase by scrypt and
+// aes-128-ctr.
+func EncryptKeystore(k MEPrivatekey, passphrase []byte, scryptN, scryptP int) (Keystore, error) {
+	e := util.StringError("encrypt keystore")
+
+	if err := k.IsValid(nil); err != nil {
+		return Keystore{}, e.Wrap(err)
+	}
+
+	if err := checkKeystoreScryptParams(scryptN, keystoreScryptR, scryptP); err != nil {
+		return Keystore{}, e.Wrap(err)
+	}
+
+	salt := make([]byte, 32) //nolint:gomnd //...
+	iv := make([]byte, aes.BlockSize)
+
+	for _, b := range [][]byte{salt, iv} {
+		if _, err := io.ReadFull(rand.Reader, b); err != nil {
+			return Keystore{}, e.Wrap(err)
+		}
+	}
+
+	dk, err := scrypt.Key(passphrase, salt, scryptN, keystoreScryptR, scryptP, keystoreScryptDKLen)
+	if err != nil {
+		return Keystore{}, e.Wrap(err)
+	}
+
+	ciphertext, err := keystoreAESCTR(dk[:16], iv, crypto.FromECDSA(k.priv))
+	if err != nil {
+		return Keystore{}, e.Wrap(err)
+	}
+
+	ks := Keystore{
+		ID:      util.UUID().String(),
+		Address: strings.ToLower(crypto.PubkeyToAddress(k.priv.PublicKey).Hex()[2:]),
+		Version: KeystoreVersion,
+	}
+
+	ks.Crypto.Cipher = keystoreCipher
+	ks.Crypto.CipherText = hex.EncodeToString(ciphertext)
+	ks.Crypto.CipherParams.IV = hex.EncodeToString(iv)
+	ks.Crypto.KDF = "scrypt"
+	ks.Crypto.KDFParams = map[string]interface{}{
+		"n":     scryptN,
+		"r":     keystoreScryptR,
+		"p":     scryptP,
+		"dklen": keystoreScryptDKLen,
+		"salt":  hex.EncodeToString(salt),
+	}
+	ks.Crypto.MAC = hex.EncodeToString(crypto.Keccak256(dk[16:32], ciphertext))
+
+	return ks, nil
+}
+
+// DecryptKeystore decrypts the keystore with passphrase; the kdf of keystore
+// should be scrypt or pbkdf2.
+func DecryptKeystore(b, passphrase []byte) (MEPrivatekey, error) {
+	e := util.StringError("decrypt keystore")
+
+	var ks Keystore
+	if err := util.UnmarshalJSON(b, &ks); err != nil {
+		return MEPrivatekey{}, e.Wrap(err)
+	}
+
+	switch {
+	case ks.Version != KeystoreVersion:
+		return MEPrivatekey{}, e.Errorf("unsupported version, %d", ks.Version)
+	case ks.Crypto.Cipher != keystoreCipher:
+		return MEPrivatekey{}, e.Errorf("unsupported cipher, %q", ks.Crypto.Cipher)
+	}
+
+	ciphertext, err := hex.DecodeString(ks.Crypto.CipherText)
+	if err != nil {
+		return MEPrivatekey{}, e.WithMessage(err, "ciphertext")
+	}
+
+	iv, err := hex.DecodeString(ks.Crypto.CipherParams.IV)
+	if err != nil {
+		return MEPrivatekey{}, e.WithMessage(err, "iv")
+	}
+
+	mac, err := hex.DecodeString(ks.Crypto.MAC)
+	if err != nil {
+		return MEPrivatekey{}, e.WithMessage(err, "mac")
+	}
+
+	dk, err := ks.Crypto.derivedKey(passphrase)
+	if err != nil {
+		return MEPrivatekey{}, e.Wrap(err)
+	}
+
+	if !hmac.Equal(crypto.Keccak256(dk[16:32], ciphertext), mac) {
+		return MEPrivatekey{}, e.Errorf("wrong passphrase")
+	}
+
+	kb, err := keystoreAESCTR(dk[:16], iv, ciphertext)
+	if err != nil {
+		return MEPrivatekey{}, e.Wrap(err)
+	}
+
+	priv, err := crypto.ToECDSA(kb)
+	if err != nil {
+		return MEPrivatekey{}, e.Wrap(err)
+	}
+
+	k := newMEPrivatekeyFromPrivateKey(priv)
+
+	if len(ks.Address) > 0 {
+		address := strings.ToLower(crypto.PubkeyToAddress(priv.PublicKey).Hex()[2:])
+
+		if address != strings.ToLower(strings.TrimPrefix(ks.Address, "0x")) {
+			return MEPrivatekey{}, e.Errorf("address not matched; keystore=%q key=%q", ks.Address, address)
+		}
+	}
+
+	return k, nil
+}
+
+func (c KeystoreCrypto) derivedKey(passphrase []byte) ([]byte, error) {
+	salt, err := hex.DecodeString(c.kdfParamString("salt"))
+	if err != nil {
+		return nil, errors.WithMessage(err, "salt")
+	}
+
+	dklen := c.kdfParamInt("dklen")
+
+	switch {
+	case dklen < 32: //nolint:gomnd //...
+		return nil, errors.Errorf("too short dklen, %d", dklen)
+	case dklen > keystoreMaxDKLen:
+		return nil, errors.Errorf("too long dklen, %d", dklen)
+	}
+
+	switch c.KDF {
+	case "scrypt":
+		n, r, p := c.kdfParamInt("n"), c.kdfParamInt("r"), c.kdfParamInt("p")
+
+		if err := checkKeystoreScryptParams(n, r, p); err != nil {
+			return nil, err
+		}
+
+		return scrypt.Key(passphrase, salt, n, r, p, dklen)
+	case "pbkdf2":
+		if prf := c.kdfParamString("prf"); prf != "hmac-sha256" {
+			return nil, errors.Errorf("unsupported pbkdf2 prf, %q", prf)
+		}
+
+		iter := c.kdfParamInt("c")
+		if iter < 1 || iter > keystoreMaxPBKDF2C {
+			return nil, errors.Errorf("invalid pbkdf2 c, %d", iter)
+		}
+
+		return pbkdf2.Key(passphrase, salt, iter, dklen, sha256.New), nil
+	default:
+		return nil, errors.Errorf("unsupported kdf, %q", c.KDF)
+	}
+}
+
+func checkKeystoreScryptParams(n, r, p int) error {
+	switch {
+	case n < 2 || n&(n-1) != 0:
+		return errors.Errorf("invalid scrypt n, %d; should be power of 2", n)
+	case r < 1, p < 1:
+		return errors.Errorf("invalid scrypt r or p, %d, %d", r, p)
+	case n > keystoreMaxScryptMemory/128/r:
+		return errors.Errorf("too much scrypt memory; n=%d r=%d", n, r)
+	case p > keystoreMaxScryptCost/n/r:
+		return errors.Errorf("too much scrypt cost; n=%d r=%d p=%d", n, r, p)
+	default:
+		return nil
+	}
+}
+
+func (c KeystoreCrypto) kdfParamString(k string) string {
+	s, _ := c.KDFParams[k].(string)
+
+	return s
+}
+
+func (c KeystoreCrypto) kdfParamInt(k string) int {
+	f, _ := c.KDFParams[k].(float64)
+
+	return int(f)
+}
+
+func keystoreAESCTR(key, iv, b []byte) ([]byte, error) {
+	if len(iv) != aes.BlockSize {
+		return nil, errors.Errorf("wrong iv length, %d", len(iv))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	o := make([]byte, len(b))
+	cipher.NewCTR(block, iv).XORKeyStream(o, b)
+
+	return o, nil
+}
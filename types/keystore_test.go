@@ -0,0 +1,244 @@
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NOTE test vectors of web3 secret storage definition,
+// https://ethereum.org/en/developers/docs/data-structures-and-encoding/web3-secret-storage/
+var testKeystoreVectors = []struct {
+	name       string
+	keystore   string
+	passphrase string
+	privatekey string
+}{
+	{
+		name: "pbkdf2",
+		keystore: `{
+  "crypto": {
+    "cipher": "aes-128-ctr",
+    "cipherparams": {"iv": "6087dab2f9fdbbfaddc31a909735c1e6"},
+    "ciphertext": "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46",
+    "kdf": "pbkdf2",
+    "kdfparams": {
+      "c": 262144,
+      "dklen": 32,
+      "prf": "hmac-sha256",
+      "salt": "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+    },
+    "mac": "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"
+  },
+  "id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+  "version": 3
+}`,
+		passphrase: "testpassword",
+		privatekey: "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d",
+	},
+	{
+		name: "scrypt",
+		keystore: `{
+  "crypto": {
+    "cipher": "aes-128-ctr",
+    "cipherparams": {"iv": "83dbcc02d8ccb40e466191a123791e0e"},
+    "ciphertext": "d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c",
+    "kdf": "scrypt",
+    "kdfparams": {
+      "dklen": 32,
+      "n": 262144,
+      "r": 1,
+      "p": 8,
+      "salt": "ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"
+    },
+    "mac": "2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"
+  },
+  "id": "3198bc9c-6672-5ab3-d995-4942343ae5b6",
+  "version": 3
+}`,
+		passphrase: "testpassword",
+		privatekey: "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d",
+	},
+}
+
+func TestDecryptKeystoreVectors(t *testing.T) {
+	for _, v := range testKeystoreVectors {
+		t.Run(v.name, func(t *testing.T) {
+			k, err := DecryptKeystore([]byte(v.keystore), []byte(v.passphrase))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if s := hex.EncodeToString(crypto.FromECDSA(k.ECDSA())); s != v.privatekey {
+				t.Fatalf("unexpected privatekey, %s", s)
+			}
+
+			if _, err := DecryptKeystore([]byte(v.keystore), []byte("wrongpassword")); err == nil {
+				t.Fatal("expected error for wrong passphrase")
+			}
+		})
+	}
+}
+
+func TestEncryptKeystore(t *testing.T) {
+	passphrase := []byte("hehehe")
+	priv := NewMEPrivatekey()
+
+	ks, err := EncryptKeystore(priv, passphrase, LightKeystoreScryptN, LightKeystoreScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := util.MarshalJSON(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("decrypt", func(t *testing.T) {
+		k, err := DecryptKeystore(b, passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !k.Equal(priv) {
+			t.Fatal("privatekey not matched")
+		}
+
+		if !strings.EqualFold("0x"+ks.Address, crypto.PubkeyToAddress(priv.ECDSA().PublicKey).Hex()) {
+			t.Fatalf("unexpected address, %q", ks.Address)
+		}
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		if _, err := DecryptKeystore(b, []byte("hohoho")); err == nil {
+			t.Fatal("expected error")
+		} else if !strings.Contains(err.Error(), "wrong passphrase") {
+			t.Fatalf("unexpected error, %v", err)
+		}
+	})
+
+	t.Run("invalid scrypt params", func(t *testing.T) {
+		for _, i := range [][2]int{
+			{0, 1},
+			{3, 1},                             // NOTE not power of 2
+			{1 << 24, 1},                       // NOTE too much memory
+			{StandardKeystoreScryptN, 1 << 10}, // NOTE too much cost
+			{LightKeystoreScryptN, 0},
+		} {
+			if _, err := EncryptKeystore(priv, passphrase, i[0], i[1]); err == nil {
+				t.Fatalf("expected error for n=%d p=%d", i[0], i[1])
+			}
+		}
+	})
+}
+
+func TestDecryptKeystoreInvalid(t *testing.T) {
+	passphrase := []byte("hehehe")
+
+	ks, err := EncryptKeystore(NewMEPrivatekey(), passphrase, LightKeystoreScryptN, LightKeystoreScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []struct {
+		name  string
+		f     func(*Keystore)
+		error string
+	}{
+		{name: "version", f: func(ks *Keystore) { ks.Version = 2 }, error: "unsupported version"},
+		{name: "cipher", f: func(ks *Keystore) { ks.Crypto.Cipher = "aes-128-cbc" }, error: "unsupported cipher"},
+		{name: "bad iv", f: func(ks *Keystore) { ks.Crypto.CipherParams.IV = "zz" }, error: "iv"},
+		{
+			name:  "short iv",
+			f:     func(ks *Keystore) { ks.Crypto.CipherParams.IV = ks.Crypto.CipherParams.IV[:16] },
+			error: "wrong iv length",
+		},
+		{
+			name: "wrong mac",
+			f: func(ks *Keystore) {
+				b, _ := hex.DecodeString(ks.Crypto.MAC)
+				b[0] ^= 0xff
+				ks.Crypto.MAC = hex.EncodeToString(b)
+			},
+			error: "wrong passphrase",
+		},
+		{
+			name: "wrong ciphertext",
+			f: func(ks *Keystore) {
+				b, _ := hex.DecodeString(ks.Crypto.CipherText)
+				b[0] ^= 0xff
+				ks.Crypto.CipherText = hex.EncodeToString(b)
+			},
+			error: "wrong passphrase",
+		},
+		{
+			name:  "wrong address",
+			f:     func(ks *Keystore) { ks.Address = strings.Repeat("0", 40) },
+			error: "address not matched",
+		},
+		{name: "unknown kdf", f: func(ks *Keystore) { ks.Crypto.KDF = "argon2" }, error: "unsupported kdf"},
+		{name: "short dklen", f: func(ks *Keystore) { ks.Crypto.KDFParams["dklen"] = 16 }, error: "too short dklen"},
+		{name: "long dklen", f: func(ks *Keystore) { ks.Crypto.KDFParams["dklen"] = 1 << 20 }, error: "too long dklen"},
+		{name: "scrypt n", f: func(ks *Keystore) { ks.Crypto.KDFParams["n"] = 1000 }, error: "invalid scrypt n"},
+		{name: "scrypt r", f: func(ks *Keystore) { ks.Crypto.KDFParams["r"] = 0 }, error: "invalid scrypt r or p"},
+		{
+			name:  "scrypt memory",
+			f:     func(ks *Keystore) { ks.Crypto.KDFParams["n"] = 1 << 30 },
+			error: "too much scrypt memory",
+		},
+		{
+			name:  "scrypt cost",
+			f:     func(ks *Keystore) { ks.Crypto.KDFParams["p"] = 1 << 20 },
+			error: "too much scrypt cost",
+		},
+		{
+			name: "pbkdf2 prf",
+			f: func(ks *Keystore) {
+				ks.Crypto.KDF = "pbkdf2"
+				ks.Crypto.KDFParams["prf"] = "hmac-sha512"
+				ks.Crypto.KDFParams["c"] = 1
+			},
+			error: "unsupported pbkdf2 prf",
+		},
+		{
+			name: "pbkdf2 c",
+			f: func(ks *Keystore) {
+				ks.Crypto.KDF = "pbkdf2"
+				ks.Crypto.KDFParams["prf"] = "hmac-sha256"
+				ks.Crypto.KDFParams["c"] = 1 << 30
+			},
+			error: "invalid pbkdf2 c",
+		},
+	} {
+		t.Run(i.name, func(t *testing.T) {
+			var c Keystore
+
+			if err := util.UnmarshalJSON(marshalTestKeystore(t, ks), &c); err != nil {
+				t.Fatal(err)
+			}
+
+			i.f(&c)
+
+			switch _, err := DecryptKeystore(marshalTestKeystore(t, c), passphrase); {
+			case err == nil:
+				t.Fatal("expected error")
+			case !strings.Contains(err.Error(), i.error):
+				t.Fatalf("expected error %q, but %v", i.error, err)
+			}
+		})
+	}
+}
+
+func marshalTestKeystore(t *testing.T, ks Keystore) []byte {
+	t.Helper()
+
+	b, err := util.MarshalJSON(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
@@ -0,0 +1,268 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ProtoconNet/mitum-currency/v3/common"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/hint"
+	"github.com/pkg/errors"
+)
+
+var (
+	Ed25519PrivatekeyHint = hint.MustNewHint("epr-v0.0.1")
+	Ed25519PublickeyHint  = hint.MustNewHint("epu-v0.0.1")
+)
+
+// Ed25519Privatekey is the ed25519 privatekey; the string is the hex encoded
+// 32 bytes seed with "epr" type.
+type Ed25519Privatekey struct {
+	priv ed25519.PrivateKey
+	s    string
+	pub  Ed25519Publickey
+	b    []byte
+	hint.BaseHinter
+}
+
+func NewEd25519Privatekey() Ed25519Privatekey {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+	return newEd25519Privatekey(priv)
+}
+
+func NewEd25519PrivatekeyFromSeed(s string) (Ed25519Privatekey, error) {
+	if l := len([]byte(s)); l < base.PrivatekeyMinSeedSize {
+		return Ed25519Privatekey{}, util.ErrInvalid.Errorf(
+			"wrong seed for privatekey; too short, %d < %d", l, base.PrivatekeyMinSeedSize)
+	}
+
+	h := sha256.Sum256([]byte(s))
+
+	return newEd25519Privatekey(ed25519.NewKeyFromSeed(h[:])), nil
+}
+
+func ParseEd25519Privatekey(s string) (Ed25519Privatekey, error) {
+	t := Ed25519PrivatekeyHint.Type().String()
+
+	switch {
+	case !strings.HasSuffix(s, t):
+		return Ed25519Privatekey{}, util.ErrInvalid.Errorf("Unknown private key string")
+	case len(s) <= len(t):
+		return Ed25519Privatekey{}, util.ErrInvalid.Errorf("Invalid private key string; too short")
+	}
+
+	return LoadEd25519Privatekey(s[:len(s)-len(t)])
+}
+
+func LoadEd25519Privatekey(s string) (Ed25519Privatekey, error) {
+	h, err := hex.DecodeString(s)
+	if err != nil {
+		return Ed25519Privatekey{}, util.ErrInvalid.WithMessage(err, "load private key")
+	}
+
+	if len(h) != ed25519.SeedSize {
+		return Ed25519Privatekey{}, util.ErrInvalid.Errorf("wrong ed25519 seed size, %d", len(h))
+	}
+
+	return newEd25519Privatekey(ed25519.NewKeyFromSeed(h)), nil
+}
+
+func newEd25519Privatekey(priv ed25519.PrivateKey) Ed25519Privatekey {
+	k := Ed25519Privatekey{
+		BaseHinter: hint.NewBaseHinter(Ed25519PrivatekeyHint),
+		priv:       priv,
+	}
+
+	return k.ensure()
+}
+
+func (k Ed25519Privatekey) String() string {
+	return k.s
+}
+
+func (k Ed25519Privatekey) Bytes() []byte {
+	return k.b
+}
+
+func (k Ed25519Privatekey) IsValid([]byte) error {
+	if err := k.BaseHinter.IsValid(Ed25519PrivatekeyHint.Type().Bytes()); err != nil {
+		return util.ErrInvalid.WithMessage(err, "wrong hint in private key")
+	}
+
+	switch {
+	case len(k.priv) != ed25519.PrivateKeySize:
+		return util.ErrInvalid.Errorf("empty ed25519 private key")
+	case len(k.s) < 1:
+		return util.ErrInvalid.Errorf("empty private key string")
+	case len(k.b) < 1:
+		return util.ErrInvalid.Errorf("empty private key []byte")
+	}
+
+	return nil
+}
+
+func (k Ed25519Privatekey) Publickey() base.Publickey {
+	return k.pub
+}
+
+func (k Ed25519Privatekey) Equal(b base.PKKey) bool {
+	switch {
+	case b == nil:
+		return false
+	default:
+		return k.s == b.String()
+	}
+}
+
+func (k Ed25519Privatekey) Sign(b []byte) (base.Signature, error) {
+	return base.Signature(ed25519.Sign(k.priv, b)), nil
+}
+
+func (k Ed25519Privatekey) MarshalText() ([]byte, error) {
+	return []byte(k.s), nil
+}
+
+func (k *Ed25519Privatekey) UnmarshalText(b []byte) error {
+	u, err := LoadEd25519Privatekey(string(b))
+	if err != nil {
+		return err
+	}
+
+	*k = u.ensure()
+
+	return nil
+}
+
+func (k *Ed25519Privatekey) ensure() Ed25519Privatekey {
+	if len(k.priv) != ed25519.PrivateKeySize {
+		return *k
+	}
+
+	k.pub = NewEd25519Publickey(k.priv.Public().(ed25519.PublicKey)) //nolint:forcetypeassert //...
+	k.s = fmt.Sprintf("%s%s", hex.EncodeToString(k.priv.Seed()), k.Hint().Type().String())
+	k.b = []byte(k.s)
+
+	return *k
+}
+
+// Ed25519Publickey is the ed25519 public key; the string is the hex encoded 32
+// bytes public key with "epu" type.
+type Ed25519Publickey struct {
+	k ed25519.PublicKey
+	s string
+	b []byte
+	hint.BaseHinter
+}
+
+func NewEd25519Publickey(k ed25519.PublicKey) Ed25519Publickey {
+	pub := Ed25519Publickey{
+		BaseHinter: hint.NewBaseHinter(Ed25519PublickeyHint),
+		k:          k,
+	}
+
+	return pub.ensure()
+}
+
+func ParseEd25519Publickey(s string) (Ed25519Publickey, error) {
+	t := Ed25519PublickeyHint.Type().String()
+
+	switch {
+	case !strings.HasSuffix(s, t):
+		return Ed25519Publickey{}, util.ErrInvalid.Errorf("unknown public key string")
+	case len(s) <= len(t):
+		return Ed25519Publickey{}, util.ErrInvalid.Errorf("invalid public key string; too short")
+	}
+
+	return LoadEd25519Publickey(s[:len(s)-len(t)])
+}
+
+func LoadEd25519Publickey(s string) (Ed25519Publickey, error) {
+	h, err := hex.DecodeString(s)
+	if err != nil {
+		return Ed25519Publickey{}, util.ErrInvalid.WithMessage(err, "load public key")
+	}
+
+	if len(h) != ed25519.PublicKeySize {
+		return Ed25519Publickey{}, util.ErrInvalid.Errorf("wrong ed25519 public key size, %d", len(h))
+	}
+
+	return NewEd25519Publickey(ed25519.PublicKey(h)), nil
+}
+
+func (k Ed25519Publickey) String() string {
+	return k.s
+}
+
+func (k Ed25519Publickey) Bytes() []byte {
+	return k.b
+}
+
+func (k Ed25519Publickey) IsValid([]byte) error {
+	if err := k.BaseHinter.IsValid(Ed25519PublickeyHint.Type().Bytes()); err != nil {
+		return util.ErrInvalid.WithMessage(err, "wrong hint in public key")
+	}
+
+	switch {
+	case len(k.k) != ed25519.PublicKeySize:
+		return util.ErrInvalid.Errorf("empty ed25519 public key")
+	case len(k.s) < 1:
+		return util.ErrInvalid.Errorf("empty public key string")
+	case len(k.b) < 1:
+		return util.ErrInvalid.Errorf("empty public key []byte")
+	}
+
+	return nil
+}
+
+func (k Ed25519Publickey) Equal(b base.PKKey) bool {
+	switch {
+	case b == nil:
+		return false
+	default:
+		return k.s == b.String()
+	}
+}
+
+func (k Ed25519Publickey) Verify(input []byte, sig base.Signature) error {
+	if len(sig) != ed25519.SignatureSize {
+		return common.ErrValueInvalid.Wrap(base.ErrSignatureVerification.WithStack())
+	}
+
+	if !ed25519.Verify(k.k, input, sig) {
+		return base.ErrSignatureVerification.WithStack()
+	}
+
+	return nil
+}
+
+func (k Ed25519Publickey) MarshalText() ([]byte, error) {
+	return []byte(k.s), nil
+}
+
+func (k *Ed25519Publickey) UnmarshalText(b []byte) error {
+	u, err := LoadEd25519Publickey(string(b))
+	if err != nil {
+		return errors.Wrap(err, "UnmarshalText for public key")
+	}
+
+	*k = u.ensure()
+
+	return nil
+}
+
+func (k *Ed25519Publickey) ensure() Ed25519Publickey {
+	if len(k.k) != ed25519.PublicKeySize {
+		return *k
+	}
+
+	k.s = fmt.Sprintf("%s%s", hex.EncodeToString(k.k), k.Hint().Type().String())
+	k.b = []byte(k.s)
+
+	return *k
+}
@@ -0,0 +1,316 @@
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	bsonenc "github.com/ProtoconNet/mitum-currency/v3/digest/util/bson"
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+)
+
+func TestEd25519Privatekey(t *testing.T) {
+	// NOTE test vector 1 of RFC 8032, https://www.rfc-editor.org/rfc/rfc8032#section-7.1
+	priv, err := ParseEd25519Privatekey("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60epr")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := priv.IsValid(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := priv.Publickey().String(); s != "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511aepu" {
+		t.Fatalf("unexpected publickey, %s", s)
+	}
+
+	sig, err := priv.Sign(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := hex.EncodeToString(sig); s != "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b" {
+		t.Fatalf("unexpected signature, %s", s)
+	}
+
+	t.Run("sign and verify", func(t *testing.T) {
+		priv := NewEd25519Privatekey()
+		input := util.UUID().Bytes()
+
+		sig, err := priv.Sign(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := priv.Publickey().Verify(input, sig); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := priv.Publickey().Verify(util.UUID().Bytes(), sig); err == nil {
+			t.Fatal("expected error for wrong input")
+		}
+
+		if err := NewEd25519Privatekey().Publickey().Verify(input, sig); err == nil {
+			t.Fatal("expected error for wrong publickey")
+		}
+
+		if err := priv.Publickey().Verify(input, sig[:len(sig)-1]); err == nil {
+			t.Fatal("expected error for short signature")
+		}
+	})
+
+	t.Run("from seed", func(t *testing.T) {
+		a, err := NewEd25519PrivatekeyFromSeed("ed25519 seed of test; longer than 36 bytes")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := NewEd25519PrivatekeyFromSeed("ed25519 seed of test; longer than 36 bytes")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !a.Equal(b) {
+			t.Fatal("privatekey from same seed not matched")
+		}
+
+		if _, err := NewEd25519PrivatekeyFromSeed("short"); err == nil {
+			t.Fatal("expected error for short seed")
+		}
+	})
+}
+
+func TestParseEd25519Key(t *testing.T) {
+	priv := NewEd25519Privatekey()
+
+	t.Run("string", func(t *testing.T) {
+		upriv, err := ParseEd25519Privatekey(priv.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !upriv.Equal(priv) {
+			t.Fatal("privatekey not matched")
+		}
+
+		upub, err := ParseEd25519Publickey(priv.Publickey().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !upub.Equal(priv.Publickey()) {
+			t.Fatal("publickey not matched")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		pub := priv.Publickey().String()
+
+		for _, s := range []string{
+			"",
+			"epu",
+			pub[:len(pub)-3],                 // NOTE without type
+			pub[:len(pub)-3] + "fpu",         // NOTE wrong type
+			pub[:len(pub)-5] + "epu",         // NOTE short
+			pub[:len(pub)-3] + "00epu",       // NOTE long
+			"zz" + pub[2:],                   // NOTE not hex
+			strings.Repeat("0", 128) + "epu", // NOTE ed25519 privatekey size
+		} {
+			if _, err := ParseEd25519Publickey(s); err == nil {
+				t.Fatalf("expected error for publickey, %q", s)
+			}
+		}
+
+		for _, s := range []string{
+			"",
+			"epr",
+			priv.String()[:len(priv.String())-5] + "epr",
+			strings.Repeat("0", 128) + "epr",
+		} {
+			if _, err := ParseEd25519Privatekey(s); err == nil {
+				t.Fatalf("expected error for privatekey, %q", s)
+			}
+		}
+	})
+}
+
+func TestEd25519KeyEncode(t *testing.T) {
+	jenc := jsonenc.NewEncoder()
+	benc := bsonenc.NewEncoder()
+
+	for _, enc := range []interface {
+		Add(encoder.DecodeDetail) error
+	}{jenc, benc} {
+		for _, d := range []encoder.DecodeDetail{
+			{Hint: Ed25519PrivatekeyHint, Instance: Ed25519Privatekey{}},
+			{Hint: Ed25519PublickeyHint, Instance: Ed25519Publickey{}},
+			{Hint: MEPublickeyHint, Instance: MEPublickey{}},
+			{Hint: AccountKeyHint, Instance: BaseAccountKey{}},
+			{Hint: AccountKeysHint, Instance: BaseAccountKeys{}},
+		} {
+			if err := enc.Add(d); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	priv := NewEd25519Privatekey()
+
+	t.Run("text", func(t *testing.T) {
+		b, err := priv.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		upriv, err := base.DecodePrivatekeyFromString(string(b), jenc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !upriv.Equal(priv) {
+			t.Fatal("privatekey not matched")
+		}
+
+		b, err = priv.Publickey().(Ed25519Publickey).MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		upub, err := base.DecodePublickeyFromString(string(b), jenc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := upub.(Ed25519Publickey); !ok {
+			t.Fatalf("expected Ed25519Publickey, but %T", upub)
+		}
+
+		if !upub.Equal(priv.Publickey()) {
+			t.Fatal("publickey not matched")
+		}
+	})
+
+	key, err := NewBaseAccountKey(priv.Publickey(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewBaseAccountKeys([]AccountKey{key}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkKeys := func(t *testing.T, i interface{}) {
+		t.Helper()
+
+		uks, ok := i.(BaseAccountKeys)
+		if !ok {
+			t.Fatalf("expected BaseAccountKeys, but %T", i)
+		}
+
+		if err := uks.IsValid(nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if !uks.Equal(keys) {
+			t.Fatal("keys not matched")
+		}
+
+		if _, ok := uks.Keys()[0].Key().(Ed25519Publickey); !ok {
+			t.Fatalf("expected Ed25519Publickey, but %T", uks.Keys()[0].Key())
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b, err := util.MarshalJSON(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		i, err := jenc.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkKeys(t, i)
+	})
+
+	t.Run("bson", func(t *testing.T) {
+		b, err := benc.Marshal(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		i, err := benc.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checkKeys(t, i)
+	})
+}
+
+func TestCheckThresholdMixedKeys(t *testing.T) {
+	networkID := base.NetworkID("mixed-keys")
+	input := util.UUID().Bytes()
+
+	mpriv := NewMEPrivatekey()
+	epriv := NewEd25519Privatekey()
+
+	mkey, err := NewBaseAccountKey(mpriv.Publickey(), 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ekey, err := NewBaseAccountKey(epriv.Publickey(), 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewBaseAccountKeys([]AccountKey{mkey, ekey}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSign := func(priv base.Privatekey) base.Sign {
+		sign, err := base.NewBaseSignFromBytes(priv, networkID, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := sign.Verify(networkID, input); err != nil {
+			t.Fatal(err)
+		}
+
+		return sign
+	}
+
+	msign, esign := newSign(mpriv), newSign(epriv)
+
+	if err := CheckThreshold([]base.Sign{msign, esign}, keys); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, signs := range [][]base.Sign{{msign}, {esign}} {
+		if err := CheckThreshold(signs, keys); err == nil {
+			t.Fatal("expected error for not passed threshold")
+		}
+	}
+
+	unknown := newSign(NewEd25519Privatekey())
+
+	if err := CheckThreshold([]base.Sign{msign, esign, unknown}, keys); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+
+	// NOTE the ed25519 signature can not be verified by the same bytes of
+	// secp256k1 publickey
+	forged := base.NewBaseSign(mpriv.Publickey(), esign.Signature(), time.Now())
+	if err := forged.Verify(networkID, input); err == nil {
+		t.Fatal("expected error for forged signature")
+	}
+}
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultHDPath is the BIP-44 path of the first ethereum account; the same
+// mnemonic derives the same MEPrivatekey with the ethereum wallets.
+const DefaultHDPath = "m/44'/60'/0'/0/0"
+
+const hdHardenedIndex uint32 = 0x80000000
+
+var hdMasterKey = []byte("Bitcoin seed")
+
+// NewMnemonic generates new BIP-39 mnemonic; bits is the entropy size, 128 for
+// 12 words and 256 for 24 words.
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", util.ErrInvalid.Wrap(err)
+	}
+
+	return bip39.NewMnemonic(entropy)
+}
+
+// NewMEPrivatekeyFromMnemonic derives MEPrivatekey from the BIP-39 mnemonic by
+// the BIP-32 path like "m/44'/60'/0'/0/0".
+func NewMEPrivatekeyFromMnemonic(mnemonic, passphrase, path string) (MEPrivatekey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return MEPrivatekey{}, util.ErrInvalid.Errorf("invalid mnemonic")
+	}
+
+	indexes, err := ParseHDPath(path)
+	if err != nil {
+		return MEPrivatekey{}, err
+	}
+
+	k, err := deriveHDPrivatekey(bip39.NewSeed(mnemonic, passphrase), indexes)
+	if err != nil {
+		return MEPrivatekey{}, err
+	}
+
+	priv, err := crypto.ToECDSA(k)
+	if err != nil {
+		return MEPrivatekey{}, errors.WithStack(err)
+	}
+
+	return newMEPrivatekeyFromPrivateKey(priv), nil
+}
+
+// ParseHDPath parses the BIP-32 path; the index with "'" or "h" suffix is
+// hardened.
+func ParseHDPath(path string) ([]uint32, error) {
+	e := util.ErrInvalid.Errorf("invalid hd path, %q", path)
+
+	l := strings.Split(strings.TrimSpace(path), "/")
+	if len(l) < 1 || l[0] != "m" {
+		return nil, e.Errorf("should start with m")
+	}
+
+	indexes := make([]uint32, len(l)-1)
+
+	for i, s := range l[1:] {
+		var hardened bool
+
+		if t := strings.TrimRight(s, "'h"); len(t) != len(s) {
+			if len(s)-len(t) != 1 {
+				return nil, e.Errorf("wrong index, %q", s)
+			}
+
+			hardened = true
+			s = t
+		}
+
+		n, err := strconv.ParseUint(s, 10, 32)
+
+		switch {
+		case err != nil:
+			return nil, e.Wrap(err)
+		case uint32(n) >= hdHardenedIndex:
+			return nil, e.Errorf("too big index, %q", s)
+		}
+
+		indexes[i] = uint32(n)
+		if hardened {
+			indexes[i] += hdHardenedIndex
+		}
+	}
+
+	return indexes, nil
+}
+
+func deriveHDPrivatekey(seed []byte, indexes []uint32) ([]byte, error) {
+	n := btcec.S256().N
+
+	mac := hmac.New(sha512.New, hdMasterKey)
+	_, _ = mac.Write(seed)
+	i := mac.Sum(nil)
+
+	k, chaincode := i[:32], i[32:]
+
+	if m := new(big.Int).SetBytes(k); m.Sign() == 0 || m.Cmp(n) >= 0 {
+		return nil, errors.Errorf("invalid master key")
+	}
+
+	for _, index := range indexes {
+		var data []byte
+
+		if index >= hdHardenedIndex {
+			data = append([]byte{0x00}, k...)
+		} else {
+			priv, _ := btcec.PrivKeyFromBytes(k)
+			data = priv.PubKey().SerializeCompressed()
+		}
+
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chaincode)
+		_, _ = mac.Write(data)
+		i := mac.Sum(nil)
+
+		il := new(big.Int).SetBytes(i[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, errors.Errorf("invalid child key; index=%d", index)
+		}
+
+		child := il.Add(il, new(big.Int).SetBytes(k))
+		child.Mod(child, n)
+
+		if child.Sign() == 0 {
+			return nil, errors.Errorf("invalid child key; index=%d", index)
+		}
+
+		k = child.FillBytes(make([]byte, 32))
+		chaincode = i[32:]
+	}
+
+	return k, nil
+}
@@ -0,0 +1,120 @@
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NOTE test vectors of BIP-32,
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors
+var testHDVectors = []struct {
+	seed string
+	keys map[string]string // NOTE path: privatekey
+}{
+	{
+		seed: "000102030405060708090a0b0c0d0e0f",
+		keys: map[string]string{
+			"m":                      "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+			"m/0'":                   "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+			"m/0'/1":                 "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+			"m/0'/1/2'":              "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+			"m/0'/1/2'/2":            "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+			"m/0'/1/2'/2/1000000000": "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+		},
+	},
+	{
+		seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		keys: map[string]string{
+			"m":                               "4b03d6fc340455b363f51020ad3ecca4f0850280cf436c70c727923f6db46c3e",
+			"m/0":                             "abe74a98f6c7eabee0428f53798f0ab8aa1bd37873999041703c742f15ac7e1e",
+			"m/0/2147483647'":                 "877c779ad9687164e9c2f4f0f4ff0340814392330693ce95a58fe18fd52e6e93",
+			"m/0/2147483647'/1":               "704addf544a06e5ee4bea37098463c23613da32020d604506da8c0518e1da4b7",
+			"m/0/2147483647'/1/2147483646'":   "f1c7c871a54a804afe328b4c83a1c33b8e5ff48f5087273f04efa83b247d6a2d",
+			"m/0/2147483647'/1/2147483646'/2": "bb7d39bdb83ecf58f2fd82b6d918341cbef428661ef01ab97c28a4842125ac23",
+		},
+	},
+	{
+		// NOTE retention of leading zeros
+		seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		keys: map[string]string{
+			"m":    "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32",
+			"m/0'": "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef",
+		},
+	},
+}
+
+func TestDeriveHDPrivatekey(t *testing.T) {
+	for _, v := range testHDVectors {
+		seed, err := hex.DecodeString(v.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for path, expected := range v.keys {
+			indexes, err := ParseHDPath(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			k, err := deriveHDPrivatekey(seed, indexes)
+			if err != nil {
+				t.Fatalf("derive %q: %v", path, err)
+			}
+
+			if s := hex.EncodeToString(k); s != expected {
+				t.Fatalf("derive %q: expected %s, but %s", path, expected, s)
+			}
+		}
+	}
+}
+
+func TestNewMEPrivatekeyFromMnemonic(t *testing.T) {
+	// NOTE the first account of the default hardhat and anvil mnemonic
+	priv, err := NewMEPrivatekeyFromMnemonic(
+		"test test test test test test test test test test test junk", "", DefaultHDPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := hex.EncodeToString(crypto.FromECDSA(priv.ECDSA())); s != "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" {
+		t.Fatalf("unexpected privatekey, %s", s)
+	}
+
+	if s := crypto.PubkeyToAddress(priv.ECDSA().PublicKey).Hex(); s != "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266" {
+		t.Fatalf("unexpected ethereum address, %s", s)
+	}
+
+	key, err := NewBaseAccountKey(priv.Publickey(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewBaseAccountKeys([]AccountKey{key}, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ad, err := NewAddressFromKeys(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := ad.String(); s != "0xCE7B13a9Dbe85a6B0BbB84976a70794e74b2C5A6fca" {
+		t.Fatalf("unexpected address, %s", s)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, path := range []string{"", "44'/60'", "m/44''", "m/-1", "m/2147483648", "m/a"} {
+			if _, err := ParseHDPath(path); err == nil {
+				t.Fatalf("expected error for path, %q", path)
+			}
+		}
+
+		if _, err := NewMEPrivatekeyFromMnemonic(
+			"test test test test test test test test test test test test", "", DefaultHDPath); err == nil {
+			t.Fatal("expected error for invalid mnemonic")
+		}
+	})
+}
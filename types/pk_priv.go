@@ -121,6 +121,10 @@ func (k MEPrivatekey) Publickey() base.Publickey {
 	return k.pub
 }
 
+func (k MEPrivatekey) ECDSA() *ecdsa.PrivateKey {
+	return k.priv
+}
+
 func (k MEPrivatekey) Equal(b base.PKKey) bool {
 	switch {
 	case b == nil:
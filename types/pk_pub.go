@@ -86,6 +86,10 @@ func (k MEPublickey) IsValid([]byte) error {
 	return nil
 }
 
+func (k MEPublickey) ECDSA() *ecdsa.PublicKey {
+	return k.k
+}
+
 func (k MEPublickey) Equal(b base.PKKey) bool {
 	switch {
 	case b == nil:
@@ -0,0 +1,17 @@
+/*
+Package signer provides the Signer, which signs without loading the privatekey
+in the process, like the hardware security module.
+
+The ExternalSigner talks to the external signing process over the local
+socket. The connection is kept and the requests are sent one by one; request
+and response are the newline terminated JSON.
+
+	{"method": "publickey"}
+	{"method": "sign", "publickey": "<publickey>", "body": "<base64 bytes>"}
+
+	{"publickey": "<publickey>", "signature": "<base64 bytes>", "error": "<error message>"}
+
+Server serves any Signer by the same protocol, so it can be used to build the
+signing process; with MockSigner, it works as the mock signing process.
+*/
+package signer
@@ -0,0 +1,243 @@
+package signer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	"github.com/pkg/errors"
+)
+
+const (
+	MethodPublickey = "publickey"
+	MethodSign      = "sign"
+)
+
+var (
+	// NOTE the ballots are signed by the signer, so the timeout should be
+	// much shorter than the consensus interval.
+	DefaultTimeout       = time.Second * 3
+	MaxMessageSize int64 = 1 << 20
+)
+
+// Request is the request to the signing process.
+type Request struct {
+	Method    string `json:"method"`
+	Publickey string `json:"publickey,omitempty"`
+	Body      []byte `json:"body,omitempty"`
+}
+
+// Response is the response of the signing process; Error is not empty when
+// the request failed.
+type Response struct {
+	Publickey string `json:"publickey,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ExternalSigner is the Signer by the external signing process. The address is
+// the unix socket path, "unix://<path>" or "tcp://<loopback host:port>". The
+// connection is kept and the requests are sent one by one; the broken
+// connection is dialed again.
+type ExternalSigner struct {
+	pub     base.Publickey
+	conn    net.Conn
+	br      *bufio.Reader
+	network string
+	address string
+	timeout time.Duration
+	sync.Mutex
+}
+
+// NewExternalSigner connects to the signing process and loads the publickey.
+func NewExternalSigner(address string, timeout time.Duration, enc encoder.Encoder) (*ExternalSigner, error) {
+	e := util.StringError("external signer")
+
+	network, addr, err := parseAddress(address)
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	if timeout < 1 {
+		timeout = DefaultTimeout
+	}
+
+	s := &ExternalSigner{network: network, address: addr, timeout: timeout}
+
+	res, err := s.request(Request{Method: MethodPublickey})
+	if err != nil {
+		return nil, e.Wrap(err)
+	}
+
+	pub, err := base.DecodePublickeyFromString(res.Publickey, enc)
+	if err != nil {
+		return nil, e.WithMessage(err, "invalid publickey")
+	}
+
+	s.pub = pub
+
+	return s, nil
+}
+
+func (s *ExternalSigner) Publickey() base.Publickey {
+	return s.pub
+}
+
+// Close closes the connection to the signing process.
+func (s *ExternalSigner) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.closeConn()
+}
+
+// Sign requests the signature to the signing process; the signature is
+// verified by the publickey before returned.
+func (s *ExternalSigner) Sign(b []byte) (base.Signature, error) {
+	res, err := s.request(Request{Method: MethodSign, Publickey: s.pub.String(), Body: b})
+	if err != nil {
+		return nil, err
+	}
+
+	sig := base.Signature(res.Signature)
+
+	if err := s.pub.Verify(b, sig); err != nil {
+		return nil, errors.WithMessage(err, "signature from signer")
+	}
+
+	return sig, nil
+}
+
+func (s *ExternalSigner) request(req Request) (Response, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	// NOTE the kept connection may be closed by the signing process; retry
+	// once with new connection.
+	reused := s.conn != nil
+
+	res, err := s.roundtrip(req)
+	if err != nil && reused {
+		res, err = s.roundtrip(req)
+	}
+
+	switch {
+	case err != nil:
+		return Response{}, err
+	case len(res.Error) > 0:
+		return Response{}, errors.Errorf("signer: %s", res.Error)
+	default:
+		return res, nil
+	}
+}
+
+func (s *ExternalSigner) roundtrip(req Request) (Response, error) {
+	if s.conn == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+
+		conn, err := (&net.Dialer{}).DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return Response{}, errors.WithStack(err)
+		}
+
+		s.conn = conn
+		s.br = bufio.NewReader(conn)
+	}
+
+	res, err := func() (Response, error) {
+		if err := s.conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+			return Response{}, errors.WithStack(err)
+		}
+
+		if err := writeMessage(s.conn, req); err != nil {
+			return Response{}, err
+		}
+
+		var res Response
+
+		return res, readMessage(s.br, &res)
+	}()
+	if err != nil {
+		_ = s.closeConn()
+
+		return Response{}, err
+	}
+
+	return res, nil
+}
+
+func (s *ExternalSigner) closeConn() error {
+	if s.conn == nil {
+		return nil
+	}
+
+	err := s.conn.Close()
+
+	s.conn = nil
+	s.br = nil
+
+	return errors.WithStack(err)
+}
+
+func parseAddress(s string) (network, address string, _ error) {
+	switch {
+	case strings.HasPrefix(s, "tcp://"):
+		address = strings.TrimPrefix(s, "tcp://")
+
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return "", "", errors.WithStack(err)
+		}
+
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return "", "", errors.Errorf("not local address, %q", host)
+		}
+
+		return "tcp", address, nil
+	default:
+		address = strings.TrimPrefix(s, "unix://")
+		if len(address) < 1 {
+			return "", "", errors.Errorf("empty signer address")
+		}
+
+		return "unix", address, nil
+	}
+}
+
+func writeMessage(w io.Writer, v interface{}) error {
+	b, err := util.MarshalJSON(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+
+	return errors.WithStack(err)
+}
+
+func readMessage(r *bufio.Reader, v interface{}) error {
+	var b []byte
+
+	for {
+		i, err := r.ReadSlice('\n')
+		b = append(b, i...)
+
+		switch {
+		case int64(len(b)) > MaxMessageSize:
+			return errors.Errorf("too large message")
+		case err == nil:
+			return util.UnmarshalJSON(b, v)
+		case errors.Is(err, bufio.ErrBufferFull):
+		default:
+			return errors.WithStack(err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package signer_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtoconNet/mitum-currency/v3/types"
+	"github.com/ProtoconNet/mitum-currency/v3/types/signer"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/ProtoconNet/mitum2/util/encoder"
+	jsonenc "github.com/ProtoconNet/mitum2/util/encoder/json"
+	"github.com/pkg/errors"
+)
+
+// startTestServer serves the signer at the unix socket; the server is stopped
+// by the returned function.
+func startTestServer(t *testing.T, f string, s signer.Signer) func() {
+	t.Helper()
+
+	l, err := net.Listen("unix", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	donech := make(chan struct{})
+
+	go func() {
+		defer close(donech)
+
+		_ = signer.NewServer(s, time.Second).Serve(ctx, l)
+	}()
+
+	stop := func() {
+		cancel()
+		<-donech
+	}
+
+	t.Cleanup(stop)
+
+	return stop
+}
+
+func newTestSocketPath(t *testing.T) string {
+	t.Helper()
+
+	// NOTE the path of unix socket is limited about 100 bytes, so t.TempDir()
+	// can be too long.
+	dir, err := os.MkdirTemp("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	return filepath.Join(dir, "s.sock")
+}
+
+func TestExternalSigner(t *testing.T) {
+	enc := jsonenc.NewEncoder()
+	if err := enc.Add(encoder.DecodeDetail{Hint: types.MEPublickeyHint, Instance: types.MEPublickey{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	priv := types.NewMEPrivatekey()
+	mock := signer.NewMockSigner(priv)
+
+	f := newTestSocketPath(t)
+	stop := startTestServer(t, f, mock)
+
+	s, err := signer.NewExternalSigner("unix://"+f, time.Second, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = s.Close()
+	}()
+
+	if !s.Publickey().Equal(priv.Publickey()) {
+		t.Fatal("publickey not matched")
+	}
+
+	sign := func(t *testing.T) []byte {
+		t.Helper()
+
+		b := util.UUID().Bytes()
+
+		sig, err := s.Sign(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := priv.Publickey().Verify(b, sig); err != nil {
+			t.Fatal(err)
+		}
+
+		return b
+	}
+
+	t.Run("sign", func(t *testing.T) {
+		b := sign(t)
+
+		signed := mock.Signed()
+		if len(signed) < 1 || string(signed[len(signed)-1]) != string(b) {
+			t.Fatal("signed body not matched")
+		}
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		mock.SetError(errors.Errorf("hsm locked"))
+
+		if _, err := s.Sign(util.UUID().Bytes()); err == nil {
+			t.Fatal("expected error")
+		} else if !strings.Contains(err.Error(), "hsm locked") {
+			t.Fatalf("unexpected error, %v", err)
+		}
+
+		mock.SetError(nil)
+
+		_ = sign(t)
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		stop()
+
+		if _, err := s.Sign(util.UUID().Bytes()); err == nil {
+			t.Fatal("expected error without server")
+		}
+
+		stop = startTestServer(t, f, mock)
+
+		_ = sign(t)
+
+		// NOTE the kept connection is closed by the server.
+		stop()
+		stop = startTestServer(t, f, mock)
+
+		_ = sign(t)
+	})
+
+	t.Run("unknown publickey", func(t *testing.T) {
+		stop()
+
+		// NOTE the signer of the other key is served at the same socket.
+		_ = startTestServer(t, f, signer.NewMockSigner(types.NewMEPrivatekey()))
+
+		if _, err := s.Sign(util.UUID().Bytes()); err == nil {
+			t.Fatal("expected error")
+		} else if !strings.Contains(err.Error(), "unknown publickey") {
+			t.Fatalf("unexpected error, %v", err)
+		}
+	})
+}
+
+func TestNewExternalSignerInvalidAddress(t *testing.T) {
+	enc := jsonenc.NewEncoder()
+
+	for _, address := range []string{
+		"unix://",
+		"tcp://8.8.8.8:1234",
+		"tcp://localhost",
+	} {
+		if _, err := signer.NewExternalSigner(address, time.Second, enc); err == nil {
+			t.Fatalf("expected error for %q", address)
+		}
+	}
+
+	if _, err := signer.NewExternalSigner("unix://"+newTestSocketPath(t), time.Second, enc); err == nil {
+		t.Fatal("expected error without server")
+	}
+}
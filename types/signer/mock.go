@@ -0,0 +1,56 @@
+package signer
+
+import (
+	"sync"
+
+	"github.com/ProtoconNet/mitum2/base"
+)
+
+// MockSigner signs by the privatekey in memory and keeps the signed bytes. It
+// is for tests; with Server, it works as the mock signing process.
+type MockSigner struct {
+	priv   base.Privatekey
+	err    error
+	signed [][]byte
+	sync.RWMutex
+}
+
+func NewMockSigner(priv base.Privatekey) *MockSigner {
+	return &MockSigner{priv: priv}
+}
+
+func (s *MockSigner) Publickey() base.Publickey {
+	return s.priv.Publickey()
+}
+
+func (s *MockSigner) Sign(b []byte) (base.Signature, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	s.signed = append(s.signed, b)
+
+	return s.priv.Sign(b)
+}
+
+// SetError makes Sign fail with the error; nil error resets.
+func (s *MockSigner) SetError(err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.err = err
+}
+
+// Signed returns the bytes signed so far.
+func (s *MockSigner) Signed() [][]byte {
+	s.RLock()
+	defer s.RUnlock()
+
+	signed := make([][]byte, len(s.signed))
+	copy(signed, s.signed)
+
+	return signed
+}
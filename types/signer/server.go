@@ -0,0 +1,117 @@
+package signer
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Server serves the Signer by the protocol of ExternalSigner.
+type Server struct {
+	signer  Signer
+	timeout time.Duration
+}
+
+func NewServer(signer Signer, timeout time.Duration) *Server {
+	if timeout < 1 {
+		timeout = DefaultTimeout
+	}
+
+	return &Server{signer: signer, timeout: timeout}
+}
+
+// Serve accepts the connections until the context is canceled or the listener
+// is closed.
+func (srv *Server) Serve(ctx context.Context, l net.Listener) error {
+	go func() {
+		<-ctx.Done()
+
+		_ = l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return errors.WithStack(err)
+		}
+
+		go srv.handle(ctx, conn)
+	}
+}
+
+// handle serves the requests of the connection until the connection is closed;
+// the timeout is applied to write the response.
+func (srv *Server) handle(ctx context.Context, conn net.Conn) {
+	donech := make(chan struct{})
+
+	defer func() {
+		close(donech)
+
+		_ = conn.Close()
+	}()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-donech:
+		}
+	}()
+
+	br := bufio.NewReader(conn)
+
+	for {
+		var req Request
+		var res Response
+
+		// NOTE after the wrong message, the connection is closed.
+		err := readMessage(br, &req)
+
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, net.ErrClosed):
+			return
+		case err != nil:
+			res = Response{Error: err.Error()}
+		default:
+			res = srv.response(req)
+		}
+
+		if werr := conn.SetWriteDeadline(time.Now().Add(srv.timeout)); werr != nil {
+			return
+		}
+
+		if werr := writeMessage(conn, res); werr != nil || err != nil {
+			return
+		}
+	}
+}
+
+func (srv *Server) response(req Request) Response {
+	pub := srv.signer.Publickey()
+
+	switch req.Method {
+	case MethodPublickey:
+		return Response{Publickey: pub.String()}
+	case MethodSign:
+		if req.Publickey != pub.String() {
+			return Response{Error: "unknown publickey"}
+		}
+
+		sig, err := srv.signer.Sign(req.Body)
+		if err != nil {
+			return Response{Error: err.Error()}
+		}
+
+		return Response{Publickey: pub.String(), Signature: sig}
+	default:
+		return Response{Error: "unknown method"}
+	}
+}
@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"fmt"
+
+	"github.com/ProtoconNet/mitum2/base"
+	"github.com/ProtoconNet/mitum2/util"
+	"github.com/pkg/errors"
+)
+
+// Signer signs the bytes by the privatekey of the publickey; base.Privatekey is
+// also Signer.
+type Signer interface {
+	Publickey() base.Publickey
+	Sign([]byte) (base.Signature, error)
+}
+
+// Privatekey is the base.Privatekey by Signer, so the Signer can be used
+// wherever base.Privatekey is used, like base.NewBaseNodeSignFromFact. The
+// privatekey itself is not exposed; String() returns the publickey with
+// "signer:" prefix.
+type Privatekey struct {
+	signer Signer
+	s      string
+}
+
+func NewPrivatekey(signer Signer) Privatekey {
+	k := Privatekey{signer: signer}
+
+	if signer != nil && signer.Publickey() != nil {
+		k.s = fmt.Sprintf("signer:%s", signer.Publickey().String())
+	}
+
+	return k
+}
+
+func (k Privatekey) String() string {
+	return k.s
+}
+
+func (k Privatekey) Bytes() []byte {
+	return []byte(k.s)
+}
+
+func (k Privatekey) IsValid([]byte) error {
+	switch {
+	case k.signer == nil:
+		return util.ErrInvalid.Errorf("empty signer")
+	case k.signer.Publickey() == nil:
+		return util.ErrInvalid.Errorf("empty signer publickey")
+	}
+
+	if err := k.signer.Publickey().IsValid(nil); err != nil {
+		return util.ErrInvalid.WithMessage(err, "invalid signer publickey")
+	}
+
+	return nil
+}
+
+func (k Privatekey) Publickey() base.Publickey {
+	return k.signer.Publickey()
+}
+
+func (k Privatekey) Equal(b base.PKKey) bool {
+	switch {
+	case b == nil:
+		return false
+	default:
+		return k.s == b.String()
+	}
+}
+
+func (k Privatekey) Sign(b []byte) (base.Signature, error) {
+	sig, err := k.signer.Sign(b)
+	if err != nil {
+		return nil, errors.WithMessage(err, "sign by signer")
+	}
+
+	return sig, nil
+}
+
+func (k Privatekey) MarshalText() ([]byte, error) {
+	return []byte(k.s), nil
+}
+
+func (k Privatekey) Signer() Signer {
+	return k.signer
+}